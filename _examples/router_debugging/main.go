@@ -11,8 +11,6 @@ package main
 import (
 	"fmt"
 	"net/http"
-	"reflect"
-	"runtime"
 
 	r "github.com/carlos-el/simplerouter"
 )
@@ -64,9 +62,9 @@ func main() {
 		if route.Handler != nil {
 			fmt.Println(path + route.Path + " " + route.Method)
 			for _, mw := range append(middlewares, route.Middlewares...) {
-				fmt.Println("\t" + runtime.FuncForPC(reflect.ValueOf(mw).Pointer()).Name())
+				fmt.Println("\t" + r.FuncName(mw))
 			}
-			fmt.Println("\t" + runtime.FuncForPC(reflect.ValueOf(route.Handler).Pointer()).Name())
+			fmt.Println("\t" + r.FuncName(route.Handler))
 		}
 	}
 
@@ -79,7 +77,7 @@ func main() {
 		r.NewRoute("/bar").Use(barMiddleware).Add(
 			r.Post(postBarHandler),
 		),
-	).MountAndWalk(walker)
+	).Mount(r.WithWalk(walker))
 	// Console output:
 	// /api/foo GET
 	// 	main.generalMiddleware
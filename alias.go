@@ -0,0 +1,21 @@
+package simplerouter
+
+// Alias adds alternative paths for the route. Once mounted, the route and its child routes
+// are also registered under each alias, dispatching to the same handlers and middlewares.
+// Aliases are reported together with the main path by [Route.Endpoints] and the route is walked only once.
+func (r *Route) Alias(paths ...string) *Route {
+	r.Aliases = append(r.Aliases, paths...)
+	return r
+}
+
+// chainPaths returns the full paths of the route for each of the full paths of its parent.
+// The paths built from the main path come first, followed by the ones built from the aliases.
+func (r *Route) chainPaths(parentPaths []string) []string {
+	chainedPaths := make([]string, 0, len(parentPaths)*(len(r.Aliases)+1))
+	for _, path := range append([]string{r.Path}, r.Aliases...) {
+		for _, parentPath := range parentPaths {
+			chainedPaths = append(chainedPaths, parentPath+path)
+		}
+	}
+	return chainedPaths
+}
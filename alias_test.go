@@ -0,0 +1,73 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestAlias tests that aliased routes dispatch to the same handlers and middlewares
+func TestAlias(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedMws    []string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "main path", path: "/api/users/1", expectedMws: []string{"mw1", "mw2"}, expectedStatus: http.StatusOK, expectedBody: "user"},
+		{name: "alias path", path: "/api/members/1", expectedMws: []string{"mw1", "mw2"}, expectedStatus: http.StatusOK, expectedBody: "user"},
+		{name: "alias of parent and child", path: "/v1/people/1", expectedMws: []string{"mw1", "mw2"}, expectedStatus: http.StatusOK, expectedBody: "user"},
+		{name: "method route alias", path: "/api/users/1/profile", expectedMws: []string{"mw1", "mw2"}, expectedStatus: http.StatusOK, expectedBody: "user"},
+		{name: "not an alias", path: "/v1/users/1/x", expectedMws: []string{}, expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mwTrackerSlice := []string{}
+			mux := r.NewRoute("/api").Alias("/v1").Use(middlewareTracker("mw1", &mwTrackerSlice)).Add(
+				r.NewRoute("/users/{id}").Alias("/members/{id}", "/people/{id}").Use(middlewareTracker("mw2", &mwTrackerSlice)).Add(
+					r.Get(handlerWriter("user")).Alias("/profile"),
+				),
+			).Mount()
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			if !reflect.DeepEqual(mwTrackerSlice, tt.expectedMws) {
+				t.Errorf("Middlewares executed = %v, want %v", mwTrackerSlice, tt.expectedMws)
+			}
+		})
+	}
+}
+
+// TestAliasGrouping tests that aliases are reported with the main path in endpoints and walks
+func TestAliasGrouping(t *testing.T) {
+	route := r.NewRoute("/api").Alias("/v1").Add(
+		r.NewRoute("/users").Alias("/members").Add(r.Get(handlerWriter("users"))),
+	)
+
+	endpoints := route.Endpoints()
+	if len(endpoints) != 1 {
+		t.Fatalf("Endpoints() returned %d endpoints, want 1", len(endpoints))
+	}
+	assertCorrect(t, endpoints[0].Path, "/api/users")
+	wantAliases := []string{"/v1/users", "/api/members", "/v1/members"}
+	if !reflect.DeepEqual(endpoints[0].Aliases, wantAliases) {
+		t.Errorf("Aliases = %v, want %v", endpoints[0].Aliases, wantAliases)
+	}
+
+	walked := []string{}
+	route.MountAndWalk(func(route *r.Route, path string, middlewares []r.Middleware) {
+		walked = append(walked, path+route.Path)
+	})
+	wantWalked := []string{"/api", "/api/users", "/api/users"}
+	if !reflect.DeepEqual(walked, wantWalked) {
+		t.Errorf("Walked = %v, want %v", walked, wantWalked)
+	}
+}
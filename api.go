@@ -0,0 +1,120 @@
+package simplerouter
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// APIOptions configures the routes created by [NewAPI]. The zero value is ready to use.
+type APIOptions struct {
+	// Version is the path prefix of the API routes, like "/v1". If empty, they are served from the root.
+	Version string
+	// HealthPath is the path of the health endpoint, "/healthz" if empty.
+	HealthPath string
+	// Stack is the middleware preset applied to every route, [DefaultAPIStack] if nil.
+	// Its Recover middleware is replaced so panics are answered by the ErrorHandler.
+	Stack *APIStack
+	// ErrorHandler writes the responses of unknown routes, disallowed methods and panics.
+	// If nil, [JSONError] is used.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, status int)
+	// MountOptions are the options the root route is mounted with, like [WithBasePath], which must be
+	// given to its Mount too, so the routes of other methods are found as they are served.
+	MountOptions []MountOption
+}
+
+// NewAPI returns the root route of a production ready API service and the route where its endpoints
+// are added. The root route applies the middleware stack, serves the health endpoint, and answers
+// unknown routes and disallowed methods through the error handler, while the API route is
+// the versioned prefix for the endpoints:
+//
+//	root, api := simplerouter.NewAPI(simplerouter.APIOptions{Version: "/v1"})
+//	api.Add(simplerouter.NewRoute("/users").Add(simplerouter.Get(listUsers)))
+//	http.ListenAndServe(":8080", root.Mount())
+//
+// The health endpoint reports the optional routes that failed to initialize, see [Route.Optional].
+// The root route registers the "/" catch-all pattern, so no other route can register it.
+func NewAPI(opts APIOptions) (root *Route, api *Route) {
+	if opts.HealthPath == "" {
+		opts.HealthPath = "/healthz"
+	}
+	if opts.ErrorHandler == nil {
+		opts.ErrorHandler = JSONError
+	}
+	stack := DefaultAPIStack()
+	if opts.Stack != nil {
+		stack = *opts.Stack
+	}
+	if stack.Recover != nil {
+		stack.Recover = middleware.RecoverWith(func(w http.ResponseWriter, r *http.Request, v any) {
+			opts.ErrorHandler(w, r, http.StatusInternalServerError)
+		})
+	}
+
+	api = NewRoute(opts.Version)
+	root = NewRoute("").Use(stack.Middlewares()...)
+
+	// The catch-all route receives both unknown paths and disallowed methods,
+	// the mounted tree tells them apart by looking for handlers of other methods.
+	mux := sync.OnceValue(func() *http.ServeMux { return root.Mount(opts.MountOptions...) })
+	root.Add(
+		NewRoute(opts.HealthPath).NoIndex().Add(Get(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			degraded := root.Degraded()
+			if len(degraded) == 0 {
+				w.Write([]byte(`{"status":"ok"}`))
+				return
+			}
+
+			// The service is still alive, so the status code doesn't change.
+			errs := map[string]string{}
+			for path, err := range degraded {
+				errs[path] = err.Error()
+			}
+			json.NewEncoder(w).Encode(map[string]any{"status": "degraded", "degraded": errs})
+		})),
+		api,
+		NewRoute("/").Add(All(func(w http.ResponseWriter, r *http.Request) {
+			allowed := allowedMethods(mux(), r, PatternFrom(r.Context()))
+			if len(allowed) == 0 {
+				opts.ErrorHandler(w, r, http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			opts.ErrorHandler(w, r, http.StatusMethodNotAllowed)
+		})),
+	)
+
+	return root, api
+}
+
+// JSONError writes an error response with the given status and a JSON object
+// holding the status text under the "error" key, e.g. {"error":"Not Found"}.
+func JSONError(w http.ResponseWriter, r *http.Request, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": http.StatusText(status)})
+}
+
+// allowedMethods returns the methods with a handler registered in mux for the request path,
+// not counting the catch-all pattern.
+func allowedMethods(mux *http.ServeMux, r *http.Request, catchAll string) []string {
+	methods := []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+	}
+
+	allowed := []string{}
+	for _, method := range methods {
+		req := r.Clone(r.Context())
+		req.Method = method
+		if _, pattern := mux.Handler(req); pattern != "" && strings.TrimSpace(pattern) != catchAll {
+			allowed = append(allowed, method)
+		}
+	}
+	return allowed
+}
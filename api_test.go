@@ -0,0 +1,115 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestNewAPI tests the routes created by NewAPI
+func TestNewAPI(t *testing.T) {
+	root, api := r.NewAPI(r.APIOptions{Version: "/v1"})
+	api.Add(
+		r.NewRoute("/users").Add(r.Get(handlerWriter("users")), r.Post(handlerWriter("create user"))),
+		r.NewRoute("/panic").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+			panic("boom")
+		})),
+	)
+	mux := root.Mount()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedAllow  string
+	}{
+		{name: "api route", method: "GET", path: "/v1/users", expectedStatus: http.StatusOK, expectedBody: "users"},
+		{name: "health", method: "GET", path: "/healthz", expectedStatus: http.StatusOK, expectedBody: `{"status":"ok"}`},
+		{name: "not found", method: "GET", path: "/v1/unknown", expectedStatus: http.StatusNotFound, expectedBody: "{\"error\":\"Not Found\"}\n"},
+		{name: "unversioned route", method: "GET", path: "/users", expectedStatus: http.StatusNotFound, expectedBody: "{\"error\":\"Not Found\"}\n"},
+		{name: "method not allowed", method: "DELETE", path: "/v1/users", expectedStatus: http.StatusMethodNotAllowed, expectedBody: "{\"error\":\"Method Not Allowed\"}\n", expectedAllow: "GET, HEAD, POST"},
+		{name: "panic", method: "GET", path: "/v1/panic", expectedStatus: http.StatusInternalServerError, expectedBody: "{\"error\":\"Internal Server Error\"}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, w.Header().Get("Allow"), tt.expectedAllow)
+			assertCorrect(t, len(w.Header().Get("X-Request-ID")), 32)
+		})
+	}
+}
+
+// TestNewAPIWithBasePath tests the error responses of an API mounted under a base path
+func TestNewAPIWithBasePath(t *testing.T) {
+	root, api := r.NewAPI(r.APIOptions{Version: "/v1", MountOptions: []r.MountOption{r.WithBasePath("/app")}})
+	api.Add(r.NewRoute("/users").Add(r.Get(handlerWriter("users"))))
+	mux := root.Mount(r.WithBasePath("/app"))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedAllow  string
+	}{
+		{name: "api route", method: "GET", path: "/app/v1/users", expectedStatus: http.StatusOK},
+		{name: "method not allowed", method: "POST", path: "/app/v1/users", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD"},
+		{name: "not found", method: "GET", path: "/app/v1/unknown", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Header().Get("Allow"), tt.expectedAllow)
+		})
+	}
+}
+
+// TestNewAPIWithOptions tests the options of NewAPI
+func TestNewAPIWithOptions(t *testing.T) {
+	tracker := []string{}
+	stack := r.APIStack{Logger: middlewareTracker("logger", &tracker)}
+	root, api := r.NewAPI(r.APIOptions{
+		HealthPath: "/health",
+		Stack:      &stack,
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, status int) {
+			http.Error(w, "custom error", status)
+		},
+	})
+	api.Add(r.NewRoute("/users").Add(r.Get(handlerWriter("users"))))
+	mux := root.Mount()
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{path: "/users", expectedStatus: http.StatusOK, expectedBody: "users"},
+		{path: "/health", expectedStatus: http.StatusOK, expectedBody: `{"status":"ok"}`},
+		{path: "/healthz", expectedStatus: http.StatusNotFound, expectedBody: "custom error\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			tracker = []string{}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, len(tracker), 1)
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package simplerouter
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/carlos-el/simplerouter/har"
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// AuditEvent records a request served by a route using the [Audit] middleware.
+type AuditEvent struct {
+	// Time is when the request was received.
+	Time time.Time
+	// Principal identifies who sent the request, empty if unknown, see [AuditPrincipal].
+	Principal string
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the path of the URL of the request.
+	Path string
+	// Route is the name of the endpoint serving the request, see [RouteInfo].
+	Route string
+	// Pattern is the path pattern of the endpoint serving the request, like "/admin/users/{id}".
+	Pattern string
+	// Params maps the wildcards of the pattern to their values in the request.
+	Params map[string]string
+	// Query holds the query parameters of the request.
+	Query url.Values
+	// Status is the status code of the response, the outcome of the request.
+	// Requests whose handler panicked are recorded with a 500 Internal Server Error.
+	Status int
+	// Duration is the time taken to serve the request.
+	Duration time.Duration
+	// RequestID is the ID of the request set by [middleware.RequestID], if any.
+	RequestID string
+	// RemoteAddr is the network address of the client.
+	RemoteAddr string
+}
+
+// AuditSink receives the events of the [Audit] middleware, to write them to an audit trail like a log
+// file, a database or a SIEM. Implementations must be safe for concurrent use. Events are delivered
+// once the request is served, before the response is completed, so slow sinks should buffer them.
+type AuditSink interface {
+	// Audit records the event of the request, whose context is ctx.
+	Audit(ctx context.Context, event AuditEvent)
+}
+
+// AuditSinkFunc is an [AuditSink] calling the function.
+type AuditSinkFunc func(ctx context.Context, event AuditEvent)
+
+// Audit calls f(ctx, event).
+func (f AuditSinkFunc) Audit(ctx context.Context, event AuditEvent) {
+	f(ctx, event)
+}
+
+// SlogAuditSink returns an [AuditSink] logging the events at the info level with the "audit" message.
+// If logger is nil, slog.Default is used.
+func SlogAuditSink(logger *slog.Logger) AuditSink {
+	return AuditSinkFunc(func(ctx context.Context, event AuditEvent) {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+		l.InfoContext(ctx, "audit",
+			"principal", event.Principal,
+			"method", event.Method,
+			"path", event.Path,
+			"route", event.Route,
+			"pattern", event.Pattern,
+			"params", event.Params,
+			"query", event.Query,
+			"status", event.Status,
+			"duration", event.Duration,
+			"request_id", event.RequestID,
+			"remote_addr", event.RemoteAddr,
+		)
+	})
+}
+
+// AuditOption configures the [Audit] middleware.
+type AuditOption func(*auditConfig)
+
+// auditConfig holds the configuration of the [Audit] middleware.
+type auditConfig struct {
+	principal func(r *http.Request) string
+	redact    map[string]bool
+}
+
+// AuditPrincipal sets the function identifying who sent the requests. By default, the principal is the
+// subject of the claims of [middleware.JWT], or the common name of the certificate of [middleware.ClientCert].
+func AuditPrincipal(fn func(r *http.Request) string) AuditOption {
+	if fn == nil {
+		panic("fn parameter cannot be nil")
+	}
+	return func(c *auditConfig) { c.principal = fn }
+}
+
+// AuditRedact replaces the values of the path wildcards and query parameters with the given names,
+// compared case-insensitively, with "[REDACTED]" in the events, so secrets and personal data like
+// tokens or emails are not written to the audit trail. The segments of the path of the events matched
+// by the wildcards are redacted too.
+func AuditRedact(names ...string) AuditOption {
+	return func(c *auditConfig) {
+		for _, name := range names {
+			c.redact[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// Audit returns a Middleware sending an [AuditEvent] to sink for each request served, recording who
+// called which endpoint with which parameters and its outcome, so regulated services can keep an audit
+// trail of the subtrees that need it:
+//
+//	router.Add(simplerouter.NewRoute("/admin").Use(
+//		middleware.JWT(keys),
+//		simplerouter.Audit(simplerouter.SlogAuditSink(auditLogger), simplerouter.AuditRedact("token")),
+//	).Add(...))
+//
+// Use it after the middlewares authenticating the requests, so their principal is known. The requests
+// rejected by them are not recorded unless Audit is used before them, with an empty principal.
+func Audit(sink AuditSink, opts ...AuditOption) Middleware {
+	if sink == nil {
+		panic("sink parameter cannot be nil")
+	}
+	config := &auditConfig{principal: defaultPrincipal, redact: map[string]bool{}}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			aw := &auditWriter{ResponseWriter: w}
+
+			panicked := true
+			defer func() {
+				status := aw.status
+				if panicked {
+					status = http.StatusInternalServerError
+				} else if status == 0 {
+					status = http.StatusOK
+				}
+				sink.Audit(r.Context(), config.event(r, start, status))
+			}()
+
+			next.ServeHTTP(aw, r)
+			panicked = false
+		})
+	}
+}
+
+// event returns the audit event of the request, with the redacted fields replaced.
+func (c *auditConfig) event(r *http.Request, start time.Time, status int) AuditEvent {
+	event := AuditEvent{
+		Time:       start,
+		Principal:  c.principal(r),
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Query:      r.URL.Query(),
+		Status:     status,
+		Duration:   time.Since(start),
+		RequestID:  middleware.RequestIDFrom(r.Context()),
+		RemoteAddr: r.RemoteAddr,
+	}
+
+	if info := RouteInfoFrom(r.Context()); info != nil {
+		event.Route, event.Pattern = info.Name, info.Pattern
+		event.Params = map[string]string{}
+		for _, match := range wildcardRegexp.FindAllStringSubmatch(info.Pattern, -1) {
+			event.Params[match[1]] = r.PathValue(match[1])
+		}
+	}
+
+	if event.Pattern != "" {
+		event.Path = c.redactPath(event.Pattern, r.URL.EscapedPath(), event.Params)
+	}
+	for name, value := range event.Params {
+		if c.redact[strings.ToLower(name)] && value != "" {
+			event.Params[name] = har.Redacted
+		}
+	}
+	for name, values := range event.Query {
+		if c.redact[strings.ToLower(name)] {
+			for i := range values {
+				values[i] = har.Redacted
+			}
+		}
+	}
+	return event
+}
+
+// redactPath returns the unescaped path p with the segments matched by the redacted wildcards of the pattern,
+// whose values are params, replaced. The segments matched by a wildcard matching the rest of the path are
+// replaced as one. p is escaped, so its segments are the ones matched by the pattern, like http.ServeMux
+// does, and the values with escaped slashes are replaced whole.
+func (c *auditConfig) redactPath(pattern, p string, params map[string]string) string {
+	if i := strings.Index(pattern, "/"); i > 0 {
+		// Remove the host of the pattern.
+		pattern = pattern[i:]
+	}
+	segments := strings.Split(p, "/")
+	redacted := make([]bool, len(segments))
+	for i, segment := range strings.Split(pattern, "/") {
+		match := wildcardRegexp.FindStringSubmatch(segment)
+		if match == nil || i >= len(segments) || !c.redact[strings.ToLower(match[1])] || params[match[1]] == "" {
+			continue
+		}
+		if match[2] != "" {
+			segments, redacted = segments[:i+1], redacted[:i+1]
+		}
+		redacted[i] = true
+	}
+
+	for i, segment := range segments {
+		if redacted[i] {
+			segments[i] = har.Redacted
+		} else if unescaped, err := url.PathUnescape(segment); err == nil {
+			segments[i] = unescaped
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// defaultPrincipal returns the subject of the JWT claims of the request, or the common name of its client certificate.
+func defaultPrincipal(r *http.Request) string {
+	if claims := middleware.ClaimsFrom(r.Context()); claims != nil && claims.Subject != "" {
+		return claims.Subject
+	}
+	if identity := middleware.ClientIdentityFrom(r.Context()); identity != nil {
+		return identity.CommonName
+	}
+	return ""
+}
+
+// auditWriter wraps an http.ResponseWriter recording the status code of the response.
+type auditWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the final status code before writing it.
+func (w *auditWriter) WriteHeader(code int) {
+	if w.status == 0 && (code < 100 || code > 199) {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write sets the status to 200 if none was written.
+func (w *auditWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for http.ResponseController.
+func (w *auditWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,113 @@
+package simplerouter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestAudit tests the events recorded for the requests of an audited subtree
+func TestAudit(t *testing.T) {
+	var events []r.AuditEvent
+	sink := r.AuditSinkFunc(func(ctx context.Context, event r.AuditEvent) {
+		events = append(events, event)
+	})
+	audit := r.Audit(sink,
+		r.AuditPrincipal(func(req *http.Request) string { return req.Header.Get("X-User") }),
+		r.AuditRedact("token", "Email"),
+	)
+	mux := r.NewRoute("").Add(
+		r.NewRoute("/admin").Use(audit).Add(
+			r.NewRoute("/users/{id}").Name("user").Add(
+				r.Get(handlerWriter("user")),
+				r.Delete(func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusForbidden) }),
+			),
+			r.NewRoute("/invites/{token}").Add(r.Post(handlerWriter("accepted"))),
+		),
+		r.NewRoute("/public").Add(r.Get(handlerWriter("public"))),
+	).Mount()
+
+	serve := func(method, target, user string) {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("X-User", user)
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	serve(http.MethodGet, "/admin/users/42?email=jane@example.com&fields=name", "alice")
+	serve(http.MethodDelete, "/admin/users/42", "bob")
+	serve(http.MethodPost, "/admin/invites/s3cr3t", "")
+	serve(http.MethodGet, "/public", "alice")
+
+	assertCorrect(t, len(events), 3)
+
+	assertCorrect(t, events[0].Principal, "alice")
+	assertCorrect(t, events[0].Method, http.MethodGet)
+	assertCorrect(t, events[0].Path, "/admin/users/42")
+	assertCorrect(t, events[0].Route, "user")
+	assertCorrect(t, events[0].Pattern, "/admin/users/{id}")
+	assertCorrect(t, events[0].Params["id"], "42")
+	assertCorrect(t, events[0].Query.Get("email"), "[REDACTED]")
+	assertCorrect(t, events[0].Query.Get("fields"), "name")
+	assertCorrect(t, events[0].Status, http.StatusOK)
+
+	assertCorrect(t, events[1].Principal, "bob")
+	assertCorrect(t, events[1].Status, http.StatusForbidden)
+
+	assertCorrect(t, events[2].Principal, "")
+	assertCorrect(t, events[2].Path, "/admin/invites/[REDACTED]")
+	assertCorrect(t, events[2].Params["token"], "[REDACTED]")
+	assertCorrect(t, events[2].Status, http.StatusOK)
+}
+
+// TestAuditRedactedPath tests that only the path segments matched by the redacted wildcards are redacted
+func TestAuditRedactedPath(t *testing.T) {
+	var event r.AuditEvent
+	sink := r.AuditSinkFunc(func(ctx context.Context, e r.AuditEvent) { event = e })
+	mux := r.NewRoute("").Use(r.Audit(sink, r.AuditRedact("id", "path"))).Add(
+		r.NewRoute("/v1/users/{id}").Add(r.Get(handlerWriter("user"))),
+		r.NewRoute("/users/{id}/profile").Add(r.Get(handlerWriter("profile"))),
+		r.NewRoute("/v1/teams/{team}/users/{id}").Add(r.Get(handlerWriter("member"))),
+		r.NewRoute("/files/{path...}").Add(r.Get(handlerWriter("file"))),
+	).Mount()
+
+	tests := []struct {
+		target       string
+		expectedPath string
+	}{
+		{target: "/v1/users/1", expectedPath: "/v1/users/[REDACTED]"},
+		{target: "/v1/users/users", expectedPath: "/v1/users/[REDACTED]"},
+		{target: "/v1/teams/1/users/1", expectedPath: "/v1/teams/1/users/[REDACTED]"},
+		{target: "/files/v1/users/1", expectedPath: "/files/[REDACTED]"},
+		{target: "/files/", expectedPath: "/files/"},
+		{target: "/users/a%2Fb/profile", expectedPath: "/users/[REDACTED]/profile"},
+		{target: "/v1/teams/a%2Fb/users/1", expectedPath: "/v1/teams/a/b/users/[REDACTED]"},
+		{target: "/files/a%2Fb/c%20d", expectedPath: "/files/[REDACTED]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assertCorrect(t, event.Path, tt.expectedPath)
+		})
+	}
+}
+
+// TestAuditPanic tests the event recorded for a request whose handler panics
+func TestAuditPanic(t *testing.T) {
+	var event r.AuditEvent
+	sink := r.AuditSinkFunc(func(ctx context.Context, e r.AuditEvent) { event = e })
+	mux := r.NewRoute("/boom").Use(r.Audit(sink)).Add(
+		r.Get(func(w http.ResponseWriter, req *http.Request) { panic("boom") }),
+	).Mount()
+
+	func() {
+		defer func() { recover() }()
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+	}()
+
+	assertCorrect(t, event.Pattern, "/boom")
+	assertCorrect(t, event.Status, http.StatusInternalServerError)
+}
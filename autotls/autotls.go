@@ -0,0 +1,46 @@
+// Package autotls serves HTTPS with certificates obtained and renewed automatically from Let's Encrypt
+// with the simplerouter server helpers. It is a module of its own, so only the applications using it
+// depend on golang.org/x/crypto:
+//
+//	simplerouter.ListenAndServe(ctx, ":443", router.Mount(), autotls.WithDomains("", "example.com", "www.example.com"))
+package autotls
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/carlos-el/simplerouter"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// WithDomains serves HTTPS with certificates for domains obtained and renewed automatically from
+// Let's Encrypt, accepting its terms of service. Certificates and the ACME account key are cached in
+// cacheDir, which should only be readable by the user running the server, so they survive restarts.
+// If cacheDir is empty, the "simplerouter/autocert" directory of the user cache directory is used,
+// see [os.UserCacheDir]. The domains are verified with the TLS-ALPN-01 challenge, so the server must
+// be reachable on port 443 under each of them.
+//
+// It panics if no domain is given, or if cacheDir is empty and there is no user cache directory.
+func WithDomains(cacheDir string, domains ...string) simplerouter.ServerOption {
+	if len(domains) == 0 {
+		panic("domains parameter cannot be empty")
+	}
+	if cacheDir == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			panic(err)
+		}
+		cacheDir = filepath.Join(dir, "simplerouter", "autocert")
+	}
+	return simplerouter.WithTLSConfig(Manager(cacheDir, domains...).TLSConfig())
+}
+
+// Manager returns the ACME client of [WithDomains], to serve its HTTP-01 challenges with
+// HTTPHandler or to use its TLS configuration with other servers.
+func Manager(cacheDir string, domains ...string) *autocert.Manager {
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cacheDir),
+	}
+}
@@ -0,0 +1,33 @@
+package autotls_test
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/autotls"
+)
+
+// TestManager tests that the manager only requests certificates for the configured domains
+func TestManager(t *testing.T) {
+	manager := autotls.Manager(t.TempDir(), "example.com")
+	if err := manager.HostPolicy(context.Background(), "example.com"); err != nil {
+		t.Errorf("HostPolicy(example.com) = %v, want nil", err)
+	}
+	if err := manager.HostPolicy(context.Background(), "other.example.com"); err == nil {
+		t.Error("HostPolicy(other.example.com) = nil, want an error")
+	}
+	if _, err := manager.GetCertificate(&tls.ClientHelloInfo{ServerName: "other.example.com"}); err == nil {
+		t.Error("GetCertificate(other.example.com) succeeded, want an error")
+	}
+}
+
+// TestWithDomainsWithoutDomains tests that WithDomains panics without domains
+func TestWithDomainsWithoutDomains(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WithDomains() did not panic")
+		}
+	}()
+	autotls.WithDomains(t.TempDir())
+}
@@ -0,0 +1,13 @@
+module github.com/carlos-el/simplerouter/autotls
+
+go 1.25.5
+
+require github.com/carlos-el/simplerouter v0.0.0
+
+require (
+	golang.org/x/crypto v0.46.0
+	golang.org/x/net v0.47.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+)
+
+replace github.com/carlos-el/simplerouter => ../
@@ -0,0 +1,84 @@
+package benchmarks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/go-chi/chi"
+	"github.com/gorilla/mux"
+)
+
+// writeUser writes the user wildcard read with the given function, and the path of the request
+func writeUser(pathValue func(req *http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(pathValue(req) + " at " + req.URL.Path))
+	}
+}
+
+// TestWrapAdapters tests chi and gorilla/mux routers wrapped into simplerouter trees, with their prefixes
+// stripped or kept as they expect, their own 404 answers and the requests falling through to them
+func TestWrapAdapters(t *testing.T) {
+	chiRouter := chi.NewRouter()
+	chiRouter.Get("/users/{id}", writeUser(func(req *http.Request) string { return chi.URLParam(req, "id") }))
+
+	gorillaRouter := mux.NewRouter()
+	gorillaAPI := gorillaRouter.PathPrefix("/gorilla").Subrouter()
+	gorillaAPI.HandleFunc("/users/{id}", writeUser(func(req *http.Request) string { return mux.Vars(req)["id"] })).Methods(http.MethodGet)
+
+	fallback := chi.NewRouter()
+	fallback.Get("/old/{id}", writeUser(func(req *http.Request) string { return chi.URLParam(req, "id") }))
+
+	authorized := 0
+	handler := r.NewRoute("").Add(
+		r.NewRoute("/api").Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				authorized++
+				next.ServeHTTP(w, req)
+			})
+		}).Add(
+			r.NewRoute("/users").Add(r.Get(ok)),
+			r.NewRoute("/chi").Add(r.Wrap(chiRouter)),
+		),
+		r.NewRoute("/gorilla").Add(r.WrapKeepPath(gorillaRouter)),
+		r.Wrap(fallback),
+	).Mount(r.WithValidation())
+
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		expectedStatus     int
+		expectedBody       string
+		expectedAuthorized int
+	}{
+		{name: "chi route", method: http.MethodGet, path: "/api/chi/users/42", expectedStatus: http.StatusOK, expectedBody: "42 at /users/42", expectedAuthorized: 1},
+		{name: "chi not found", method: http.MethodGet, path: "/api/chi/orders", expectedStatus: http.StatusNotFound, expectedAuthorized: 1},
+		{name: "chi method not allowed", method: http.MethodPost, path: "/api/chi/users/42", expectedStatus: http.StatusMethodNotAllowed, expectedAuthorized: 1},
+		{name: "gorilla route", method: http.MethodGet, path: "/gorilla/users/42", expectedStatus: http.StatusOK, expectedBody: "42 at /gorilla/users/42"},
+		{name: "gorilla not found", method: http.MethodGet, path: "/gorilla/orders", expectedStatus: http.StatusNotFound},
+		{name: "gorilla method not allowed", method: http.MethodPost, path: "/gorilla/users/42", expectedStatus: http.StatusMethodNotAllowed},
+		{name: "route of the tree", method: http.MethodGet, path: "/api/users", expectedStatus: http.StatusOK, expectedAuthorized: 1},
+		{name: "fall through", method: http.MethodGet, path: "/old/7", expectedStatus: http.StatusOK, expectedBody: "7 at /old/7"},
+		{name: "fall through not found", method: http.MethodGet, path: "/missing", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authorized = 0
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("status %d, want %d", w.Code, tt.expectedStatus)
+			}
+			if tt.expectedBody != "" && w.Body.String() != tt.expectedBody {
+				t.Errorf("body %q, want %q", w.Body.String(), tt.expectedBody)
+			}
+			if authorized != tt.expectedAuthorized {
+				t.Errorf("authorized %d times, want %d", authorized, tt.expectedAuthorized)
+			}
+		})
+	}
+}
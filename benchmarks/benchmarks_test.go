@@ -0,0 +1,181 @@
+package benchmarks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// discardWriter is an http.ResponseWriter discarding the responses, so only routing is measured.
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(statusCode int)  {}
+
+// requests are the requests of the benchmarks, matching routes of githubAPI.
+var requests = []struct {
+	name string
+	req  *http.Request
+}{
+	{name: "static", req: httptest.NewRequest(http.MethodGet, "/user/repos", nil)},
+	{name: "param", req: httptest.NewRequest(http.MethodGet, "/users/octocat", nil)},
+	{name: "deepParam", req: httptest.NewRequest(http.MethodGet, "/repos/octocat/hello/issues/1/comments", nil)},
+	{name: "notFound", req: httptest.NewRequest(http.MethodGet, "/repos/octocat/hello/wiki", nil)},
+}
+
+// middlewares returns n middlewares doing nothing.
+func middlewares(n int) []func(http.Handler) http.Handler {
+	mws := make([]func(http.Handler) http.Handler, n)
+	for i := range mws {
+		mws[i] = noop
+	}
+	return mws
+}
+
+// serve runs the benchmark serving the request with the handler.
+func serve(b *testing.B, handler http.Handler, req *http.Request) {
+	w := &discardWriter{header: http.Header{}}
+	b.ReportAllocs()
+	for b.Loop() {
+		handler.ServeHTTP(w, req)
+	}
+}
+
+// TestRouters tests that every router serves the route set, so the routers are compared fairly.
+func TestRouters(t *testing.T) {
+	for _, rt := range routers {
+		handler := rt.build(githubAPI, middlewares(1))
+		for _, route := range githubAPI {
+			path := strings.NewReplacer("{", "", "}", "").Replace(route.path)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(route.method, path, nil))
+			if w.Code != http.StatusOK {
+				t.Errorf("%s: %s %s = %d, want 200", rt.name, route.method, path, w.Code)
+			}
+		}
+		for _, n := range sizes {
+			handler := rt.build(generateRoutes(n), nil)
+			for _, route := range generateRoutes(n) {
+				path := strings.ReplaceAll(route.path, "{id}", "42")
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, httptest.NewRequest(route.method, path, nil))
+				if w.Code != http.StatusOK {
+					t.Errorf("%s: %s %s = %d, want 200", rt.name, route.method, path, w.Code)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkRouting measures the cost of matching requests to the routes.
+func BenchmarkRouting(b *testing.B) {
+	for _, rt := range routers {
+		handler := rt.build(githubAPI, nil)
+		for _, r := range requests {
+			b.Run(rt.name+"/"+r.name, func(b *testing.B) {
+				serve(b, handler, r.req)
+			})
+		}
+	}
+}
+
+// BenchmarkMiddleware measures the cost of chaining middlewares to the routes.
+func BenchmarkMiddleware(b *testing.B) {
+	for _, rt := range routers {
+		for _, n := range []int{1, 5, 10} {
+			handler := rt.build(githubAPI, middlewares(n))
+			b.Run(fmt.Sprintf("%s/%dmiddlewares", rt.name, n), func(b *testing.B) {
+				serve(b, handler, requests[1].req)
+			})
+		}
+	}
+}
+
+// BenchmarkBuild measures the time and memory used to build the route set.
+func BenchmarkBuild(b *testing.B) {
+	for _, rt := range routers {
+		b.Run(rt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				rt.build(githubAPI, middlewares(5))
+			}
+		})
+	}
+}
+
+// sizes are the numbers of routes of the generated route sets of the benchmarks.
+var sizes = []int{10, 100, 1000}
+
+// BenchmarkMount measures the time and memory used to build and mount generated route sets of each size.
+func BenchmarkMount(b *testing.B) {
+	for _, n := range sizes {
+		routes := generateRoutes(n)
+		for _, rt := range routers {
+			b.Run(fmt.Sprintf("%s/%droutes", rt.name, n), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					rt.build(routes, middlewares(5))
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkServe measures the cost per request of generated route sets of each size, with 5 middlewares,
+// requesting the last routes registered, the slowest to match for the routers trying them in order.
+func BenchmarkServe(b *testing.B) {
+	for _, n := range sizes {
+		last := fmt.Sprintf("/resources%d", n/5-1)
+		reqs := []struct {
+			name string
+			req  *http.Request
+		}{
+			{name: "static", req: httptest.NewRequest(http.MethodPost, last, nil)},
+			{name: "param", req: httptest.NewRequest(http.MethodDelete, last+"/42", nil)},
+		}
+		for _, rt := range routers {
+			handler := rt.build(generateRoutes(n), middlewares(5))
+			for _, r := range reqs {
+				b.Run(fmt.Sprintf("%s/%droutes/%s", rt.name, n, r.name), func(b *testing.B) {
+					serve(b, handler, r.req)
+				})
+			}
+		}
+	}
+}
+
+// BenchmarkDeepTree measures the cost per request of the routes of deep trees, with a middleware
+// used at every level, compared to the same middlewares chained on a bare http.ServeMux handler.
+// The middleware chains are built when mounting, so the cost does not depend on the depth
+// but on the number of middlewares.
+func BenchmarkDeepTree(b *testing.B) {
+	for _, depth := range []int{1, 5, 10} {
+		path := strings.Repeat("/level", depth)
+		req := httptest.NewRequest(http.MethodGet, path+"/users/octocat", nil)
+
+		b.Run(fmt.Sprintf("servemux/depth%d", depth), func(b *testing.B) {
+			serve(b, buildServeMux([]route{{http.MethodGet, path + "/users/{user}"}}, middlewares(depth)), req)
+		})
+
+		tree := func() *r.Route {
+			root := r.NewRoute("").Add(r.NewRoute("/users/{user}").Add(r.Get(ok)))
+			for range depth {
+				root = r.NewRoute("/level").Use(noop).Add(root)
+			}
+			return root
+		}
+		b.Run(fmt.Sprintf("simplerouter/depth%d", depth), func(b *testing.B) {
+			serve(b, tree().Mount(), req)
+		})
+		b.Run(fmt.Sprintf("simplerouter-fast/depth%d", depth), func(b *testing.B) {
+			serve(b, tree().MountFast(), req)
+		})
+	}
+}
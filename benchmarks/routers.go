@@ -0,0 +1,95 @@
+package benchmarks
+
+import (
+	"net/http"
+	"regexp"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/go-chi/chi"
+	"github.com/gorilla/mux"
+	"github.com/labstack/echo/v4"
+)
+
+// buildServeMux serves the routes with a bare http.ServeMux, applying the middlewares to each handler.
+func buildServeMux(routes []route, middlewares []func(http.Handler) http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	for _, rt := range routes {
+		var handler http.Handler = http.HandlerFunc(ok)
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			handler = middlewares[i](handler)
+		}
+		mux.Handle(rt.method+" "+rt.path, handler)
+	}
+	return mux
+}
+
+// simplerouterConstructors maps the methods of the routes to the simplerouter constructors.
+var simplerouterConstructors = map[string]func(http.HandlerFunc) *r.Route{
+	http.MethodGet:    r.Get,
+	http.MethodPost:   r.Post,
+	http.MethodPut:    r.Put,
+	http.MethodPatch:  r.Patch,
+	http.MethodDelete: r.Delete,
+}
+
+// buildSimplerouter serves the routes with a simplerouter tree, the middlewares used by its root.
+func buildSimplerouter(routes []route, middlewares []func(http.Handler) http.Handler) http.Handler {
+	root := r.NewRoute("")
+	for _, mw := range middlewares {
+		root.Use(mw)
+	}
+	for _, rt := range routes {
+		root.Add(r.NewRoute(rt.path).Add(simplerouterConstructors[rt.method](ok)))
+	}
+	return root.Mount()
+}
+
+// buildSimplerouterFast serves the routes with the same simplerouter tree as buildSimplerouter, mounted with MountFast.
+func buildSimplerouterFast(routes []route, middlewares []func(http.Handler) http.Handler) http.Handler {
+	root := r.NewRoute("")
+	for _, mw := range middlewares {
+		root.Use(mw)
+	}
+	for _, rt := range routes {
+		root.Add(r.NewRoute(rt.path).Add(simplerouterConstructors[rt.method](ok)))
+	}
+	return root.MountFast()
+}
+
+// buildChi serves the routes with a chi router.
+func buildChi(routes []route, middlewares []func(http.Handler) http.Handler) http.Handler {
+	mux := chi.NewRouter()
+	mux.Use(middlewares...)
+	for _, rt := range routes {
+		mux.MethodFunc(rt.method, rt.path, ok)
+	}
+	return mux
+}
+
+// buildGorilla serves the routes with a gorilla/mux router.
+func buildGorilla(routes []route, middlewares []func(http.Handler) http.Handler) http.Handler {
+	router := mux.NewRouter()
+	for _, mw := range middlewares {
+		router.Use(mw)
+	}
+	for _, rt := range routes {
+		router.HandleFunc(rt.path, ok).Methods(rt.method)
+	}
+	return router
+}
+
+// echoWildcards matches the {name} wildcards of the paths, written :name in echo routes.
+var echoWildcards = regexp.MustCompile(`\{(\w+)\}`)
+
+// buildEcho serves the routes with an echo router, the middlewares wrapped as echo middlewares.
+func buildEcho(routes []route, middlewares []func(http.Handler) http.Handler) http.Handler {
+	e := echo.New()
+	for _, mw := range middlewares {
+		e.Use(echo.WrapMiddleware(mw))
+	}
+	handler := echo.WrapHandler(http.HandlerFunc(ok))
+	for _, rt := range routes {
+		e.Add(rt.method, echoWildcards.ReplaceAllString(rt.path, ":$1"), handler)
+	}
+	return e
+}
@@ -0,0 +1,123 @@
+// Package benchmarks compares the routing overhead, middleware chaining cost and memory use of simplerouter
+// with the ones of other routers, serving the same route set with each of them, and measures how the mount
+// time and the cost per request grow with the number of routes.
+//
+// Run the benchmarks from this directory using the following command:
+//
+//	$ go test -bench . -benchmem
+//
+// Routers are compared by adding them to the routers list, see router.
+// As this module depends on the other routers, it also tests them wrapped into simplerouter trees
+// with simplerouter.Wrap, to migrate them gradually.
+package benchmarks
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// route is a route of the benchmarked route set, its path with {name} wildcards.
+type route struct {
+	method string
+	path   string
+}
+
+// githubAPI is a subset of the GitHub REST API, mixing static and parametrized routes.
+var githubAPI = []route{
+	{http.MethodGet, "/user"},
+	{http.MethodPatch, "/user"},
+	{http.MethodGet, "/user/repos"},
+	{http.MethodPost, "/user/repos"},
+	{http.MethodGet, "/user/orgs"},
+	{http.MethodGet, "/user/followers"},
+	{http.MethodGet, "/user/following/{user}"},
+	{http.MethodPut, "/user/following/{user}"},
+	{http.MethodDelete, "/user/following/{user}"},
+	{http.MethodGet, "/users"},
+	{http.MethodGet, "/users/{user}"},
+	{http.MethodGet, "/users/{user}/repos"},
+	{http.MethodGet, "/users/{user}/orgs"},
+	{http.MethodGet, "/users/{user}/followers"},
+	{http.MethodGet, "/users/{user}/gists"},
+	{http.MethodGet, "/orgs/{org}"},
+	{http.MethodPatch, "/orgs/{org}"},
+	{http.MethodGet, "/orgs/{org}/repos"},
+	{http.MethodPost, "/orgs/{org}/repos"},
+	{http.MethodGet, "/orgs/{org}/members"},
+	{http.MethodGet, "/orgs/{org}/members/{user}"},
+	{http.MethodDelete, "/orgs/{org}/members/{user}"},
+	{http.MethodGet, "/orgs/{org}/teams"},
+	{http.MethodPost, "/orgs/{org}/teams"},
+	{http.MethodGet, "/repos/{owner}/{repo}"},
+	{http.MethodPatch, "/repos/{owner}/{repo}"},
+	{http.MethodDelete, "/repos/{owner}/{repo}"},
+	{http.MethodGet, "/repos/{owner}/{repo}/branches"},
+	{http.MethodGet, "/repos/{owner}/{repo}/branches/{branch}"},
+	{http.MethodGet, "/repos/{owner}/{repo}/commits"},
+	{http.MethodGet, "/repos/{owner}/{repo}/commits/{sha}"},
+	{http.MethodGet, "/repos/{owner}/{repo}/issues"},
+	{http.MethodPost, "/repos/{owner}/{repo}/issues"},
+	{http.MethodGet, "/repos/{owner}/{repo}/issues/{number}"},
+	{http.MethodPatch, "/repos/{owner}/{repo}/issues/{number}"},
+	{http.MethodGet, "/repos/{owner}/{repo}/issues/{number}/comments"},
+	{http.MethodPost, "/repos/{owner}/{repo}/issues/{number}/comments"},
+	{http.MethodGet, "/repos/{owner}/{repo}/pulls"},
+	{http.MethodPost, "/repos/{owner}/{repo}/pulls"},
+	{http.MethodGet, "/repos/{owner}/{repo}/pulls/{number}"},
+	{http.MethodPut, "/repos/{owner}/{repo}/pulls/{number}/merge"},
+	{http.MethodGet, "/repos/{owner}/{repo}/releases"},
+	{http.MethodGet, "/repos/{owner}/{repo}/releases/latest"},
+	{http.MethodGet, "/gists"},
+	{http.MethodPost, "/gists"},
+	{http.MethodGet, "/gists/{id}"},
+	{http.MethodDelete, "/gists/{id}"},
+	{http.MethodGet, "/search/repositories"},
+	{http.MethodGet, "/search/users"},
+	{http.MethodGet, "/rate_limit"},
+}
+
+// generateRoutes returns a route set of n routes, a multiple of 5, with a collection and an item route
+// for each resource, like the routes of a REST API.
+func generateRoutes(n int) []route {
+	routes := make([]route, 0, n)
+	for i := range n / 5 {
+		collection := fmt.Sprintf("/resources%d", i)
+		routes = append(routes,
+			route{http.MethodGet, collection},
+			route{http.MethodPost, collection},
+			route{http.MethodGet, collection + "/{id}"},
+			route{http.MethodPut, collection + "/{id}"},
+			route{http.MethodDelete, collection + "/{id}"},
+		)
+	}
+	return routes
+}
+
+// router builds a handler serving a route set with a router, with the given middlewares
+// applied to all the routes.
+type router struct {
+	name  string
+	build func(routes []route, middlewares []func(http.Handler) http.Handler) http.Handler
+}
+
+// routers are the routers compared, the first one being the baseline.
+var routers = []router{
+	{name: "servemux", build: buildServeMux},
+	{name: "simplerouter", build: buildSimplerouter},
+	{name: "simplerouter-fast", build: buildSimplerouterFast},
+	{name: "chi", build: buildChi},
+	{name: "gorilla", build: buildGorilla},
+	{name: "echo", build: buildEcho},
+}
+
+// ok is the handler of every benchmarked route.
+func ok(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// noop is a middleware doing nothing but calling the next handler.
+func noop(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+	})
+}
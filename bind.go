@@ -0,0 +1,284 @@
+package simplerouter
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BindError is the error returned by [Bind] when a value of the request cannot be stored in a field.
+type BindError struct {
+	// Source is the part of the request holding the value: "path", "query", "header", "form" or "body".
+	Source string
+	// Name is the name of the value in its source, like the query parameter name. It is empty for the body.
+	Name string
+	// Field is the name of the struct field, empty for the body.
+	Field string
+	// Err is the error parsing the value.
+	Err error
+}
+
+// Error returns the source, name and field of the value followed by the error.
+func (e *BindError) Error() string {
+	if e.Source == "body" {
+		return "bind body: " + e.Err.Error()
+	}
+	return fmt.Sprintf("bind %s %q to field %s: %v", e.Source, e.Name, e.Field, e.Err)
+}
+
+// Unwrap returns the error parsing the value.
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// ErrInvalidBindTarget is returned by [Bind] when the destination is not a non-nil pointer to a struct.
+var ErrInvalidBindTarget = errors.New("dst parameter must be a non-nil pointer to a struct")
+
+// Bind stores the values of the request into the fields of the struct pointed to by dst, following
+// the tags of the fields:
+//
+//	type updateUser struct {
+//		ID     int    `path:"id"`
+//		Notify bool   `query:"notify"`
+//		Tenant string `header:"X-Tenant"`
+//		Name   string `json:"name"`
+//	}
+//
+// The body is decoded first, as JSON into the whole struct for JSON requests, or field by field following
+// the form tags for URL-encoded and multipart form requests. The files of multipart forms are stored in
+// the *multipart.FileHeader and []*multipart.FileHeader fields with form tags, and the parts of the form
+// beyond 32 MiB are stored in temporary files, removed by the server once the request is answered,
+// see http.Request.ParseMultipartForm. Then the path wildcards of the matched pattern, the query
+// parameters and the headers are stored in the fields with path, query and header tags, overriding
+// the body. Values missing from the request leave their fields untouched, so defaults can be set before.
+// Fields can be strings, booleans, integers, floats, types implementing encoding.TextUnmarshaler, and
+// slices of them filled with every value of query parameters, headers and form fields.
+// Embedded structs are bound as part of the struct. It returns a [BindError] if a value cannot be stored.
+// The bound struct is then validated with the validator of the route, if any, returning a [ValidationError]
+// if it is not valid, see [Route.Validator].
+func Bind(r *http.Request, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidBindTarget
+	}
+
+	if err := bindBody(r, dst); err != nil {
+		return err
+	}
+	if err := bindFields(v.Elem(), "form", func(name string) []string { return r.PostForm[name] }); err != nil {
+		return err
+	}
+	if r.MultipartForm != nil {
+		bindFiles(v.Elem(), r.MultipartForm.File)
+	}
+	if err := bindFields(v.Elem(), "path", func(name string) []string {
+		if value := r.PathValue(name); value != "" {
+			return []string{value}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	query := r.URL.Query()
+	if err := bindFields(v.Elem(), "query", func(name string) []string { return query[name] }); err != nil {
+		return err
+	}
+	if err := bindFields(v.Elem(), "header", func(name string) []string { return r.Header.Values(name) }); err != nil {
+		return err
+	}
+	return validateBound(r, dst)
+}
+
+// multipartMemory is the maximum number of bytes of the multipart forms bound by [Bind] kept in memory.
+const multipartMemory = 32 << 20
+
+// bindBody decodes the JSON body of the request into dst, or parses its URL-encoded or multipart form.
+func bindBody(r *http.Request, dst any) error {
+	if r.Body == nil || r.Body == http.NoBody || !hasJSONBody(r.Method) {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return &BindError{Source: "body", Err: err}
+		}
+	case mediaType == "multipart/form-data":
+		if err := r.ParseMultipartForm(multipartMemory); err != nil {
+			return &BindError{Source: "body", Err: err}
+		}
+	case isJSON(mediaType):
+		if err := json.NewDecoder(r.Body).Decode(dst); err != nil && !errors.Is(err, io.EOF) {
+			return &BindError{Source: "body", Err: err}
+		}
+	}
+	return nil
+}
+
+// bindFields stores the values returned by lookup for the names of the tag in the fields of the struct v.
+func bindFields(v reflect.Value, tag string, lookup func(name string) []string) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := bindFields(v.Field(i), tag, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		values := lookup(name)
+		if len(values) == 0 {
+			continue
+		}
+		if err := setField(v.Field(i), values); err != nil {
+			return &BindError{Source: tag, Name: name, Field: field.Name, Err: err}
+		}
+	}
+	return nil
+}
+
+// fileHeaderType is the type of the fields holding the files of multipart forms.
+var fileHeaderType = reflect.TypeFor[*multipart.FileHeader]()
+
+// bindFiles stores the files of a multipart form in the file fields with form tags of the struct v,
+// the first file in *multipart.FileHeader fields and all of them in []*multipart.FileHeader fields.
+func bindFiles(v reflect.Value, files map[string][]*multipart.FileHeader) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			bindFiles(v.Field(i), files)
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if !field.IsExported() || name == "" || name == "-" || len(files[name]) == 0 {
+			continue
+		}
+
+		switch field.Type {
+		case fileHeaderType:
+			v.Field(i).Set(reflect.ValueOf(files[name][0]))
+		case reflect.SliceOf(fileHeaderType):
+			v.Field(i).Set(reflect.ValueOf(files[name]))
+		}
+	}
+}
+
+// textUnmarshalerType is the type of the encoding.TextUnmarshaler interface.
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// setField parses the values into the field, all of them for slices and the first one otherwise.
+func setField(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice && !field.Addr().Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	return setValue(field, values[0])
+}
+
+// setValue parses the value into v.
+func setValue(v reflect.Value, value string) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValue(v.Elem(), value)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// writeBindError answers a request that could not be bound with err, see [Bind].
+func writeBindError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	JSONError(w, r, http.StatusBadRequest)
+}
+
+// boundKey is the context key storing the value bound by [BindMiddleware].
+type boundKey[T any] struct{}
+
+// BindMiddleware returns a middleware binding each request into a new T with [Bind], stored in the request
+// context for the next handlers, which read it with [Bound]. Requests that cannot be bound are answered
+// with a 400 Bad Request by [JSONError], and the ones that are not valid with a 422 Unprocessable Entity
+// listing the errors of their fields. It panics if T is not a struct.
+func BindMiddleware[T any]() Middleware {
+	if reflect.TypeFor[T]().Kind() != reflect.Struct {
+		panic("T type parameter must be a struct")
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			dst := new(T)
+			if err := Bind(r, dst); err != nil {
+				writeBindError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), boundKey[T]{}, dst)))
+		})
+	}
+}
+
+// Bound returns the value bound by [BindMiddleware] for the request, or nil if there is none.
+func Bound[T any](r *http.Request) *T {
+	dst, _ := r.Context().Value(boundKey[T]{}).(*T)
+	return dst
+}
@@ -0,0 +1,156 @@
+package simplerouter_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+type pagination struct {
+	Page int `query:"page"`
+}
+
+type updateRequest struct {
+	pagination
+	ID      int       `path:"id"`
+	Tags    []string  `query:"tag"`
+	Tenant  string    `header:"X-Tenant"`
+	Since   time.Time `query:"since"`
+	Ratio   *float64  `query:"ratio"`
+	Name    string    `json:"name" form:"name"`
+	Enabled bool      `json:"enabled" form:"enabled"`
+}
+
+// TestBind tests the values bound from the requests
+func TestBind(t *testing.T) {
+	tests := []struct {
+		name          string
+		target        string
+		contentType   string
+		body          string
+		expected      string
+		expectedError string
+	}{
+		{name: "JSON body", target: "/items/3?page=2&tag=a&tag=b&since=2024-01-02T00:00:00Z&ratio=0.5", contentType: "application/json", body: `{"name":"ada","enabled":true}`, expected: "3 2 [a b] acme 2024-01-02 0.5 ada true"},
+		{name: "form body", target: "/items/3", contentType: "application/x-www-form-urlencoded", body: "name=alan&enabled=1", expected: "3 0 [] acme 0001-01-01 <nil> alan true"},
+		{name: "invalid query", target: "/items/3?page=two", expectedError: `bind query "page" to field Page: strconv.ParseInt: parsing "two": invalid syntax`},
+		{name: "invalid body", target: "/items/3", contentType: "application/json", body: `{"name":1}`, expectedError: "bind body: json: cannot unmarshal number into Go struct field updateRequest.name of type string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			var bindErr error
+			mux := r.NewRoute("/items/{id}").Add(r.Patch(func(w http.ResponseWriter, req *http.Request) {
+				var dst updateRequest
+				if bindErr = r.Bind(req, &dst); bindErr != nil {
+					return
+				}
+				ratio := "<nil>"
+				if dst.Ratio != nil {
+					ratio = "0.5"
+				}
+				got = strings.Join([]string{
+					strconv.Itoa(dst.ID), strconv.Itoa(dst.Page), "[" + strings.Join(dst.Tags, " ") + "]", dst.Tenant,
+					dst.Since.Format(time.DateOnly), ratio, dst.Name, map[bool]string{true: "true", false: "false"}[dst.Enabled],
+				}, " ")
+			})).Mount()
+
+			req := httptest.NewRequest(http.MethodPatch, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("X-Tenant", "acme")
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			mux.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.expectedError != "" {
+				var target *r.BindError
+				if !errors.As(bindErr, &target) {
+					t.Fatalf("Bind() error = %v, want a BindError", bindErr)
+				}
+				assertCorrect(t, bindErr.Error(), tt.expectedError)
+				return
+			}
+			assertCorrect(t, bindErr, nil)
+			assertCorrect(t, got, tt.expected)
+		})
+	}
+}
+
+// TestBindMultipartForm tests the values and files bound from multipart forms
+func TestBindMultipartForm(t *testing.T) {
+	type profileForm struct {
+		Name        string                  `form:"name"`
+		Avatar      *multipart.FileHeader   `form:"avatar"`
+		Attachments []*multipart.FileHeader `form:"attachment"`
+		Missing     *multipart.FileHeader   `form:"missing"`
+	}
+
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	mw.WriteField("name", "ada")
+	for name, content := range map[string]string{"avatar": "png", "attachment": "a"} {
+		w, _ := mw.CreateFormFile(name, name+".bin")
+		w.Write([]byte(content))
+	}
+	w, _ := mw.CreateFormFile("attachment", "b.bin")
+	w.Write([]byte("b"))
+	mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/profile", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	var dst profileForm
+	assertCorrect(t, r.Bind(req, &dst), nil)
+
+	assertCorrect(t, dst.Name, "ada")
+	assertCorrect(t, dst.Avatar.Filename, "avatar.bin")
+	assertCorrect(t, len(dst.Attachments), 2)
+	assertCorrect(t, dst.Attachments[1].Filename, "b.bin")
+	assertCorrect(t, dst.Missing == nil, true)
+	f, err := dst.Avatar.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	content, _ := io.ReadAll(f)
+	assertCorrect(t, string(content), "png")
+
+	req = httptest.NewRequest(http.MethodPost, "/profile", strings.NewReader("--x\r\nContent-Disposition: form-data; name=\"name\"\r\n\r\nada"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	var bindErr *r.BindError
+	assertCorrect(t, errors.As(r.Bind(req, &dst), &bindErr), true)
+	assertCorrect(t, bindErr.Source, "body")
+}
+
+// TestBindInvalidTarget tests that only pointers to structs can be bound
+func TestBindInvalidTarget(t *testing.T) {
+	var s string
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	assertCorrect(t, r.Bind(req, &s), r.ErrInvalidBindTarget)
+	assertCorrect(t, r.Bind(req, nil), r.ErrInvalidBindTarget)
+}
+
+// TestBindMiddleware tests the values bound by the binding middleware
+func TestBindMiddleware(t *testing.T) {
+	mux := r.NewRoute("/items").Use(r.BindMiddleware[pagination]()).Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(strconv.Itoa(r.Bound[pagination](req).Page)))
+	})).Mount()
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items?page=4", nil))
+	assertCorrect(t, w.Code, http.StatusOK)
+	assertCorrect(t, w.Body.String(), "4")
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items?page=x", nil))
+	assertCorrect(t, w.Code, http.StatusBadRequest)
+}
@@ -0,0 +1,14 @@
+package simplerouter
+
+// MaxBodySize limits the size of the request bodies of the route and its child routes to n bytes
+// with the [github.com/carlos-el/simplerouter/middleware.MaxBytes] middleware, answering larger requests with a 413 JSON error.
+// Child routes can set their own limit, higher or lower, which replaces the one of their parents,
+// so an upload subtree can accept bigger bodies than the rest of an API.
+// Once mounted, the limit applies right before the handler, so the middlewares of the route see the 413 responses.
+func (r *Route) MaxBodySize(n int64) *Route {
+	if n <= 0 {
+		panic("n parameter must be greater than zero")
+	}
+	r.Metadata.MaxBodySize = n
+	return r
+}
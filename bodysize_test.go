@@ -0,0 +1,73 @@
+package simplerouter_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// echoBody writes the request body back, answering with a 400 status code if it cannot be read
+func echoBody(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.Write(body)
+}
+
+// TestMaxBodySize tests the body limits of mounted subtrees
+func TestMaxBodySize(t *testing.T) {
+	statuses := []int{}
+	recordStatus := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			rec := httptest.NewRecorder()
+			next.ServeHTTP(rec, req)
+			statuses = append(statuses, rec.Code)
+			w.WriteHeader(rec.Code)
+			w.Write(rec.Body.Bytes())
+		})
+	}
+
+	mux := r.NewRoute("/api").MaxBodySize(4).Use(recordStatus).Add(
+		r.NewRoute("/users").Add(r.Post(echoBody)),
+		r.NewRoute("/upload").MaxBodySize(16).Add(r.Post(echoBody)),
+	).Mount()
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "within the parent limit", path: "/api/users", body: "abc", expectedStatus: http.StatusOK},
+		{name: "over the parent limit", path: "/api/users", body: "abcdefgh", expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "within the child limit", path: "/api/upload", body: "abcdefgh", expectedStatus: http.StatusOK},
+		{name: "over the child limit", path: "/api/upload", body: strings.Repeat("a", 20), expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, statuses[len(statuses)-1], tt.expectedStatus)
+		})
+	}
+}
+
+// TestMaxBodySizeWithInvalidSize tests that non positive sizes cause a panic
+func TestMaxBodySizeWithInvalidSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MaxBodySize(0) to panic, but it didn't")
+		}
+	}()
+
+	r.NewRoute("/").MaxBodySize(0)
+}
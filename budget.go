@@ -0,0 +1,49 @@
+package simplerouter
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Timeout sets the time budget of the requests of the route and its child routes: once mounted, the
+// context of their requests is canceled after d, so handlers and downstream calls using it give up
+// instead of running past the point where the client stopped waiting. Handlers read the remaining
+// budget with [Budget] to derive the timeouts of their downstream calls. Child routes can set their
+// own budget, which replaces the one of their parents, but it must not be greater than it, as reported
+// by [Route.Validate]. The budget applies before the middlewares of the route, so it covers them too.
+func (r *Route) Timeout(d time.Duration) *Route {
+	if d <= 0 {
+		panic("d parameter must be greater than zero")
+	}
+	r.Metadata.Timeout = d
+	return r
+}
+
+// Budget returns the time remaining until the deadline of ctx, set by [Route.Timeout] or by any
+// other deadline of the request, and whether ctx has a deadline. The remaining time is negative once
+// the deadline has passed. Handlers use it to give sub-timeouts to their downstream calls:
+//
+//	if budget, ok := simplerouter.Budget(r.Context()); ok {
+//		ctx, cancel := context.WithTimeout(r.Context(), budget*8/10)
+//		defer cancel()
+//		...
+//	}
+func Budget(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// withTimeout returns a Middleware canceling the context of the requests after d.
+func withTimeout(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
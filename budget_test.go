@@ -0,0 +1,57 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestTimeout tests the budget of the requests of routes with timeouts
+func TestTimeout(t *testing.T) {
+	budgetWriter := func(w http.ResponseWriter, req *http.Request) {
+		budget, ok := r.Budget(req.Context())
+		if !ok {
+			w.Write([]byte("none"))
+			return
+		}
+		w.Write([]byte(budget.Round(time.Minute).String()))
+	}
+	route := r.NewRoute("").Add(
+		r.NewRoute("/api").Timeout(time.Hour).Add(
+			r.NewRoute("/users").Add(r.Get(budgetWriter)),
+			r.NewRoute("/search").Timeout(time.Minute).Add(r.Get(budgetWriter)),
+		),
+		r.NewRoute("/static").Add(r.Get(budgetWriter)),
+	)
+	mux := route.Mount()
+
+	tests := []struct {
+		path           string
+		expectedBudget string
+	}{
+		{path: "/api/users", expectedBudget: "1h0m0s"},
+		{path: "/api/search", expectedBudget: "1m0s"},
+		{path: "/static", expectedBudget: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assertCorrect(t, w.Body.String(), tt.expectedBudget)
+		})
+	}
+}
+
+// TestTimeoutWithInvalidDuration tests that non-positive timeouts cause a panic
+func TestTimeoutWithInvalidDuration(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Timeout() did not panic")
+		}
+	}()
+	r.NewRoute("/").Timeout(0)
+}
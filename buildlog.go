@@ -0,0 +1,99 @@
+package simplerouter
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// buildLog reports whether the construction calls of the routes are recorded.
+var buildLog atomic.Bool
+
+// buildSeq orders the recorded construction calls across all routes.
+var buildSeq atomic.Uint64
+
+// BuildEvent is a construction call recorded while the build log is enabled, see [EnableBuildLog].
+type BuildEvent struct {
+	// Op is the name of the call: "NewRoute", "Get" (or any other method constructor), "All", "Methods", "Parse", "WebSocket", "JSON", "Upload", "Canary", "Build", "Use", "UsePre", "With", "Add" or "Merge".
+	Op string
+	// Route describes the route the call was made on, as its method and path when it has a handler.
+	Route string
+	// Detail describes the arguments of the call: the handler, middlewares or routes added.
+	Detail string
+	// Source is the "file:line" location of the call, the first caller outside this package.
+	Source string
+
+	seq uint64
+}
+
+// String returns a one line description of the event.
+func (e BuildEvent) String() string {
+	return strings.Join(strings.Fields(shortSource(e.Source)+": "+e.Op+" "+e.Route+" "+e.Detail), " ")
+}
+
+// EnableBuildLog enables or disables recording the construction calls of routes.
+// When enabled, every NewRoute, method constructor, Use and Add call is recorded with its caller,
+// and reported by [Route.BuildLog], to find out how a tree assembled dynamically
+// (from configuration, plugins...) was built.
+// It is disabled by default, as it has a cost, and only affects the calls made after enabling it.
+func EnableBuildLog(enabled bool) {
+	buildLog.Store(enabled)
+}
+
+// BuildLog returns the construction calls recorded for the route and its child routes,
+// in the order they were made. It is empty unless the build log was enabled, see [EnableBuildLog].
+func (r *Route) BuildLog() []BuildEvent {
+	var events []BuildEvent
+	seen := map[uint64]bool{}
+	r.collectBuildEvents(&events, seen)
+
+	// Routes are created before being added to their parents, so the tree order is not the call order.
+	slices.SortFunc(events, func(a, b BuildEvent) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+	return events
+}
+
+// collectBuildEvents appends the events of the route and its child routes not seen yet.
+func (r *Route) collectBuildEvents(events *[]BuildEvent, seen map[uint64]bool) {
+	for _, event := range r.buildEvents {
+		if !seen[event.seq] {
+			seen[event.seq] = true
+			*events = append(*events, event)
+		}
+	}
+	for _, route := range r.Routes {
+		route.collectBuildEvents(events, seen)
+	}
+}
+
+// recordBuild records a construction call on the route if the build log is enabled.
+// The detail is only computed when recording.
+func (r *Route) recordBuild(op string, detail func() string) *Route {
+	if !buildLog.Load() {
+		return r
+	}
+	r.buildEvents = append(r.buildEvents, BuildEvent{
+		Op:     op,
+		Route:  r.describe(),
+		Detail: detail(),
+		Source: callerSource(),
+		seq:    buildSeq.Add(1),
+	})
+	return r
+}
+
+// describe returns a short description of the route for the build log.
+func (r *Route) describe() string {
+	if r.Handler == nil {
+		return r.Path
+	}
+	return strings.TrimSpace(methodName(r.Method) + " " + r.Path)
+}
+
+// handlerName returns the detail of the method constructor calls for the build log.
+func handlerName(handler http.HandlerFunc) func() string {
+	return func() string { return FuncName(handler) }
+}
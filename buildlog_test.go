@@ -0,0 +1,69 @@
+package simplerouter_test
+
+import (
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestBuildLog tests the construction calls recorded for a tree
+func TestBuildLog(t *testing.T) {
+	r.NewRoute("/ignored").Use(authMiddleware)
+
+	r.EnableBuildLog(true)
+	defer r.EnableBuildLog(false)
+
+	users := r.NewRoute("/users").Use(authMiddleware)
+	usersLine := previousLine()
+	get := r.Get(listUsers)
+	getLine := previousLine()
+	root := r.NewRoute("/api").Add(users.Add(get))
+	rootLine := previousLine()
+
+	tests := []struct {
+		op     string
+		route  string
+		detail string
+		source string
+	}{
+		{op: "NewRoute", route: "/users", detail: "", source: usersLine},
+		{op: "Use", route: "/users", detail: "simplerouter_test.authMiddleware", source: usersLine},
+		{op: "Get", route: "GET", detail: "simplerouter_test.listUsers", source: getLine},
+		{op: "NewRoute", route: "/api", detail: "", source: rootLine},
+		{op: "Add", route: "/users", detail: "GET", source: rootLine},
+		{op: "Add", route: "/api", detail: "/users", source: rootLine},
+	}
+
+	events := root.BuildLog()
+	if len(events) != len(tests) {
+		t.Fatalf("BuildLog() returned %d events, want %d: %v", len(events), len(tests), events)
+	}
+	for i, tt := range tests {
+		t.Run(tt.op+" "+tt.route, func(t *testing.T) {
+			event := events[i]
+			assertCorrect(t, event.Op, tt.op)
+			assertCorrect(t, event.Route, tt.route)
+			assertCorrect(t, event.Detail, tt.detail)
+			if !strings.HasSuffix(event.Source, tt.source) {
+				t.Errorf("Source = %q, want suffix %q", event.Source, tt.source)
+			}
+		})
+	}
+}
+
+// TestBuildLogDisabled tests that nothing is recorded by default
+func TestBuildLogDisabled(t *testing.T) {
+	route := r.NewRoute("/api").Use(authMiddleware).Add(r.Get(listUsers))
+
+	if events := route.BuildLog(); len(events) != 0 {
+		t.Errorf("BuildLog() = %v, want no events", events)
+	}
+}
+
+// TestBuildEventString tests the description of recorded events
+func TestBuildEventString(t *testing.T) {
+	event := r.BuildEvent{Op: "Use", Route: "/users", Detail: "auth", Source: "/src/app/routes.go:12"}
+
+	assertCorrect(t, event.String(), "app/routes.go:12: Use /users auth")
+}
@@ -0,0 +1,148 @@
+package simplerouter
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"net/http"
+)
+
+// CanaryOption configures the routes returned by [Canary].
+type CanaryOption func(*canaryConfig)
+
+// canaryConfig holds the configuration of a canary route.
+type canaryConfig struct {
+	percent      float64
+	header       string
+	headerValue  string
+	cookie       string
+	cookieValue  string
+	stickyCookie string
+	stickyHeader string
+}
+
+// CanaryPercent sends percent of the requests, from 0 to 100, to the canary handler.
+func CanaryPercent(percent float64) CanaryOption {
+	if percent < 0 || percent > 100 {
+		panic("percent parameter must be between 0 and 100")
+	}
+	return func(c *canaryConfig) { c.percent = percent }
+}
+
+// CanaryHeader sends the requests with the header set to value to the canary handler, whatever their
+// share, like the requests of testers. If value is empty, any value of the header matches.
+func CanaryHeader(name, value string) CanaryOption {
+	if name == "" {
+		panic("name parameter cannot be empty")
+	}
+	return func(c *canaryConfig) { c.header, c.headerValue = name, value }
+}
+
+// CanaryCookie sends the requests with the cookie set to value to the canary handler, whatever their
+// share, like the requests of the users that opted in a beta. If value is empty, any value of the cookie matches.
+func CanaryCookie(name, value string) CanaryOption {
+	if name == "" {
+		panic("name parameter cannot be empty")
+	}
+	return func(c *canaryConfig) { c.cookie, c.cookieValue = name, value }
+}
+
+// CanaryStickyCookie keeps each client on the handler chosen for its first request with a cookie with
+// the given name, set on that response, so clients do not switch between both implementations.
+// The cookie stores "canary" or "stable", so clients can choose their handler by setting it.
+func CanaryStickyCookie(name string) CanaryOption {
+	if name == "" {
+		panic("name parameter cannot be empty")
+	}
+	return func(c *canaryConfig) { c.stickyCookie = name }
+}
+
+// CanaryStickyHeader chooses the handler of the requests with the header by hashing its value, like a
+// user or tenant ID, so all the requests with the same value are served by the same handler while the
+// share of the canary does not change. Requests without the header are assigned at random.
+func CanaryStickyHeader(name string) CanaryOption {
+	if name == "" {
+		panic("name parameter cannot be empty")
+	}
+	return func(c *canaryConfig) { c.stickyHeader = name }
+}
+
+// Canary returns a Route with a handler for the given HTTP method that splits the requests between
+// the stable handler and a canary one, so a new implementation can be rolled out to a share of the
+// traffic before replacing the stable one:
+//
+//	router.Add(simplerouter.NewRoute("/search").Add(
+//		simplerouter.Canary(http.MethodGet, search, searchV2,
+//			simplerouter.CanaryPercent(5),
+//			simplerouter.CanaryHeader("X-Canary", "always"),
+//			simplerouter.CanaryStickyCookie("search_variant"),
+//		),
+//	))
+//
+// Requests matching [CanaryHeader] or [CanaryCookie] are always served by the canary handler. The other
+// ones are assigned by [CanaryStickyCookie] or [CanaryStickyHeader] if given, and at random otherwise,
+// sending [CanaryPercent] of them to the canary handler, none by default. Both handlers are behind the
+// middlewares of the route.
+func Canary(method string, stable, canary http.HandlerFunc, opts ...CanaryOption) *Route {
+	if stable == nil || canary == nil {
+		panic("stable and canary parameters cannot be nil")
+	}
+	config := &canaryConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if config.assign(w, r) {
+			canary(w, r)
+			return
+		}
+		stable(w, r)
+	}
+	return (&Route{Handler: handler, Method: method}).recordBuild("Canary", func() string {
+		return FuncName(stable) + ", " + FuncName(canary)
+	})
+}
+
+// assign reports whether the request is served by the canary handler, setting the sticky cookie of new clients.
+func (c *canaryConfig) assign(w http.ResponseWriter, r *http.Request) bool {
+	if c.header != "" {
+		if value := r.Header.Get(c.header); value != "" && (c.headerValue == "" || value == c.headerValue) {
+			return true
+		}
+	}
+	if c.cookie != "" {
+		if cookie, err := r.Cookie(c.cookie); err == nil && (c.cookieValue == "" || cookie.Value == c.cookieValue) {
+			return true
+		}
+	}
+
+	if c.stickyCookie != "" {
+		if cookie, err := r.Cookie(c.stickyCookie); err == nil && (cookie.Value == "canary" || cookie.Value == "stable") {
+			return cookie.Value == "canary"
+		}
+	}
+	var toCanary bool
+	if value := r.Header.Get(c.stickyHeader); c.stickyHeader != "" && value != "" {
+		h := fnv.New32a()
+		h.Write([]byte(value))
+		toCanary = float64(h.Sum32()%10000) < c.percent*100
+	} else {
+		toCanary = rand.Float64()*100 < c.percent
+	}
+
+	if c.stickyCookie != "" {
+		value := "stable"
+		if toCanary {
+			value = "canary"
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     c.stickyCookie,
+			Value:    value,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	return toCanary
+}
@@ -0,0 +1,80 @@
+package simplerouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestCanary tests the handlers serving the requests of canary routes
+func TestCanary(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []r.CanaryOption
+		header         http.Header
+		expectedBody   string
+		expectedCookie string
+	}{
+		{name: "no share", opts: []r.CanaryOption{}, expectedBody: "stable"},
+		{name: "whole share", opts: []r.CanaryOption{r.CanaryPercent(100)}, expectedBody: "canary"},
+		{name: "matching header", opts: []r.CanaryOption{r.CanaryHeader("X-Canary", "always")}, header: http.Header{"X-Canary": {"always"}}, expectedBody: "canary"},
+		{name: "other header value", opts: []r.CanaryOption{r.CanaryHeader("X-Canary", "always")}, header: http.Header{"X-Canary": {"never"}}, expectedBody: "stable"},
+		{name: "any header value", opts: []r.CanaryOption{r.CanaryHeader("X-Canary", "")}, header: http.Header{"X-Canary": {"1"}}, expectedBody: "canary"},
+		{name: "matching cookie", opts: []r.CanaryOption{r.CanaryCookie("beta", "yes")}, header: http.Header{"Cookie": {"beta=yes"}}, expectedBody: "canary"},
+		{name: "new sticky client", opts: []r.CanaryOption{r.CanaryPercent(100), r.CanaryStickyCookie("variant")}, expectedBody: "canary", expectedCookie: "variant=canary; Path=/; HttpOnly; SameSite=Lax"},
+		{name: "sticky client", opts: []r.CanaryOption{r.CanaryPercent(100), r.CanaryStickyCookie("variant")}, header: http.Header{"Cookie": {"variant=stable"}}, expectedBody: "stable"},
+		{name: "header over sticky cookie", opts: []r.CanaryOption{r.CanaryHeader("X-Canary", ""), r.CanaryStickyCookie("variant")}, header: http.Header{"X-Canary": {"1"}, "Cookie": {"variant=stable"}}, expectedBody: "canary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := r.NewRoute("/search").Add(r.Canary(http.MethodGet, handlerWriter("stable"), handlerWriter("canary"), tt.opts...)).Mount()
+			req := httptest.NewRequest(http.MethodGet, "/search", nil)
+			for name, values := range tt.header {
+				req.Header[name] = values
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, w.Header().Get("Set-Cookie"), tt.expectedCookie)
+		})
+	}
+}
+
+// TestCanaryShare tests that the requests are split by share, and by the sticky header when given
+func TestCanaryShare(t *testing.T) {
+	mux := r.NewRoute("/search").Add(
+		r.Canary(http.MethodGet, handlerWriter("stable"), handlerWriter("canary"), r.CanaryPercent(20), r.CanaryStickyHeader("X-User")),
+	).Mount()
+	serve := func(user string) string {
+		req := httptest.NewRequest(http.MethodGet, "/search", nil)
+		if user != "" {
+			req.Header.Set("X-User", user)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	canaries := 0
+	for range 2000 {
+		if serve("") == "canary" {
+			canaries++
+		}
+	}
+	if canaries < 300 || canaries > 500 {
+		t.Errorf("got %d canary requests out of 2000, want about 400", canaries)
+	}
+
+	for i := range 50 {
+		user := fmt.Sprint("user", i)
+		first := serve(user)
+		for range 5 {
+			assertCorrect(t, serve(user), first)
+		}
+	}
+}
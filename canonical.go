@@ -0,0 +1,67 @@
+package simplerouter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// canonicalKey is the context key storing the canonical URL of a request.
+type canonicalKey struct{}
+
+// Canonical declares the canonical version of the route and its child routes, for routes serving
+// duplicated content like aliases or legacy paths. The canonical version is given as a route name
+// (see [Route.Name]) or as a path, and its wildcards are filled with the values of the matched request.
+// Once mounted, responses carry a `Link: <url>; rel="canonical"` header and the URL is available
+// to handlers and templates through [CanonicalURL].
+func (r *Route) Canonical(nameOrPath string) *Route {
+	if nameOrPath == "" {
+		panic("nameOrPath parameter cannot be empty")
+	}
+	r.Metadata.Canonical = nameOrPath
+	return r
+}
+
+// CanonicalURL returns the canonical URL of the request declared with [Route.Canonical],
+// or an empty string if the matched route has none.
+// It is meant to be used when rendering templates, e.g. to write the `<link rel="canonical">` tag.
+func CanonicalURL(r *http.Request) string {
+	url, _ := r.Context().Value(canonicalKey{}).(string)
+	return url
+}
+
+// canonicalPath resolves the canonical version of a route into a path, including the base path.
+// It panics if the canonical version is a route name not defined in the mounted tree.
+func (m *mounter) canonicalPath(nameOrPath string) string {
+	if strings.HasPrefix(nameOrPath, "/") {
+		return m.basePath + nameOrPath
+	}
+
+	path, ok := m.paths[nameOrPath]
+	if !ok {
+		panic(fmt.Sprintf("canonical route name %q is not defined", nameOrPath))
+	}
+	return path
+}
+
+// canonical returns a Middleware that sets the canonical Link header for the path,
+// filling its wildcards with the path values of the request.
+func canonical(path string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			url, err := buildPath(path, func(key string) (string, bool) {
+				value := r.PathValue(key)
+				return value, value != ""
+			})
+			if err != nil {
+				// The canonical version needs values the request doesn't have.
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Link", "<"+url+`>; rel="canonical"`)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), canonicalKey{}, url)))
+		})
+	}
+}
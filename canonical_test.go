@@ -0,0 +1,64 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// canonicalWriter creates a handler that writes the canonical URL of the request
+func canonicalWriter(w http.ResponseWriter, req *http.Request) {
+	w.Write([]byte(r.CanonicalURL(req)))
+}
+
+// TestCanonical tests the canonical Link header and URL of mounted routes
+func TestCanonical(t *testing.T) {
+	mux := r.NewRoute("").Add(
+		r.NewRoute("/products/{id}").Name("product").Add(r.Get(canonicalWriter)),
+		r.NewRoute("/items/{id}").Canonical("product").Add(r.Get(canonicalWriter)),
+		r.NewRoute("/legacy").Canonical("/products/{id}").Add(
+			r.NewRoute("/item/{id}").Add(r.Get(canonicalWriter)),
+			r.NewRoute("/list").Add(r.Get(canonicalWriter)),
+		),
+		r.NewRoute("/home").Canonical("/").Add(r.Get(canonicalWriter)),
+	).Mount()
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedLink string
+		expectedBody string
+	}{
+		{name: "canonical route", path: "/products/42", expectedLink: "", expectedBody: ""},
+		{name: "canonical by name", path: "/items/42", expectedLink: `</products/42>; rel="canonical"`, expectedBody: "/products/42"},
+		{name: "canonical by path inherited", path: "/legacy/item/7", expectedLink: `</products/7>; rel="canonical"`, expectedBody: "/products/7"},
+		{name: "canonical missing wildcard value", path: "/legacy/list", expectedLink: "", expectedBody: ""},
+		{name: "canonical static path", path: "/home", expectedLink: `</>; rel="canonical"`, expectedBody: "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, http.StatusOK)
+			assertCorrect(t, w.Header().Get("Link"), tt.expectedLink)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+// TestCanonicalWithUndefinedName tests that mounting a canonical route name not defined causes a panic
+func TestCanonicalWithUndefinedName(t *testing.T) {
+	route := r.NewRoute("/items/{id}").Canonical("product").Add(r.Get(canonicalWriter))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Mount() with undefined canonical name to panic, but it didn't")
+		}
+	}()
+
+	route.Mount()
+}
@@ -0,0 +1,44 @@
+package simplerouter
+
+import "net/http"
+
+// Chain is a reusable stack of middlewares in execution order, defined once and attached to several
+// unrelated subtrees, as it is a slice of middlewares that can be passed directly to [Route.Use]:
+//
+//	secured := simplerouter.NewChain(middleware.Recover(), middleware.Logger(nil), auth)
+//	admin.Use(secured...)
+//	billing.Use(secured.Append(audit)...)
+//
+// Append and Extend never modify the chain they are called on, so a chain can be shared safely.
+type Chain []Middleware
+
+// NewChain returns a Chain with the given middlewares. It panics if they contain a nil middleware.
+func NewChain(middlewares ...Middleware) Chain {
+	return Chain{}.Append(middlewares...)
+}
+
+// Append returns a new Chain with the middlewares of c followed by the given ones.
+// It panics if they contain a nil middleware.
+func (c Chain) Append(middlewares ...Middleware) Chain {
+	for _, mw := range middlewares {
+		if mw == nil {
+			panic(ErrNilMiddleware.Error())
+		}
+	}
+	chain := make(Chain, 0, len(c)+len(middlewares))
+	return append(append(chain, c...), middlewares...)
+}
+
+// Extend returns a new Chain with the middlewares of c followed by the ones of other.
+func (c Chain) Extend(other Chain) Chain {
+	return c.Append(other...)
+}
+
+// Then returns h wrapped by the middlewares of the chain, the first one being the outermost,
+// to use the chain outside of a route tree. It panics if h is nil.
+func (c Chain) Then(h http.Handler) http.Handler {
+	if h == nil {
+		panic("h parameter cannot be nil")
+	}
+	return applyMiddleware(c...)(h)
+}
@@ -0,0 +1,61 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestChain tests the execution order of composed chains used with Use and Then
+func TestChain(t *testing.T) {
+	tracker := []string{}
+	base := r.NewChain(middlewareTracker("recover", &tracker), middlewareTracker("logger", &tracker))
+	secured := base.Extend(r.NewChain(middlewareTracker("auth", &tracker)))
+	audited := secured.Append(middlewareTracker("audit", &tracker))
+
+	tests := []struct {
+		name             string
+		handler          http.Handler
+		expectedTracking []string
+	}{
+		{
+			name:             "then",
+			handler:          base.Then(handlerWriter("ok")),
+			expectedTracking: []string{"recover", "logger"},
+		},
+		{
+			name:             "used by a route",
+			handler:          r.NewRoute("/").Use(audited...).Add(r.Get(handlerWriter("ok"))).Mount(),
+			expectedTracking: []string{"recover", "logger", "auth", "audit"},
+		},
+		{
+			name:             "shared chain left untouched",
+			handler:          r.NewRoute("/").Use(secured...).Add(r.Get(handlerWriter("ok"))).Mount(),
+			expectedTracking: []string{"recover", "logger", "auth"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker = tracker[:0]
+			tt.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			if !reflect.DeepEqual(tracker, tt.expectedTracking) {
+				t.Errorf("Middlewares executed = %v, want %v", tracker, tt.expectedTracking)
+			}
+		})
+	}
+}
+
+// TestChainWithNilMiddleware tests that nil middlewares cause a panic
+func TestChainWithNilMiddleware(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected NewChain() with nil middleware to panic, but it didn't")
+		}
+	}()
+
+	r.NewChain(middlewareTracker("m1", &[]string{}), nil)
+}
@@ -0,0 +1,43 @@
+package simplerouter
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the route tree: the route and its child routes are copied with their
+// paths, aliases, middlewares and metadata, so a common skeleton can be added under several paths and
+// modified independently afterwards:
+//
+//	skeleton := simplerouter.NewRoute("").Add(users, orders)
+//	router.Add(
+//		simplerouter.NewRoute("/v1").Add(skeleton),
+//		simplerouter.NewRoute("/v2").Add(skeleton.Clone().Add(invoices)),
+//	)
+//
+// Middlewares and handlers are functions, so the copies share them, as well as the values stored
+// with [Route.Meta] and the examples, which are shallow copied. The copies are never frozen, see [WithFreeze].
+// As with [Route.With], the copies do not keep the names of the routes, which identify a single route
+// of the tree, so the copies added to the same tree as the original are not duplicate names; name
+// them again with [Route.Name] to look them up.
+func (r *Route) Clone() *Route {
+	clone := *r
+	clone.frozen = false
+	clone.Metadata.Name = ""
+	clone.Aliases = slices.Clone(r.Aliases)
+	clone.Middlewares = slices.Clone(r.Middlewares)
+	clone.sources = slices.Clone(r.sources)
+	clone.buildEvents = slices.Clone(r.buildEvents)
+	clone.Metadata.Tags = slices.Clone(r.Metadata.Tags)
+	clone.Metadata.Examples = slices.Clone(r.Metadata.Examples)
+	clone.Metadata.Responses = slices.Clone(r.Metadata.Responses)
+	clone.Metadata.Assets = slices.Clone(r.Metadata.Assets)
+	clone.Metadata.Values = maps.Clone(r.Metadata.Values)
+	clone.Metadata.Locales = maps.Clone(r.Metadata.Locales)
+
+	clone.Routes = make([]*Route, len(r.Routes))
+	for i, route := range r.Routes {
+		clone.Routes[i] = route.Clone()
+	}
+	return &clone
+}
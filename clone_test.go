@@ -0,0 +1,49 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestClone tests that cloned trees can be modified independently of the original
+func TestClone(t *testing.T) {
+	skeleton := r.NewRoute("").Add(
+		r.NewRoute("/users").Name("users").Meta("version", "1").Add(r.Get(handlerWriter("users"))),
+	)
+	clone := skeleton.Clone()
+	clone.Routes[0].Meta("version", "2").Add(r.NewRoute("/{id}").Add(r.Get(handlerWriter("user"))))
+	clone.Add(r.NewRoute("/invoices").Add(r.Get(handlerWriter("invoices"))))
+	clone.Routes[0].Path = "/people"
+
+	mux := r.NewRoute("").Add(
+		r.NewRoute("/v1").Add(skeleton),
+		r.NewRoute("/v2").Add(clone),
+	).Mount()
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/v1/users", expectedStatus: http.StatusOK},
+		{path: "/v1/users/1", expectedStatus: http.StatusNotFound},
+		{path: "/v1/invoices", expectedStatus: http.StatusNotFound},
+		{path: "/v2/people", expectedStatus: http.StatusOK},
+		{path: "/v2/people/1", expectedStatus: http.StatusOK},
+		{path: "/v2/invoices", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assertCorrect(t, w.Code, tt.expectedStatus)
+		})
+	}
+	assertCorrect(t, skeleton.Routes[0].Metadata.Values["version"], "1")
+	assertCorrect(t, clone.Routes[0].Metadata.Values["version"], "2")
+	assertCorrect(t, skeleton.Routes[0].Metadata.Name, "users")
+	assertCorrect(t, clone.Routes[0].Metadata.Name, "")
+}
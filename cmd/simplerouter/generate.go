@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// openAPIOperation is the part of an OpenAPI operation used to generate its route.
+type openAPIOperation struct {
+	OperationID string   `json:"operationId"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Deprecated  bool     `json:"deprecated"`
+}
+
+// openAPIDocument is the part of an OpenAPI document used to generate its route tree.
+type openAPIDocument struct {
+	Info struct {
+		Title string `json:"title"`
+	} `json:"info"`
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// operationMethods maps the operation fields of an OpenAPI path item to the route constructors of their methods.
+var operationMethods = map[string]string{
+	"get":     "Get",
+	"head":    "Head",
+	"post":    "Post",
+	"put":     "Put",
+	"patch":   "Patch",
+	"delete":  "Delete",
+	"options": "Options",
+	"trace":   "Trace",
+}
+
+// generatedOperation is an operation of the generated file.
+type generatedOperation struct {
+	openAPIOperation
+	Method      string
+	Path        string
+	Func        string
+	Constructor string
+}
+
+// generatedPath is a path of the generated route tree with its operations.
+type generatedPath struct {
+	Path       string
+	Operations []generatedOperation
+}
+
+// generatedFile is the template of the generated Go file.
+var generatedFile = template.Must(template.New("routes.go").Funcs(template.FuncMap{
+	"line": func(s string) string { return strings.Join(strings.Fields(s), " ") },
+}).Parse(`// Code generated by simplerouter generate from {{.Spec}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"net/http"
+
+	"github.com/carlos-el/simplerouter"
+)
+
+// Handlers serves the operations of the {{.Title}} API.
+type Handlers interface {
+{{- range .Operations}}
+	// {{.Func}} serves {{.Method}} {{.Path}}{{with line .Summary}}: {{.}}{{end}}
+	{{.Func}}(w http.ResponseWriter, r *http.Request)
+{{- end}}
+}
+
+// UnimplementedHandlers answers every operation with a 501 Not Implemented. It can be embedded by the
+// implementations of Handlers serving only some operations.
+type UnimplementedHandlers struct{}
+{{range .Operations}}
+// {{.Func}} answers with a 501 Not Implemented.
+func (UnimplementedHandlers) {{.Func}}(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, http.StatusText(http.StatusNotImplemented), http.StatusNotImplemented)
+}
+{{end}}
+// Routes returns the route tree of the API, serving its operations with h.
+func Routes(h Handlers) *simplerouter.Route {
+	return simplerouter.NewRoute("").Add(
+{{- range .Paths}}
+		simplerouter.NewRoute({{printf "%q" .Path}}).Add(
+{{- range .Operations}}
+			simplerouter.{{.Constructor}}(h.{{.Func}})
+{{- with .OperationID}}.Name({{printf "%q" .}}){{end}}
+{{- if or .Summary .Description}}.Describe({{printf "%q" .Summary}}, {{printf "%q" .Description}}){{end}}
+{{- with .Tags}}.Tags({{range $i, $tag := .}}{{if $i}}, {{end}}{{printf "%q" $tag}}{{end}}){{end}}
+{{- if .Deprecated}}.Deprecated(){{end}},
+{{- end}}
+		),
+{{- end}}
+	)
+}
+`))
+
+// runGenerate runs the generate subcommand with its arguments.
+func runGenerate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	pkg := flags.String("package", "api", "package name of the generated file")
+	output := flags.String("o", "", "file to write the generated code to, instead of the standard output")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: simplerouter generate [-package name] [-o file] openapi.json")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	spec, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	source, err := generate(spec, filepath.Base(flags.Arg(0)), *pkg)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return os.WriteFile(*output, source, 0o644)
+}
+
+// generate returns the Go source declaring the handlers and the route tree of the OpenAPI document spec,
+// read from the named file, in the given package.
+func generate(spec []byte, name, pkg string) ([]byte, error) {
+	var doc openAPIDocument
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", name, err)
+	}
+
+	var (
+		paths      []generatedPath
+		operations []generatedOperation
+		funcs      = map[string]string{}
+	)
+	for _, specPath := range slices.Sorted(maps.Keys(doc.Paths)) {
+		// OpenAPI paths match exactly, while http.ServeMux patterns ending in a slash match prefixes.
+		path := specPath
+		if strings.HasSuffix(path, "/") {
+			path += "{$}"
+		}
+		generated := generatedPath{Path: path}
+
+		item := doc.Paths[specPath]
+		for _, field := range []string{"get", "head", "post", "put", "patch", "delete", "options", "trace"} {
+			raw, ok := item[field]
+			if !ok {
+				continue
+			}
+			op := generatedOperation{Method: strings.ToUpper(field), Path: specPath, Constructor: operationMethods[field]}
+			if err := json.Unmarshal(raw, &op.openAPIOperation); err != nil {
+				return nil, fmt.Errorf("decoding %s %s: %w", op.Method, specPath, err)
+			}
+			op.Func = goName(op.OperationID)
+			if op.OperationID == "" {
+				op.Func = goName(field + " " + specPath)
+			}
+			if other, found := funcs[op.Func]; found {
+				return nil, fmt.Errorf("operations %s and %s %s are both named %s", other, op.Method, specPath, op.Func)
+			}
+			funcs[op.Func] = op.Method + " " + specPath
+
+			generated.Operations = append(generated.Operations, op)
+			operations = append(operations, op)
+		}
+		if len(generated.Operations) > 0 {
+			paths = append(paths, generated)
+		}
+	}
+
+	var b bytes.Buffer
+	generatedFile.Execute(&b, map[string]any{
+		"Spec":       name,
+		"Package":    pkg,
+		"Title":      doc.Info.Title,
+		"Operations": operations,
+		"Paths":      paths,
+	})
+	return format.Source(b.Bytes())
+}
+
+// goName returns an exported Go identifier for s, capitalizing each of its words,
+// like "GetUsersId" for "get /users/{id}".
+func goName(s string) string {
+	var b strings.Builder
+	for word := range strings.FieldsFuncSeq(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		runes := []rune(word)
+		b.WriteString(string(unicode.ToUpper(runes[0])) + string(runes[1:]))
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "Op" + name
+	}
+	return name
+}
@@ -0,0 +1,251 @@
+// Command simplerouter prints the route table of a program, built by calling one of its functions returning
+// the route tree, to publish it as a CI artifact or to review route changes in pull requests:
+//
+//	$ simplerouter -format markdown ./internal/api.NewRouter
+//
+// The function must be exported by a package other than main and take no parameters, like
+// "func NewRouter() *simplerouter.Route". It is called by a temporary program built with "go run"
+// in the module of its package, so it should not start servers or connect to databases.
+// The table lists the method, path, aliases, name, middlewares and handler of every endpoint in text,
+// JSON or Markdown format. It can be kept up to date with go:generate:
+//
+//	//go:generate go run github.com/carlos-el/simplerouter/cmd/simplerouter -o routes.md -format markdown .NewRouter
+//
+// The generate subcommand works the other way around for spec-first APIs: it reads an OpenAPI document
+// in JSON format and writes a Go file declaring a Handlers interface with a method for each operation,
+// an UnimplementedHandlers type answering them with a 501 Not Implemented, and a Routes function
+// returning the route tree serving them, named after their operation IDs and described by their metadata:
+//
+//	//go:generate go run github.com/carlos-el/simplerouter/cmd/simplerouter generate -package api -o routes_gen.go openapi.json
+//
+// Regenerating the file after changing the document keeps the routes in sync with it, as implementations
+// stop compiling when operations are added or renamed.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// endpoint is an endpoint of the route table, as encoded by the generated program.
+type endpoint struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Middlewares []string `json:"middlewares,omitempty"`
+	Handler     string   `json:"handler"`
+}
+
+// formats are the formats of the route table.
+var formats = []string{"text", "json", "markdown"}
+
+// program is the temporary program calling the function and encoding its endpoints as JSON.
+var program = template.Must(template.New("main.go").Parse(`// Code generated by simplerouter. DO NOT EDIT.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	sr "github.com/carlos-el/simplerouter"
+	target {{printf "%q" .ImportPath}}
+)
+
+func main() {
+	var route *sr.Route = target.{{.Func}}()
+	endpoints := []map[string]any{}
+	for _, e := range route.Endpoints() {
+		middlewares := []string{}
+		for _, mw := range e.Middlewares {
+			middlewares = append(middlewares, sr.FuncName(mw))
+		}
+		endpoints = append(endpoints, map[string]any{
+			"method": e.Method, "path": e.Path, "aliases": e.Aliases, "name": e.Metadata.Name,
+			"middlewares": middlewares, "handler": sr.FuncName(e.Handler),
+		})
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(endpoints); err != nil {
+		os.Exit(1)
+	}
+}
+`))
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := runGenerate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "simplerouter generate:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	format := flag.String("format", "text", "format of the route table: text, json or markdown")
+	output := flag.String("o", "", "file to write the route table to, instead of the standard output")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: simplerouter [-format text|json|markdown] [-o file] package.Func")
+		fmt.Fprintln(flag.CommandLine.Output(), "       simplerouter generate [-package name] [-o file] openapi.json")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *format, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "simplerouter:", err)
+		os.Exit(1)
+	}
+}
+
+// run prints the route table of the function in the given format to the output file, or to the standard output.
+func run(target, format, output string) error {
+	if !slices.Contains(formats, format) {
+		return fmt.Errorf("unknown format %s, want %s", format, strings.Join(formats, ", "))
+	}
+	pkg, fn, err := splitTarget(target)
+	if err != nil {
+		return err
+	}
+	endpoints, err := loadEndpoints(pkg, fn)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := writeTable(&b, endpoints, format); err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(b.Bytes())
+		return err
+	}
+	return os.WriteFile(output, b.Bytes(), 0o644)
+}
+
+// splitTarget splits "package.Func" into the package pattern and the function name. An empty package,
+// like in ".NewRouter", is the package in the current directory.
+func splitTarget(target string) (pkg, fn string, err error) {
+	i := strings.LastIndex(target, ".")
+	if i <= strings.LastIndex(target, "/") || i == len(target)-1 {
+		return "", "", fmt.Errorf("invalid target %q, want package.Func", target)
+	}
+	pkg, fn = target[:i], target[i+1:]
+	if pkg == "" {
+		pkg = "."
+	}
+	return pkg, fn, nil
+}
+
+// loadEndpoints builds and runs a temporary program calling the function of the package, in its module,
+// and decodes the endpoints it prints.
+func loadEndpoints(pkg, fn string) ([]endpoint, error) {
+	list, err := goCommand("", "list", "-f", "{{.ImportPath}} {{with .Module}}{{.Dir}}{{end}}", pkg)
+	if err != nil {
+		return nil, err
+	}
+	importPath, moduleDir, _ := strings.Cut(strings.TrimSpace(list), " ")
+	if moduleDir == "" {
+		return nil, fmt.Errorf("package %s is not in a module", importPath)
+	}
+
+	// The program is built inside the module, so it can import its internal packages. Directories starting
+	// with an underscore are ignored by the "./..." patterns of other commands run in the meantime.
+	dir, err := os.MkdirTemp(moduleDir, "_simplerouter")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	var source bytes.Buffer
+	program.Execute(&source, map[string]string{"ImportPath": importPath, "Func": fn})
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), source.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+
+	out, err := goCommand(moduleDir, "run", "./"+filepath.Base(dir))
+	if err != nil {
+		return nil, err
+	}
+	var endpoints []endpoint
+	if err := json.Unmarshal([]byte(out), &endpoints); err != nil {
+		return nil, fmt.Errorf("decoding the endpoints of %s.%s: %w", importPath, fn, err)
+	}
+	return endpoints, nil
+}
+
+// goCommand runs the go command with the arguments in dir, returning its standard output.
+func goCommand(dir string, args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go %s: %w\n%s", args[0], err, stderr.String())
+	}
+	return string(out), nil
+}
+
+// writeTable writes the route table of the endpoints to w in one of the formats.
+func writeTable(w io.Writer, endpoints []endpoint, format string) error {
+	switch format {
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "METHOD\tPATH\tNAME\tMIDDLEWARES\tHANDLER")
+		for _, e := range endpoints {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", methodName(e.Method), strings.Join(append([]string{e.Path}, e.Aliases...), ","),
+				e.Name, strings.Join(e.Middlewares, ","), e.Handler)
+		}
+		return tw.Flush()
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(endpoints)
+	case "markdown":
+		fmt.Fprintln(w, "| Method | Path | Name | Middlewares | Handler |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+		for _, e := range endpoints {
+			cells := []string{
+				methodName(e.Method),
+				codeList(append([]string{e.Path}, e.Aliases...)),
+				e.Name,
+				codeList(e.Middlewares),
+				codeList([]string{e.Handler}),
+			}
+			for i, cell := range cells {
+				cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+	return nil
+}
+
+// methodName returns the name used to display an HTTP method, "ALL" for endpoints matching every method.
+func methodName(method string) string {
+	if method == "" {
+		return "ALL"
+	}
+	return method
+}
+
+// codeList formats the values as code, one per line of a Markdown table cell.
+func codeList(values []string) string {
+	for i, value := range values {
+		values[i] = "`" + value + "`"
+	}
+	return strings.Join(values, "<br>")
+}
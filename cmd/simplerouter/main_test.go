@@ -0,0 +1,174 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func assertCorrect(t testing.TB, got, want any) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+// TestSplitTarget tests the package and function names of the targets
+func TestSplitTarget(t *testing.T) {
+	tests := []struct {
+		target      string
+		expectedPkg string
+		expectedFn  string
+		fails       bool
+	}{
+		{target: "./internal/api.NewRouter", expectedPkg: "./internal/api", expectedFn: "NewRouter"},
+		{target: "example.com/api.v2.NewRouter", expectedPkg: "example.com/api.v2", expectedFn: "NewRouter"},
+		{target: ".NewRouter", expectedPkg: ".", expectedFn: "NewRouter"},
+		{target: "NewRouter", fails: true},
+		{target: "./internal/api", fails: true},
+		{target: "./api.", fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			pkg, fn, err := splitTarget(tt.target)
+			assertCorrect(t, err != nil, tt.fails)
+			assertCorrect(t, pkg, tt.expectedPkg)
+			assertCorrect(t, fn, tt.expectedFn)
+		})
+	}
+}
+
+// TestRun tests the route tables printed for the function of a package in every format
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program with the go command")
+	}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: "text", expected: "" +
+			"METHOD  PATH                              NAME  MIDDLEWARES  HANDLER\n" +
+			"GET     /api/users                              routes.auth  routes.listUsers\n" +
+			"GET     /api/users/{id},/api/people/{id}  user  routes.auth  routes.getUser\n"},
+		{format: "markdown", expected: "" +
+			"| Method | Path | Name | Middlewares | Handler |\n" +
+			"| --- | --- | --- | --- | --- |\n" +
+			"| GET | `/api/users` |  | `routes.auth` | `routes.listUsers` |\n" +
+			"| GET | `/api/users/{id}`<br>`/api/people/{id}` | user | `routes.auth` | `routes.getUser` |\n"},
+		{format: "json", expected: `[
+  {
+    "method": "GET",
+    "path": "/api/users",
+    "middlewares": [
+      "routes.auth"
+    ],
+    "handler": "routes.listUsers"
+  },
+  {
+    "method": "GET",
+    "path": "/api/users/{id}",
+    "aliases": [
+      "/api/people/{id}"
+    ],
+    "name": "user",
+    "middlewares": [
+      "routes.auth"
+    ],
+    "handler": "routes.getUser"
+  }
+]
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "routes")
+			if err := run("./testdata/routes.NewRouter", tt.format, output); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertCorrect(t, string(data), tt.expected)
+		})
+	}
+
+	err := run("./testdata/routes.NewRouter", "yaml", filepath.Join(t.TempDir(), "routes"))
+	if err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Errorf("run() with yaml format = %v, want an unknown format error", err)
+	}
+}
+
+// TestGenerate tests the Go file generated from an OpenAPI document, building it with a program serving its routes
+func TestGenerate(t *testing.T) {
+	spec, err := os.ReadFile("testdata/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := generate(spec, "openapi.json", "users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		"// ListUsers serves GET /users/: List the users\n\tListUsers(w http.ResponseWriter, r *http.Request)\n",
+		"\t// PostUsers serves POST /users/: Create a user\n",
+		"func (UnimplementedHandlers) DeleteUser(w http.ResponseWriter, r *http.Request) {\n",
+		"\t\tsimplerouter.NewRoute(\"/users/{$}\").Add(\n" +
+			"\t\t\tsimplerouter.Get(h.ListUsers).Name(\"listUsers\").Describe(\"List the users\", \"Lists the users\\nby name.\"),\n" +
+			"\t\t\tsimplerouter.Post(h.PostUsers).Describe(\"Create a user\", \"\").Tags(\"users\", \"admin\"),\n",
+		"\t\t\tsimplerouter.Delete(h.DeleteUser).Name(\"delete-user\").Deprecated(),\n",
+	} {
+		if !strings.Contains(string(source), line) {
+			t.Errorf("generated source does not contain %q:\n%s", line, source)
+		}
+	}
+
+	if testing.Short() {
+		return
+	}
+	// The generated package is built inside the module, to import its root package.
+	dir, err := os.MkdirTemp("../..", "_generate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.WriteFile(filepath.Join(dir, "routes_gen.go"), source, 0o644)
+	os.WriteFile(filepath.Join(dir, "handlers.go"), []byte(`package users
+
+import "net/http"
+
+type handlers struct{ UnimplementedHandlers }
+
+func (handlers) GetUser(w http.ResponseWriter, r *http.Request) { w.Write([]byte(r.PathValue("id"))) }
+
+var _ = Routes(handlers{}).Mount()
+`), 0o644)
+	if _, err := goCommand(dir, "vet", "."); err != nil {
+		t.Error(err)
+	}
+
+	_, err = generate([]byte(`{"paths": {"/a": {"get": {"operationId": "op"}}, "/b": {"get": {"operationId": "Op"}}}}`), "openapi.json", "api")
+	if err == nil || !strings.Contains(err.Error(), "both named Op") {
+		t.Errorf("generate() with duplicated names = %v, want a duplicated name error", err)
+	}
+}
+
+// TestGoName tests the Go identifiers of the operations
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"listUsers":        "ListUsers",
+		"get-user_by.id":   "GetUserById",
+		"get /users/{id}/": "GetUsersId",
+		"2fa":              "Op2fa",
+		"":                 "Op",
+	}
+	for s, expected := range tests {
+		assertCorrect(t, goName(s), expected)
+	}
+}
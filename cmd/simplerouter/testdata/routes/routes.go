@@ -0,0 +1,22 @@
+// Package routes is a route tree printed by the tests of the simplerouter command.
+package routes
+
+import (
+	"net/http"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+func auth(next http.Handler) http.Handler { return next }
+
+func listUsers(w http.ResponseWriter, req *http.Request) {}
+
+func getUser(w http.ResponseWriter, req *http.Request) {}
+
+// NewRouter returns the route tree.
+func NewRouter() *r.Route {
+	return r.NewRoute("/api").Use(auth).Add(
+		r.NewRoute("/users").Add(r.Get(listUsers)),
+		r.NewRoute("/users/{id}").Alias("/people/{id}").Add(r.Get(getUser).Name("user")),
+	)
+}
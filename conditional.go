@@ -0,0 +1,49 @@
+package simplerouter
+
+import "net/http"
+
+// UseIf adds middlewares that execute before the route's handlers or child routes only for the requests
+// for which cond returns true, the other requests go straight to the next handler. It allows bypassing
+// a logger or an authentication middleware for health checks or internal clients without restructuring
+// the tree. It panics if cond is nil or the middlewares contain a nil middleware.
+func (r *Route) UseIf(cond func(r *http.Request) bool, middlewares ...Middleware) *Route {
+	if cond == nil {
+		panic("cond parameter cannot be nil")
+	}
+	conditional := make([]Middleware, len(middlewares))
+	for i, mw := range middlewares {
+		if mw == nil {
+			panic(ErrNilMiddleware.Error())
+		}
+		conditional[i] = useIf(cond, mw)
+	}
+	return r.Use(conditional...)
+}
+
+// useIf returns a Middleware applying mw to the requests for which cond returns true.
+func useIf(cond func(r *http.Request) bool, mw Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cond(r) {
+				wrapped.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// UseFor adds middlewares that execute before the route's handlers or child routes only for the requests
+// with the given method, like a body validation middleware needed by POST and PUT requests but not by
+// GET ones. Middlewares for GET also execute for HEAD requests, as http.ServeMux serves them with GET
+// handlers. The middlewares keep their position in the chain, see [Route.UseIf].
+// It panics if method is empty or the middlewares contain a nil middleware.
+func (r *Route) UseFor(method string, middlewares ...Middleware) *Route {
+	if method == "" {
+		panic("method parameter cannot be empty")
+	}
+	return r.UseIf(func(req *http.Request) bool {
+		return req.Method == method || (method == http.MethodGet && req.Method == http.MethodHead)
+	}, middlewares...)
+}
@@ -0,0 +1,98 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestUseIf tests that conditional middlewares only execute for the requests matching their condition
+func TestUseIf(t *testing.T) {
+	var tracker []string
+	external := func(req *http.Request) bool { return req.Header.Get("X-Internal") == "" }
+	route := r.NewRoute("/").
+		Use(middlewareTracker("logger", &tracker)).
+		UseIf(external, middlewareTracker("auth", &tracker)).
+		Add(r.Get(handlerWriter("ok")))
+	mux := route.Mount()
+
+	tests := []struct {
+		name             string
+		internal         string
+		expectedTracking []string
+	}{
+		{name: "external request", expectedTracking: []string{"logger", "auth"}},
+		{name: "internal request", internal: "1", expectedTracking: []string{"logger"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker = nil
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.internal != "" {
+				req.Header.Set("X-Internal", tt.internal)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assertCorrect(t, w.Body.String(), "ok")
+			assertCorrect(t, len(tracker), len(tt.expectedTracking))
+			for i, name := range tt.expectedTracking {
+				assertCorrect(t, tracker[i], name)
+			}
+		})
+	}
+}
+
+// TestUseIfWithNilParameters tests that nil conditions and middlewares cause a panic
+func TestUseIfWithNilParameters(t *testing.T) {
+	always := func(*http.Request) bool { return true }
+	for name, use := range map[string]func(){
+		"nil condition":  func() { r.NewRoute("/").UseIf(nil, middlewareTracker("a", new([]string))) },
+		"nil middleware": func() { r.NewRoute("/").UseIf(always, nil) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("UseIf() did not panic")
+				}
+			}()
+			use()
+		})
+	}
+}
+
+// TestUseFor tests that per-method middlewares only execute for the requests with their method
+func TestUseFor(t *testing.T) {
+	var tracker []string
+	route := r.NewRoute("/users").
+		Use(middlewareTracker("logger", &tracker)).
+		UseFor(http.MethodPost, middlewareTracker("validate", &tracker)).
+		UseFor(http.MethodGet, middlewareTracker("cache", &tracker)).
+		Add(r.Get(handlerWriter("users")), r.Post(handlerWriter("created")))
+	mux := route.Mount()
+
+	tests := []struct {
+		method           string
+		expectedTracking []string
+	}{
+		{method: http.MethodGet, expectedTracking: []string{"logger", "cache"}},
+		{method: http.MethodHead, expectedTracking: []string{"logger", "cache"}},
+		{method: http.MethodPost, expectedTracking: []string{"logger", "validate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			tracker = nil
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, "/users", nil))
+
+			assertCorrect(t, len(tracker), len(tt.expectedTracking))
+			for i, name := range tt.expectedTracking {
+				assertCorrect(t, tracker[i], name)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package simplerouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RouteConfig declares a route of a tree built by [HandlerRegistry.Build] from a configuration file,
+// referencing its handlers and middlewares by name, so the paths, prefixes and redirects of a service
+// like a gateway can change without recompiling it:
+//
+//	{
+//		"path": "/api",
+//		"middlewares": ["auth"],
+//		"routes": [
+//			{"path": "/users", "handlers": {"GET": "listUsers", "POST": "createUser"}},
+//			{"path": "/orders/", "handlers": {"ALL": "proxy:orders"}},
+//			{"path": "/v1/", "redirect": "/api/"}
+//		]
+//	}
+//
+// Its fields are tagged for encoding/json; YAML files can be decoded into it with any YAML library
+// honoring the same tags, like the ones converting YAML to JSON first.
+type RouteConfig struct {
+	// Path is the path of the route, relative to its parent.
+	Path string `json:"path" yaml:"path"`
+	// Aliases are the additional paths of the route, see [Route.Alias].
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// Name is the name of the route, see [Route.Name].
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Middlewares are the names of the middlewares of the route, in execution order.
+	Middlewares []string `json:"middlewares,omitempty" yaml:"middlewares,omitempty"`
+	// Handlers maps the methods served by the route to the names of their handlers,
+	// "ALL" serving the methods without their own handler.
+	Handlers map[string]string `json:"handlers,omitempty" yaml:"handlers,omitempty"`
+	// Redirect is the URL the requests of all methods are redirected to, instead of being handled.
+	Redirect string `json:"redirect,omitempty" yaml:"redirect,omitempty"`
+	// RedirectStatus is the status code of the redirect, 308 Permanent Redirect if zero.
+	RedirectStatus int `json:"redirectStatus,omitempty" yaml:"redirectStatus,omitempty"`
+	// Routes are the child routes.
+	Routes []RouteConfig `json:"routes,omitempty" yaml:"routes,omitempty"`
+}
+
+// HandlerRegistry holds the handlers and middlewares referenced by name in a [RouteConfig].
+// It is safe for concurrent use.
+type HandlerRegistry struct {
+	mu          sync.RWMutex
+	handlers    map[string]http.HandlerFunc
+	factories   map[string]func(arg string) (http.HandlerFunc, error)
+	middlewares map[string]Middleware
+}
+
+// NewHandlerRegistry returns an empty [HandlerRegistry].
+func NewHandlerRegistry() *HandlerRegistry {
+	return &HandlerRegistry{
+		handlers:    map[string]http.HandlerFunc{},
+		factories:   map[string]func(arg string) (http.HandlerFunc, error){},
+		middlewares: map[string]Middleware{},
+	}
+}
+
+// Handle registers the handler under the given name. It panics if name is empty or handler is nil.
+func (reg *HandlerRegistry) Handle(name string, handler http.HandlerFunc) *HandlerRegistry {
+	if name == "" || handler == nil {
+		panic("name and handler parameters cannot be empty")
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.handlers[name] = handler
+	return reg
+}
+
+// HandlePrefix registers a factory building the handlers named "prefix:arg", called with arg by
+// [HandlerRegistry.Build], so configs can reference handlers that are not known in advance, like
+// the proxies to the upstreams of a gateway:
+//
+//	reg.HandlePrefix("proxy", func(name string) (http.HandlerFunc, error) {
+//		return upstreams.Proxy(name), nil
+//	})
+//
+// It panics if prefix is empty or factory is nil.
+func (reg *HandlerRegistry) HandlePrefix(prefix string, factory func(arg string) (http.HandlerFunc, error)) *HandlerRegistry {
+	if prefix == "" || factory == nil {
+		panic("prefix and factory parameters cannot be empty")
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.factories[prefix] = factory
+	return reg
+}
+
+// Middleware registers the middleware under the given name. It panics if name is empty or middleware is nil.
+func (reg *HandlerRegistry) Middleware(name string, middleware Middleware) *HandlerRegistry {
+	if name == "" || middleware == nil {
+		panic("name and middleware parameters cannot be empty")
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.middlewares[name] = middleware
+	return reg
+}
+
+// LoadRoutes reads the JSON file at path and builds its route tree with [HandlerRegistry.Build].
+func (reg *HandlerRegistry) LoadRoutes(path string) (*Route, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config RouteConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return reg.Build(config)
+}
+
+// Build builds the route tree declared by config. It returns an error naming the path of the route
+// if a handler or middleware is not registered, or if a route both redirects and has handlers.
+func (reg *HandlerRegistry) Build(config RouteConfig) (*Route, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return reg.build(config, "")
+}
+
+// build builds the route declared by config, whose parent has the given full path.
+func (reg *HandlerRegistry) build(config RouteConfig, parentPath string) (*Route, error) {
+	path := parentPath + config.Path
+	route := NewRoute(config.Path).Alias(config.Aliases...)
+	if config.Name != "" {
+		route.Name(config.Name)
+	}
+
+	for _, name := range config.Middlewares {
+		mw, ok := reg.middlewares[name]
+		if !ok {
+			return nil, fmt.Errorf("route %q: middleware %q not registered", path, name)
+		}
+		route.Use(mw)
+	}
+
+	if config.Redirect != "" {
+		if len(config.Handlers) > 0 {
+			return nil, fmt.Errorf("route %q: redirect cannot be combined with handlers", path)
+		}
+		status := config.RedirectStatus
+		if status == 0 {
+			status = http.StatusPermanentRedirect
+		}
+		if status < 300 || status > 399 {
+			return nil, fmt.Errorf("route %q: redirect status %d is not a redirection", path, status)
+		}
+		route.Add(All(redirectTo(config.Redirect, status)))
+	}
+
+	methods := slices.SortedFunc(maps.Keys(config.Handlers), compareMethods)
+	for _, method := range methods {
+		handler, err := reg.handler(config.Handlers[method])
+		if err != nil {
+			return nil, fmt.Errorf("route %q: %w", path, err)
+		}
+		switch method {
+		case "":
+			return nil, fmt.Errorf("route %q: handler %q has an empty method, use ALL for all methods", path, config.Handlers[method])
+		case "ALL":
+			method = ""
+		}
+		route.Add((&Route{Handler: handler, Method: method}).recordBuild("Build", handlerName(handler)))
+	}
+
+	for _, child := range config.Routes {
+		childRoute, err := reg.build(child, path)
+		if err != nil {
+			return nil, err
+		}
+		route.Add(childRoute)
+	}
+	return route, nil
+}
+
+// handler returns the handler registered under the name, or built by the factory of its prefix.
+func (reg *HandlerRegistry) handler(name string) (http.HandlerFunc, error) {
+	if handler, ok := reg.handlers[name]; ok {
+		return handler, nil
+	}
+	if prefix, arg, found := strings.Cut(name, ":"); found {
+		if factory, ok := reg.factories[prefix]; ok {
+			handler, err := factory(arg)
+			if err == nil && handler == nil {
+				err = errors.New("factory returned a nil handler")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("handler %q: %w", name, err)
+			}
+			return handler, nil
+		}
+	}
+	return nil, fmt.Errorf("handler %q not registered", name)
+}
+
+// redirectTo returns a handler redirecting the requests to url with the status code.
+func redirectTo(url string, status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, url, status)
+	}
+}
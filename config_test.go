@@ -0,0 +1,130 @@
+package simplerouter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// configRegistry returns a registry with the handlers and middlewares referenced by the test configs
+func configRegistry(tracker *[]string) *r.HandlerRegistry {
+	return r.NewHandlerRegistry().
+		Handle("listUsers", handlerWriter("users")).
+		Handle("createUser", handlerWriter("created")).
+		HandlePrefix("proxy", func(name string) (http.HandlerFunc, error) {
+			if name == "" {
+				return nil, errors.New("upstream name cannot be empty")
+			}
+			return handlerWriter("proxied to " + name), nil
+		}).
+		Middleware("auth", middlewareTracker("auth", tracker))
+}
+
+// TestLoadRoutes tests that the routes of a config file are served by the registered handlers
+func TestLoadRoutes(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "routes.json")
+	os.WriteFile(name, []byte(`{
+		"path": "/api",
+		"middlewares": ["auth"],
+		"routes": [
+			{"path": "/users", "aliases": ["/people"], "name": "users", "handlers": {"POST": "createUser", "GET": "listUsers"}},
+			{"path": "/orders/", "handlers": {"ALL": "proxy:orders"}},
+			{"path": "/v1/users", "redirect": "/api/users", "redirectStatus": 301}
+		]
+	}`), 0o600)
+
+	tracker := []string{}
+	route, err := configRegistry(&tracker).LoadRoutes(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := route.Mount(r.WithValidation())
+
+	tests := []struct {
+		method           string
+		path             string
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+	}{
+		{method: http.MethodGet, path: "/api/users", expectedStatus: http.StatusOK, expectedBody: "users"},
+		{method: http.MethodPost, path: "/api/people", expectedStatus: http.StatusOK, expectedBody: "created"},
+		{method: http.MethodDelete, path: "/api/orders/1", expectedStatus: http.StatusOK, expectedBody: "proxied to orders"},
+		{method: http.MethodGet, path: "/api/v1/users", expectedStatus: http.StatusMovedPermanently, expectedLocation: "/api/users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			tracker = []string{}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, strings.Join(tracker, ","), "auth")
+			assertCorrect(t, w.Header().Get("Location"), tt.expectedLocation)
+			if tt.expectedBody != "" {
+				assertCorrect(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+
+	url, err := route.URL("users")
+	assertCorrect(t, err, nil)
+	assertCorrect(t, url, "/api/users")
+}
+
+// TestBuildWithInvalidConfig tests that configs referencing unknown names or mixing redirects and handlers are rejected
+func TestBuildWithInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        r.RouteConfig
+		expectedError string
+	}{
+		{
+			name:          "unknown handler",
+			config:        r.RouteConfig{Path: "/api", Routes: []r.RouteConfig{{Path: "/users", Handlers: map[string]string{"GET": "getUsers"}}}},
+			expectedError: `route "/api/users": handler "getUsers" not registered`,
+		},
+		{
+			name:          "unknown middleware",
+			config:        r.RouteConfig{Path: "/api", Middlewares: []string{"auth", "logger"}},
+			expectedError: `route "/api": middleware "logger" not registered`,
+		},
+		{
+			name:          "failing factory",
+			config:        r.RouteConfig{Path: "/orders/", Handlers: map[string]string{"ALL": "proxy:"}},
+			expectedError: `route "/orders/": handler "proxy:": upstream name cannot be empty`,
+		},
+		{
+			name:          "empty method",
+			config:        r.RouteConfig{Path: "/users", Handlers: map[string]string{"": "listUsers"}},
+			expectedError: `route "/users": handler "listUsers" has an empty method, use ALL for all methods`,
+		},
+		{
+			name:          "redirect with handlers",
+			config:        r.RouteConfig{Path: "/old", Redirect: "/new", Handlers: map[string]string{"GET": "listUsers"}},
+			expectedError: `route "/old": redirect cannot be combined with handlers`,
+		},
+		{
+			name:          "redirect status",
+			config:        r.RouteConfig{Path: "/old", Redirect: "/new", RedirectStatus: http.StatusOK},
+			expectedError: `route "/old": redirect status 200 is not a redirection`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := configRegistry(&[]string{}).Build(tt.config)
+			if err == nil {
+				t.Fatal("Build() did not fail")
+			}
+			assertCorrect(t, err.Error(), tt.expectedError)
+		})
+	}
+}
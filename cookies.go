@@ -0,0 +1,153 @@
+package simplerouter
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrNoCookieSecret is returned by [SetCookie] and [GetSignedCookie] for signed cookies when the route
+// serving the request has no secret, see [Route.CookieSecret].
+var ErrNoCookieSecret = errors.New("route has no cookie secret")
+
+// ErrInvalidCookieSignature is returned by [GetSignedCookie] when the signature of the cookie does not match
+// its value, as it was not set by [SetCookie] with the secret of the route or was modified since.
+var ErrInvalidCookieSignature = errors.New("invalid cookie signature")
+
+// CookieOptions describes a cookie set by [SetCookie]. The zero values of its fields are secure defaults:
+// cookies are sent over HTTPS only, hidden from scripts, not sent with cross-site subrequests and valid
+// for the whole site.
+type CookieOptions struct {
+	// Name is the name of the cookie.
+	Name string
+	// Value is the value of the cookie, which should only contain the characters allowed by RFC 6265,
+	// like the ones of base64.URLEncoding.
+	Value string
+	// Path is the path of the URLs the cookie is sent with, "/" if empty.
+	Path string
+	// Domain is the domain of the URLs the cookie is sent with, only the host of the request if empty.
+	Domain string
+	// MaxAge is the lifetime of the cookie, which is kept until the browser is closed if zero, and
+	// deleted if negative.
+	MaxAge time.Duration
+	// SameSite restricts the cross-site requests sent with the cookie, http.SameSiteLaxMode if zero.
+	SameSite http.SameSite
+	// Signed signs the cookie with the secret of the route, so it can be read with [GetSignedCookie].
+	Signed bool
+	// AllowInsecure lets browsers send the cookie over plain HTTP.
+	AllowInsecure bool
+	// AllowScripts lets the scripts of the pages read the cookie.
+	AllowScripts bool
+}
+
+// SetCookie adds the cookie described by opts to the response to the request, with secure defaults:
+//
+//	simplerouter.SetCookie(w, r, simplerouter.CookieOptions{Name: "session", Value: id, MaxAge: 24 * time.Hour, Signed: true})
+//
+// Signed cookies are signed with the secret of the route serving the request, returning
+// [ErrNoCookieSecret] if there is none. It returns an error if the name or value are not valid.
+func SetCookie(w http.ResponseWriter, r *http.Request, opts CookieOptions) error {
+	cookie := &http.Cookie{
+		Name:     opts.Name,
+		Value:    opts.Value,
+		Path:     opts.Path,
+		Domain:   opts.Domain,
+		SameSite: opts.SameSite,
+		Secure:   !opts.AllowInsecure,
+		HttpOnly: !opts.AllowScripts,
+	}
+	if cookie.Path == "" {
+		cookie.Path = "/"
+	}
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+	switch {
+	case opts.MaxAge > 0:
+		cookie.MaxAge = max(int(opts.MaxAge/time.Second), 1)
+		cookie.Expires = time.Now().Add(opts.MaxAge).UTC()
+	case opts.MaxAge < 0:
+		cookie.MaxAge = -1
+		cookie.Expires = time.Unix(0, 0)
+	}
+	if err := cookie.Valid(); err != nil {
+		return err
+	}
+
+	if opts.Signed {
+		secret, ok := r.Context().Value(cookieSecretKey{}).([]byte)
+		if !ok {
+			return ErrNoCookieSecret
+		}
+		cookie.Value += "." + cookieSignature(secret, opts.Name, opts.Value)
+	}
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+// GetCookie returns the value of the named cookie of the request, or http.ErrNoCookie if there is none.
+// Its value can be set by the client as any other input, use [GetSignedCookie] for signed cookies.
+func GetCookie(r *http.Request, name string) (string, error) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return cookie.Value, nil
+}
+
+// GetSignedCookie returns the value of the named cookie of the request set by [SetCookie] as signed,
+// verifying its signature with the secret of the route serving the request. It returns http.ErrNoCookie
+// if there is no such cookie, [ErrInvalidCookieSignature] if its signature does not match and
+// [ErrNoCookieSecret] if the route has no secret.
+func GetSignedCookie(r *http.Request, name string) (string, error) {
+	secret, ok := r.Context().Value(cookieSecretKey{}).([]byte)
+	if !ok {
+		return "", ErrNoCookieSecret
+	}
+	value, err := GetCookie(r, name)
+	if err != nil {
+		return "", err
+	}
+	i := strings.LastIndexByte(value, '.')
+	if i < 0 || !hmac.Equal([]byte(value[i+1:]), []byte(cookieSignature(secret, name, value[:i]))) {
+		return "", ErrInvalidCookieSignature
+	}
+	return value[:i], nil
+}
+
+// cookieSignature returns the signature of the named cookie with the value, so signed values cannot be
+// moved to other cookies.
+func cookieSignature(secret []byte, name, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(name + "=" + value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// CookieSecret sets the secret signing the cookies of the route and its child routes, see [SetCookie] and
+// [GetSignedCookie]. Child routes can set their own secret, replacing the one of their parents.
+// It panics if secret is shorter than 32 bytes.
+func (r *Route) CookieSecret(secret []byte) *Route {
+	if len(secret) < 32 {
+		panic("secret parameter must be at least 32 bytes long")
+	}
+	r.Metadata.CookieSecret = slices.Clone(secret)
+	return r
+}
+
+// cookieSecretKey is the context key storing the cookie secret of the route serving the request.
+type cookieSecretKey struct{}
+
+// withCookieSecret returns a middleware storing the secret in the request context for [SetCookie] and [GetSignedCookie].
+func withCookieSecret(secret []byte) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), cookieSecretKey{}, secret)))
+		})
+	}
+}
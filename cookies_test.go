@@ -0,0 +1,111 @@
+package simplerouter_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestSetCookie tests the attributes of the cookies set with their defaults and options
+func TestSetCookie(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     r.CookieOptions
+		expected string
+	}{
+		{name: "defaults", opts: r.CookieOptions{Name: "theme", Value: "dark"}, expected: "theme=dark; Path=/; HttpOnly; Secure; SameSite=Lax"},
+		{
+			name:     "options",
+			opts:     r.CookieOptions{Name: "theme", Value: "dark", Path: "/app", Domain: "example.com", SameSite: http.SameSiteStrictMode, AllowInsecure: true, AllowScripts: true},
+			expected: "theme=dark; Path=/app; Domain=example.com; SameSite=Strict",
+		},
+		{name: "deleted", opts: r.CookieOptions{Name: "theme", MaxAge: -1}, expected: "theme=; Path=/; Expires=Thu, 01 Jan 1970 00:00:00 GMT; Max-Age=0; HttpOnly; Secure; SameSite=Lax"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			assertCorrect(t, r.SetCookie(w, httptest.NewRequest(http.MethodGet, "/", nil), tt.opts), nil)
+			assertCorrect(t, w.Header().Get("Set-Cookie"), tt.expected)
+		})
+	}
+
+	w := httptest.NewRecorder()
+	r.SetCookie(w, httptest.NewRequest(http.MethodGet, "/", nil), r.CookieOptions{Name: "session", Value: "1", MaxAge: time.Hour})
+	assertCorrect(t, w.Result().Cookies()[0].MaxAge, 3600)
+
+	err := r.SetCookie(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), r.CookieOptions{Name: "a b"})
+	assertCorrect(t, err != nil, true)
+}
+
+// TestSignedCookies tests that signed cookies are read back only if they were not modified
+func TestSignedCookies(t *testing.T) {
+	var readValue string
+	var readErr error
+	mux := r.NewRoute("").Add(
+		r.NewRoute("/signed").CookieSecret(bytes.Repeat([]byte("k"), 32)).Add(
+			r.NewRoute("/set").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+				r.SetCookie(w, req, r.CookieOptions{Name: "session", Value: "ada", Signed: true})
+			})),
+			r.NewRoute("/get").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+				readValue, readErr = r.GetSignedCookie(req, "session")
+			})),
+			r.NewRoute("/rotated").CookieSecret(bytes.Repeat([]byte("n"), 32)).Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+				readValue, readErr = r.GetSignedCookie(req, "session")
+			})),
+		),
+		r.NewRoute("/unsigned").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+			readErr = r.SetCookie(w, req, r.CookieOptions{Name: "session", Value: "ada", Signed: true})
+		})),
+	).Mount()
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/signed/set", nil))
+	signed := w.Result().Cookies()[0].Value
+
+	tests := []struct {
+		name          string
+		path          string
+		cookie        string
+		expectedValue string
+		expectedError error
+	}{
+		{name: "signed", path: "/signed/get", cookie: "session=" + signed, expectedValue: "ada"},
+		{name: "modified", path: "/signed/get", cookie: "session=alan" + signed[3:], expectedError: r.ErrInvalidCookieSignature},
+		{name: "unsigned", path: "/signed/get", cookie: "session=ada", expectedError: r.ErrInvalidCookieSignature},
+		{name: "other name", path: "/signed/get", cookie: "user=" + signed, expectedError: http.ErrNoCookie},
+		{name: "other secret", path: "/signed/rotated", cookie: "session=" + signed, expectedError: r.ErrInvalidCookieSignature},
+		{name: "without secret", path: "/unsigned", expectedError: r.ErrNoCookieSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readValue, readErr = "", nil
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.cookie != "" {
+				req.Header.Set("Cookie", tt.cookie)
+			}
+			mux.ServeHTTP(httptest.NewRecorder(), req)
+
+			assertCorrect(t, readValue, tt.expectedValue)
+			if !errors.Is(readErr, tt.expectedError) {
+				t.Errorf("got error %v want %v", readErr, tt.expectedError)
+			}
+		})
+	}
+}
+
+// TestCookieSecretTooShort tests that short cookie secrets cause a panic
+func TestCookieSecretTooShort(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("CookieSecret() did not panic")
+		}
+	}()
+	r.NewRoute("/").CookieSecret([]byte("secret"))
+}
@@ -0,0 +1,72 @@
+package simplerouter
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// CORS enables Cross-Origin Resource Sharing for the route and its child routes with the
+// [middleware.CORS] middleware. Once mounted, OPTIONS handlers are registered for the paths of the
+// route without one, so preflight requests go through the middlewares of the route and are answered.
+func (r *Route) CORS(opts middleware.CORSOptions) *Route {
+	r.Use(middleware.CORS(opts))
+	r.Metadata.Preflight = true
+	return r
+}
+
+// registerPreflights registers OPTIONS handlers for the paths of the endpoints marked for preflight
+// requests that have no handler matching the OPTIONS method. Each handler applies the middlewares
+// of the first endpoint of its path, and answers the OPTIONS requests that reach it with the allowed methods.
+func (m *mounter) registerPreflights(endpoints []Endpoint) {
+	methods := map[string][]string{}
+	for _, endpoint := range endpoints {
+		for _, path := range append([]string{endpoint.Path}, endpoint.Aliases...) {
+			path = m.basePath + path
+			methods[path] = append(methods[path], endpoint.Method)
+		}
+	}
+
+	registered := map[string]bool{}
+	for _, endpoint := range endpoints {
+		if !endpoint.Metadata.Preflight {
+			continue
+		}
+
+		for _, path := range append([]string{endpoint.Path}, endpoint.Aliases...) {
+			path = m.basePath + path
+			allowed := methods[path]
+			if registered[path] || slices.Contains(allowed, http.MethodOptions) || slices.Contains(allowed, "") {
+				continue
+			}
+			registered[path] = true
+
+			info := &RouteInfo{
+				Method:   http.MethodOptions,
+				Pattern:  path,
+				BasePath: m.basePath,
+				Metadata: Metadata{}.inherit(endpoint.Metadata),
+				paths:    m.paths,
+			}
+			m.handle(http.MethodOptions+" "+path, withRouteInfo(info, applyMiddleware(endpoint.Middlewares...)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Allow", allowHeader(allowed))
+					w.WriteHeader(http.StatusNoContent)
+				}),
+			)), endpoint.Metadata.Priority)
+		}
+	}
+}
+
+// allowHeader returns the value of the Allow header for the given registered methods.
+func allowHeader(methods []string) string {
+	allowed := slices.Clone(methods)
+	if slices.Contains(allowed, http.MethodGet) && !slices.Contains(allowed, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	allowed = append(allowed, http.MethodOptions)
+	slices.Sort(allowed)
+	return strings.Join(slices.Compact(allowed), ", ")
+}
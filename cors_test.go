@@ -0,0 +1,55 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestRouteCORS tests the preflight handlers registered for routes with CORS
+func TestRouteCORS(t *testing.T) {
+	mux := r.NewRoute("").Add(
+		r.NewRoute("/api").CORS(middleware.CORSOptions{AllowedOrigins: []string{"https://example.com"}}).Add(
+			r.NewRoute("/users").Alias("/members").Add(r.Get(handlerWriter("users")), r.Post(handlerWriter("create user"))),
+			r.NewRoute("/users").Add(r.Delete(handlerWriter("delete users"))),
+			r.NewRoute("/custom").Add(r.Options(handlerWriter("custom options"))),
+		),
+		r.NewRoute("/private").Add(r.Get(handlerWriter("private"))),
+	).Mount()
+
+	tests := []struct {
+		name           string
+		path           string
+		preflight      bool
+		expectedStatus int
+		expectedBody   string
+		expectedOrigin string
+		expectedAllow  string
+	}{
+		{name: "preflight", path: "/api/users", preflight: true, expectedStatus: http.StatusNoContent, expectedOrigin: "https://example.com"},
+		{name: "preflight on alias", path: "/api/members", preflight: true, expectedStatus: http.StatusNoContent, expectedOrigin: "https://example.com"},
+		{name: "plain options", path: "/api/users", expectedStatus: http.StatusNoContent, expectedOrigin: "https://example.com", expectedAllow: "DELETE, GET, HEAD, OPTIONS, POST"},
+		{name: "user options handler", path: "/api/custom", expectedStatus: http.StatusOK, expectedBody: "custom options", expectedOrigin: "https://example.com"},
+		{name: "route without cors", path: "/private", preflight: true, expectedStatus: http.StatusMethodNotAllowed, expectedBody: "Method Not Allowed\n", expectedAllow: "GET, HEAD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+			req.Header.Set("Origin", "https://example.com")
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, w.Header().Get("Access-Control-Allow-Origin"), tt.expectedOrigin)
+			assertCorrect(t, w.Header().Get("Allow"), tt.expectedAllow)
+		})
+	}
+}
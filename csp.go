@@ -0,0 +1,14 @@
+package simplerouter
+
+import (
+	"context"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// CSPNonce returns the CSP nonce of the request stored in ctx by the [middleware.ContentSecurityPolicy]
+// middleware, see [WebStack], or an empty string if there is none. Templates set it in the nonce
+// attribute of their inline scripts and styles so the Content-Security-Policy header allows them.
+func CSPNonce(ctx context.Context) string {
+	return middleware.CSPNonceFrom(ctx)
+}
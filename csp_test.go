@@ -0,0 +1,29 @@
+package simplerouter_test
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestCSPNonce tests the CSP nonce available to the templates rendered by the handlers of a tree
+func TestCSPNonce(t *testing.T) {
+	page := template.Must(template.New("page").Parse(`<script nonce="{{.}}">init()</script>`))
+	mux := r.NewRoute("/").Use(middleware.ContentSecurityPolicy("")).Add(
+		r.Get(func(w http.ResponseWriter, req *http.Request) {
+			page.Execute(w, r.CSPNonce(req.Context()))
+		}),
+	).Mount()
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	nonce := strings.TrimSuffix(strings.TrimPrefix(w.Body.String(), `<script nonce="`), `">init()</script>`)
+	assertCorrect(t, len(nonce), 24)
+	assertCorrect(t, strings.Contains(w.Header().Get("Content-Security-Policy"), "script-src 'self' 'nonce-"+nonce+"'"), true)
+}
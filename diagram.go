@@ -0,0 +1,98 @@
+package simplerouter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diagramNode is a route of the tree as drawn in a diagram.
+type diagramNode struct {
+	id     string
+	lines  []string
+	parent string
+}
+
+// DOT renders the route tree as a Graphviz DOT graph.
+// Each route is drawn as a node labeled with its path, aliases, middlewares and handler,
+// showing where in the tree each middleware is attached, and where in the code if provenance is enabled.
+func (r *Route) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph routes {\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, node := range r.diagramNodes() {
+		label := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(strings.Join(node.lines, "\n"))
+		label = strings.ReplaceAll(label, "\n", `\n`)
+		fmt.Fprintf(&b, "  %s [label=\"%s\"];\n", node.id, label)
+		if node.parent != "" {
+			fmt.Fprintf(&b, "  %s -> %s;\n", node.parent, node.id)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the route tree as a Mermaid flowchart.
+// Each route is drawn as a node labeled with its path, aliases, middlewares and handler,
+// showing where in the tree each middleware is attached, and where in the code if provenance is enabled.
+func (r *Route) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, node := range r.diagramNodes() {
+		label := strings.ReplaceAll(strings.Join(node.lines, "<br/>"), `"`, "#quot;")
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", node.id, label)
+		if node.parent != "" {
+			fmt.Fprintf(&b, "  %s --> %s\n", node.parent, node.id)
+		}
+	}
+	return b.String()
+}
+
+// diagramNodes returns the nodes of the route tree in depth-first order.
+func (r *Route) diagramNodes() []diagramNode {
+	nodes := []diagramNode{}
+
+	var visit func(route *Route, parent string)
+	visit = func(route *Route, parent string) {
+		id := fmt.Sprintf("n%d", len(nodes))
+
+		title := route.Path
+		if route.Handler != nil {
+			title = strings.TrimSpace(methodName(route.Method) + " " + route.Path)
+		}
+		if title == "" {
+			title = `""`
+		}
+
+		lines := []string{title}
+		if len(route.Aliases) > 0 {
+			lines = append(lines, "alias: "+strings.Join(route.Aliases, ", "))
+		}
+		if len(route.Middlewares) > 0 {
+			names := make([]string, len(route.Middlewares))
+			sources := route.MiddlewareSources()
+			for i, mw := range route.Middlewares {
+				names[i] = middlewareName(mw, sources[i])
+			}
+			lines = append(lines, "use: "+strings.Join(names, ", "))
+		}
+		if route.Handler != nil {
+			lines = append(lines, "handler: "+FuncName(route.Handler))
+		}
+
+		nodes = append(nodes, diagramNode{id: id, lines: lines, parent: parent})
+		for _, child := range route.Routes {
+			visit(child, id)
+		}
+	}
+	visit(r, "")
+
+	return nodes
+}
+
+// methodName returns the name used to display an HTTP method, "ALL" for routes matching every method.
+func methodName(method string) string {
+	if method == "" {
+		return "ALL"
+	}
+	return method
+}
@@ -0,0 +1,69 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// authMiddleware is a named middleware used to check the rendered names
+func authMiddleware(next http.Handler) http.Handler {
+	return next
+}
+
+// listUsers is a named handler used to check the rendered names
+func listUsers(w http.ResponseWriter, req *http.Request) {}
+
+// diagramRoute returns the route tree rendered in the diagram tests
+func diagramRoute() *r.Route {
+	return r.NewRoute("/api").Use(authMiddleware).Add(
+		r.NewRoute("/users").Alias("/members").Add(
+			r.Get(listUsers),
+			r.All(listUsers).Use(authMiddleware),
+		),
+		r.NewRoute(""),
+	)
+}
+
+// TestDOT tests the Graphviz rendering of a route tree
+func TestDOT(t *testing.T) {
+	want := `digraph routes {
+  node [shape=box];
+  n0 [label="/api\nuse: simplerouter_test.authMiddleware"];
+  n1 [label="/users\nalias: /members"];
+  n0 -> n1;
+  n2 [label="GET\nhandler: simplerouter_test.listUsers"];
+  n1 -> n2;
+  n3 [label="ALL\nuse: simplerouter_test.authMiddleware\nhandler: simplerouter_test.listUsers"];
+  n1 -> n3;
+  n4 [label="\"\""];
+  n0 -> n4;
+}
+`
+
+	got := diagramRoute().DOT()
+	if got != want {
+		t.Errorf("DOT() = %s, want %s", got, want)
+	}
+}
+
+// TestMermaid tests the Mermaid rendering of a route tree
+func TestMermaid(t *testing.T) {
+	want := `flowchart TD
+  n0["/api<br/>use: simplerouter_test.authMiddleware"]
+  n1["/users<br/>alias: /members"]
+  n0 --> n1
+  n2["GET<br/>handler: simplerouter_test.listUsers"]
+  n1 --> n2
+  n3["ALL<br/>use: simplerouter_test.authMiddleware<br/>handler: simplerouter_test.listUsers"]
+  n1 --> n3
+  n4["#quot;#quot;"]
+  n0 --> n4
+`
+
+	got := diagramRoute().Mermaid()
+	if got != want {
+		t.Errorf("Mermaid() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package simplerouter
+
+import (
+	"net/http"
+)
+
+// swaggerUIPage is the HTML page loading Swagger UI against the sibling openapi.json document.
+// The assets are pinned to a release and checked with their Subresource Integrity hashes, so a
+// compromised or updated CDN cannot run other scripts on the pages of the service.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>API documentation</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.10.3/swagger-ui.css"
+    integrity="sha384-h0W3Vqg5Snxbn56nHu/JCHYsKdSuoEcQneezEWEYGsAdajQJkgD+v9Qy8cuv/1bA" crossorigin="anonymous">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5.10.3/swagger-ui-bundle.js"
+    integrity="sha384-jVJWQ0wtFEKcwLYTTe3ZTkA8DbVK3s5bLmxjc30v16evmnx8m4NYVsc52bA+qIUl" crossorigin="anonymous"></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: window.location.pathname.replace(/\/$/, "") + "/openapi.json",
+      dom_id: "#swagger-ui",
+    });
+  </script>
+</body>
+</html>
+`
+
+// redocPage is the HTML page loading Redoc against the sibling openapi.json document.
+// The script is pinned to a release instead of the moving "latest" bundle.
+const redocPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>API documentation</title>
+</head>
+<body>
+  <div id="redoc"></div>
+  <script src="https://cdn.jsdelivr.net/npm/redoc@2.1.5/bundles/redoc.standalone.js"></script>
+  <script>
+    Redoc.init(window.location.pathname.replace(/\/$/, "") + "/openapi.json", {}, document.getElementById("redoc"));
+  </script>
+</body>
+</html>
+`
+
+// DocsRoute returns a Route serving Swagger UI on the given path for the OpenAPI document spec.
+// The document itself is served on path + "/openapi.json".
+// The spec can be user supplied or generated from the route tree with [Route.OpenAPI]:
+//
+//	router.Add(simplerouter.DocsRoute("/docs", router.OpenAPI("My API", "1.0.0")))
+func DocsRoute(path string, spec []byte) *Route {
+	return docsRoute(path, swaggerUIPage, spec)
+}
+
+// RedocRoute does the same as [DocsRoute], but serves Redoc instead of Swagger UI.
+func RedocRoute(path string, spec []byte) *Route {
+	return docsRoute(path, redocPage, spec)
+}
+
+// docsRoute returns a Route serving the given documentation page and the OpenAPI document it loads.
+func docsRoute(path string, page string, spec []byte) *Route {
+	if spec == nil {
+		panic("spec parameter cannot be nil")
+	}
+
+	return NewRoute(path).NoIndex().Add(
+		Get(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(page))
+		}),
+		NewRoute("/openapi.json").Add(
+			Get(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(spec)
+			}),
+		),
+	)
+}
@@ -0,0 +1,92 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestDocsRoute tests the routes serving the documentation pages and the OpenAPI document
+func TestDocsRoute(t *testing.T) {
+	spec := []byte(`{"openapi":"3.1.0"}`)
+
+	tests := []struct {
+		name                string
+		route               *r.Route
+		path                string
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "swagger ui page",
+			route:               r.DocsRoute("/docs", spec),
+			path:                "/docs",
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "swagger-ui",
+		},
+		{
+			name:                "redoc page",
+			route:               r.RedocRoute("/docs", spec),
+			path:                "/docs",
+			expectedContentType: "text/html; charset=utf-8",
+			expectedBody:        "redoc",
+		},
+		{
+			name:                "openapi document",
+			route:               r.DocsRoute("/docs", spec),
+			path:                "/docs/openapi.json",
+			expectedContentType: "application/json",
+			expectedBody:        `{"openapi":"3.1.0"}`,
+		},
+		{
+			name:                "nested docs route",
+			route:               r.NewRoute("/api").Add(r.RedocRoute("/docs", spec)),
+			path:                "/api/docs/openapi.json",
+			expectedContentType: "application/json",
+			expectedBody:        `{"openapi":"3.1.0"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.route.Mount().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, http.StatusOK)
+			assertCorrect(t, w.Header().Get("Content-Type"), tt.expectedContentType)
+			assertCorrect(t, w.Header().Get("X-Robots-Tag"), "noindex")
+			if !strings.Contains(w.Body.String(), tt.expectedBody) {
+				t.Errorf("Body = %q, want it to contain %q", w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+// TestDocsRouteWithNilSpec tests that a nil spec causes a panic
+func TestDocsRouteWithNilSpec(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected DocsRoute() with nil spec to panic, but it didn't")
+		}
+	}()
+
+	r.DocsRoute("/docs", nil)
+}
+
+// TestDocsRoutePinnedAssets tests that the documentation pages load pinned releases of their assets
+func TestDocsRoutePinnedAssets(t *testing.T) {
+	spec := []byte(`{"openapi":"3.1.0"}`)
+
+	w := httptest.NewRecorder()
+	r.DocsRoute("/docs", spec).Mount().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assertCorrect(t, strings.Count(w.Body.String(), "swagger-ui-dist@5.10.3/"), 2)
+	assertCorrect(t, strings.Count(w.Body.String(), `integrity="sha384-`), 2)
+
+	w = httptest.NewRecorder()
+	r.RedocRoute("/docs", spec).Mount().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assertCorrect(t, strings.Contains(w.Body.String(), "redoc@2.1.5/"), true)
+	assertCorrect(t, strings.Contains(w.Body.String(), "latest"), false)
+}
@@ -0,0 +1,74 @@
+package simplerouter
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Preload declares critical assets (stylesheets, scripts, fonts...) needed by the route and its child routes.
+// Once mounted, requests served by the route send a 103 Early Hints response announcing the assets
+// before the middlewares and handler run, so clients can start fetching them right away.
+// Each asset can be a plain path ("/static/app.css") or a full Link header value ("</app.css>; rel=preload; as=style").
+func (r *Route) Preload(assets ...string) *Route {
+	for _, asset := range assets {
+		if asset == "" {
+			panic("assets parameter cannot contain empty assets")
+		}
+	}
+	r.Metadata.Assets = append(r.Metadata.Assets, assets...)
+	return r
+}
+
+// EarlyHints returns a Middleware that announces the given assets with Link preload headers
+// in a 103 Early Hints response before calling the next handler.
+// Plain paths are also pushed when the connection supports HTTP/2 server push.
+// The Link headers are kept in the final response for clients that ignore informational responses.
+func EarlyHints(assets ...string) Middleware {
+	links := make([]string, len(assets))
+	for i, asset := range assets {
+		links[i] = preloadLink(asset)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, link := range links {
+				w.Header().Add("Link", link)
+			}
+			w.WriteHeader(http.StatusEarlyHints)
+
+			if pusher, ok := w.(http.Pusher); ok {
+				for _, asset := range assets {
+					if !strings.HasPrefix(asset, "<") {
+						// Push is best effort, clients may have disabled it.
+						_ = pusher.Push(asset, nil)
+					}
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// preloadLink builds the Link header value for an asset.
+// Full Link values are returned untouched, plain paths get the destination inferred from their extension.
+func preloadLink(asset string) string {
+	if strings.HasPrefix(asset, "<") {
+		return asset
+	}
+
+	link := "<" + asset + ">; rel=preload"
+	switch path.Ext(asset) {
+	case ".css":
+		link += "; as=style"
+	case ".js", ".mjs":
+		link += "; as=script"
+	case ".woff", ".woff2", ".ttf", ".otf":
+		link += "; as=font; crossorigin"
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp", ".avif", ".svg", ".ico":
+		link += "; as=image"
+	}
+
+	return link
+}
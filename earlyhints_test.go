@@ -0,0 +1,116 @@
+package simplerouter_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"reflect"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestPreload tests that Preload stores the assets in the route metadata
+func TestPreload(t *testing.T) {
+	route := r.NewRoute("/").Preload("/app.css").Preload("/app.js", "</font.woff2>; rel=preload; as=font")
+
+	want := []string{"/app.css", "/app.js", "</font.woff2>; rel=preload; as=font"}
+	if !reflect.DeepEqual(route.Metadata.Assets, want) {
+		t.Errorf("Assets = %v, want %v", route.Metadata.Assets, want)
+	}
+}
+
+// TestPreloadWithEmptyAsset tests that preloading empty assets causes a panic
+func TestPreloadWithEmptyAsset(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Preload(\"\") to panic, but it didn't")
+		}
+	}()
+
+	r.NewRoute("/").Preload("")
+}
+
+// TestEarlyHints tests the 103 responses sent for mounted routes with assets
+func TestEarlyHints(t *testing.T) {
+	tests := []struct {
+		name          string
+		route         *r.Route
+		path          string
+		expectedLinks []string
+	}{
+		{
+			name:          "route without assets",
+			route:         r.NewRoute("/page").Add(r.Get(handlerWriter("page"))),
+			path:          "/page",
+			expectedLinks: nil,
+		},
+		{
+			name:  "assets with inferred destinations",
+			route: r.NewRoute("/page").Preload("/app.css", "/app.js", "/logo.png", "/data.json").Add(r.Get(handlerWriter("page"))),
+			path:  "/page",
+			expectedLinks: []string{
+				"</app.css>; rel=preload; as=style",
+				"</app.js>; rel=preload; as=script",
+				"</logo.png>; rel=preload; as=image",
+				"</data.json>; rel=preload",
+			},
+		},
+		{
+			name:          "raw link values",
+			route:         r.NewRoute("/page").Preload("<https://cdn.example.com>; rel=preconnect").Add(r.Get(handlerWriter("page"))),
+			path:          "/page",
+			expectedLinks: []string{"<https://cdn.example.com>; rel=preconnect"},
+		},
+		{
+			name: "assets inherited from parent routes",
+			route: r.NewRoute("").Preload("/base.css").Add(
+				r.NewRoute("/page").Preload("/page.js").Add(r.Get(handlerWriter("page"))),
+				r.NewRoute("/other").Add(r.Get(handlerWriter("other"))),
+			),
+			path:          "/page",
+			expectedLinks: []string{"</base.css>; rel=preload; as=style", "</page.js>; rel=preload; as=script"},
+		},
+		{
+			name: "sibling assets are not shared",
+			route: r.NewRoute("").Preload("/base.css").Add(
+				r.NewRoute("/page").Preload("/page.js").Add(r.Get(handlerWriter("page"))),
+				r.NewRoute("/other").Add(r.Get(handlerWriter("other"))),
+			),
+			path:          "/other",
+			expectedLinks: []string{"</base.css>; rel=preload; as=style"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.route.Mount())
+			defer server.Close()
+
+			var gotLinks []string
+			trace := &httptrace.ClientTrace{
+				Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+					if code == http.StatusEarlyHints {
+						gotLinks = header["Link"]
+					}
+					return nil
+				},
+			}
+			ctx := httptrace.WithClientTrace(context.Background(), trace)
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+tt.path, nil)
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			assertCorrect(t, resp.StatusCode, http.StatusOK)
+			if !reflect.DeepEqual(gotLinks, tt.expectedLinks) {
+				t.Errorf("Early Hints links = %v, want %v", gotLinks, tt.expectedLinks)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package simplerouter
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Endpoint describes a handler of the route tree as it gets registered when the route is mounted.
+type Endpoint struct {
+	// Method is the HTTP method of the endpoint, empty when it matches all methods.
+	Method string
+	// Path is the full path of the endpoint, including the paths of its parent routes.
+	Path string
+	// Aliases are the additional full paths of the endpoint coming from route aliases.
+	Aliases []string
+	// Middlewares is the complete middleware chain applied to the handler, in execution order.
+	Middlewares []Middleware
+	// MiddlewareSources are the locations where the middlewares were attached, aligned with Middlewares,
+	// see [EnableProvenance].
+	MiddlewareSources []string
+	// Handler is the handler of the endpoint, or the fallback of the optional route it belongs to
+	// if its dependency failed, see [Route.Optional].
+	Handler http.HandlerFunc
+	// Metadata is the metadata of the endpoint, including the one inherited from its parent routes.
+	Metadata Metadata
+}
+
+// Pattern returns the http.ServeMux pattern used to register the endpoint.
+func (e Endpoint) Pattern() string {
+	return strings.TrimSpace(e.Method + " " + e.Path)
+}
+
+// Endpoints returns the endpoints of the route tree in the order they are registered when mounting it,
+// the same order used by [WithWalk] and by every export of the tree.
+// It can be used to generate documentation or to check the structure of the tree in tests.
+func (r *Route) Endpoints() []Endpoint {
+	return r.endpoints([]string{""}, []Middleware{}, []string{}, Metadata{})
+}
+
+// endpoints recursively collects the endpoints of the route and its child routes.
+func (r *Route) endpoints(paths []string, middlewares []Middleware, sources []string, metadata Metadata) []Endpoint {
+	chainedPaths := r.chainPaths(paths)
+	chainedMiddleware := slices.Concat(middlewares, r.Middlewares)
+	chainedSources := slices.Concat(sources, r.MiddlewareSources())
+	chainedMetadata := r.Metadata.inherit(metadata)
+
+	endpoints := []Endpoint{}
+	if r.Handler != nil {
+		endpoints = append(endpoints, Endpoint{
+			Method:            r.Method,
+			Path:              chainedPaths[0],
+			Aliases:           chainedPaths[1:],
+			Middlewares:       append([]Middleware{}, chainedMiddleware...),
+			MiddlewareSources: append([]string{}, chainedSources...),
+			Handler:           chainedMetadata.handler(r.Handler),
+			Metadata:          chainedMetadata,
+		})
+	}
+
+	for _, route := range r.Routes {
+		endpoints = append(endpoints, route.endpoints(chainedPaths, chainedMiddleware, chainedSources, chainedMetadata)...)
+	}
+
+	return endpoints
+}
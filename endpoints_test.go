@@ -0,0 +1,64 @@
+package simplerouter_test
+
+import (
+	"reflect"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestEndpoints tests the endpoints collected from a route tree
+func TestEndpoints(t *testing.T) {
+	tracker := []string{}
+	mw1 := middlewareTracker("mw1", &tracker)
+	mw2 := middlewareTracker("mw2", &tracker)
+	mw3 := middlewareTracker("mw3", &tracker)
+	getUsers := handlerWriter("users get")
+	postUsers := handlerWriter("users post")
+	anyPost := handlerWriter("post all")
+
+	route := r.NewRoute("/api").Use(mw1).Add(
+		r.NewRoute("/users").Use(mw2).Add(
+			r.Get(getUsers),
+			r.Post(postUsers).Use(mw3),
+		),
+		r.NewRoute("/posts/{id}").Add(
+			r.All(anyPost),
+		),
+		r.NewRoute("/empty"),
+	)
+
+	expected := []struct {
+		pattern     string
+		method      string
+		path        string
+		middlewares []r.Middleware
+		handler     any
+	}{
+		{pattern: "GET /api/users", method: "GET", path: "/api/users", middlewares: []r.Middleware{mw1, mw2}, handler: getUsers},
+		{pattern: "POST /api/users", method: "POST", path: "/api/users", middlewares: []r.Middleware{mw1, mw2, mw3}, handler: postUsers},
+		{pattern: "/api/posts/{id}", method: "", path: "/api/posts/{id}", middlewares: []r.Middleware{mw1}, handler: anyPost},
+	}
+
+	got := route.Endpoints()
+	if len(got) != len(expected) {
+		t.Fatalf("Endpoints() returned %d endpoints, want %d", len(got), len(expected))
+	}
+
+	for i, want := range expected {
+		t.Run(want.pattern, func(t *testing.T) {
+			assertCorrect(t, got[i].Pattern(), want.pattern)
+			assertCorrect(t, got[i].Method, want.method)
+			assertCorrect(t, got[i].Path, want.path)
+			assertCorrect(t, reflect.ValueOf(got[i].Handler).Pointer(), reflect.ValueOf(want.handler).Pointer())
+			if len(got[i].Middlewares) != len(want.middlewares) {
+				t.Fatalf("Endpoint has %d middlewares, want %d", len(got[i].Middlewares), len(want.middlewares))
+			}
+			for j, mw := range want.middlewares {
+				if reflect.ValueOf(got[i].Middlewares[j]).Pointer() != reflect.ValueOf(mw).Pointer() {
+					t.Errorf("Middleware at index %d is not the expected middleware", j)
+				}
+			}
+		})
+	}
+}
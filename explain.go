@@ -0,0 +1,117 @@
+package simplerouter
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Explain returns a human-readable trace of how the mounted route tree matches a request with the given
+// method and path: every pattern of the tree with the reason it was rejected, and the pattern selected by
+// http.ServeMux with the middlewares and handler of its endpoint. It helps to find out why a request
+// reaches an unexpected handler when the precedence rules of http.ServeMux come into play:
+//
+//	fmt.Print(router.Explain(http.MethodGet, "/users/me"))
+//
+// The tree is mounted with opts on every call, so it reflects its current state. Give it the options
+// used to mount the served tree, like [WithBasePath], so the patterns compared are the served ones.
+func (r *Route) Explain(method, path string, opts ...MountOption) string {
+	mux := r.Mount(opts...)
+	config := &mountConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	req := &http.Request{Method: method, URL: &url.URL{Path: path}, Host: "localhost", Header: http.Header{}}
+	_, winner := mux.Handler(req)
+	winner = strings.TrimSpace(winner)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", method, path)
+	b.WriteString("candidates:\n")
+
+	var matched *Endpoint
+	methodMismatch := false
+	endpoints := r.Endpoints()
+	for i, endpoint := range endpoints {
+		for _, chainedPath := range append([]string{endpoint.Path}, endpoint.Aliases...) {
+			chainedPath = config.basePath + chainedPath
+			pattern := strings.TrimSpace(endpoint.Method + " " + chainedPath)
+			reason := explainPattern(pattern, chainedPath, winner, req)
+			if reason == "" {
+				matched = &endpoints[i]
+				reason = "selected"
+			} else if strings.HasPrefix(reason, "method") {
+				methodMismatch = true
+			}
+			fmt.Fprintf(&b, "  %-7s %s: %s\n", methodName(endpoint.Method), chainedPath, reason)
+		}
+	}
+
+	switch {
+	case matched != nil:
+		fmt.Fprintf(&b, "matched: %s\n", winner)
+		b.WriteString("chain:\n")
+		for i, mw := range matched.Middlewares {
+			fmt.Fprintf(&b, "  %s\n", middlewareName(mw, matched.MiddlewareSources[i]))
+		}
+		fmt.Fprintf(&b, "  handler %s\n", FuncName(matched.Handler))
+	case config.notFound != nil && winner == "/":
+		b.WriteString("matched: none, answered by the WithNotFound handler\n")
+	case winner != "":
+		fmt.Fprintf(&b, "matched: %s, registered when mounting the tree, like a CORS preflight handler\n", winner)
+	case methodMismatch:
+		b.WriteString("matched: none, answered with 405 Method Not Allowed\n")
+	default:
+		b.WriteString("matched: none, answered with 404 Not Found\n")
+	}
+	return b.String()
+}
+
+// explainPattern returns why the pattern registered for the path was not selected for the request,
+// or an empty string if it is the winner pattern selected by the mounted tree.
+func explainPattern(pattern, path, winner string, req *http.Request) string {
+	if !matchesPattern(pattern, req) {
+		if matchesPattern(path, req) {
+			return fmt.Sprintf("method %s does not match", req.Method)
+		}
+		return "path does not match"
+	}
+	if pattern != winner {
+		return fmt.Sprintf("matches, but %q takes precedence", winner)
+	}
+	return ""
+}
+
+// matchesPattern reports whether the pattern matches the request on its own.
+func matchesPattern(pattern string, req *http.Request) bool {
+	mux := http.NewServeMux()
+	mux.Handle(pattern, http.NotFoundHandler())
+	_, matched := mux.Handler(req)
+	return matched != ""
+}
+
+// ExplainRoute returns a Route serving the trace of [Route.Explain] for the tree as plain text on the given
+// path, for the request described by the method and path query parameters, like "?method=POST&path=/users".
+// The method defaults to GET, and the tree is explained with opts, see [Route.Explain]. It is meant for
+// admin or debugging subtrees, not for public ones.
+func ExplainRoute(path string, tree *Route, opts ...MountOption) *Route {
+	if tree == nil {
+		panic("tree parameter cannot be nil")
+	}
+	return NewRoute(path).NoIndex().Add(
+		Get(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			method, target := query.Get("method"), query.Get("path")
+			if method == "" {
+				method = http.MethodGet
+			}
+			if !strings.HasPrefix(target, "/") {
+				http.Error(w, "path query parameter must start with a slash", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Write([]byte(tree.Explain(method, target, opts...)))
+		}),
+	)
+}
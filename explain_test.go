@@ -0,0 +1,91 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// explainTree is a tree with overlapping patterns
+func explainTree() *r.Route {
+	return r.NewRoute("/users").Use(authMiddleware).Add(
+		r.Get(listUsers),
+		r.NewRoute("/{id}").Add(r.Get(handlerWriter("user")), r.Delete(handlerWriter("delete"))),
+		r.NewRoute("/me").Add(r.Get(handlerWriter("me"))),
+	)
+}
+
+// TestExplain tests the traces of the matching decisions
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		expected string
+	}{
+		{
+			name:   "precedence",
+			method: http.MethodGet,
+			path:   "/users/me",
+			expected: "GET /users/me\n" +
+				"candidates:\n" +
+				"  GET     /users: path does not match\n" +
+				"  GET     /users/{id}: matches, but \"GET /users/me\" takes precedence\n" +
+				"  DELETE  /users/{id}: method GET does not match\n" +
+				"  GET     /users/me: selected\n" +
+				"matched: GET /users/me\n" +
+				"chain:\n" +
+				"  simplerouter_test.authMiddleware\n" +
+				"  handler simplerouter_test.handlerWriter.func1\n",
+		},
+		{
+			name:     "method not allowed",
+			method:   http.MethodPost,
+			path:     "/users/1",
+			expected: "matched: none, answered with 405 Method Not Allowed\n",
+		},
+		{
+			name:     "not found",
+			method:   http.MethodGet,
+			path:     "/orders",
+			expected: "matched: none, answered with 404 Not Found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation := explainTree().Explain(tt.method, tt.path)
+			if !strings.HasSuffix(explanation, tt.expected) {
+				t.Errorf("Explain() = %q, want suffix %q", explanation, tt.expected)
+			}
+		})
+	}
+}
+
+// TestExplainWithMountOptions tests that the trees are explained as mounted with the given options
+func TestExplainWithMountOptions(t *testing.T) {
+	explanation := explainTree().Explain(http.MethodGet, "/app/users/me", r.WithBasePath("/app"))
+	assertCorrect(t, strings.Contains(explanation, "  GET     /app/users/me: selected\n"), true)
+	assertCorrect(t, strings.HasSuffix(explanation, "  handler simplerouter_test.handlerWriter.func1\n"), true)
+
+	explanation = explainTree().Explain(http.MethodGet, "/orders", r.WithNotFound(http.NotFoundHandler()))
+	assertCorrect(t, strings.HasSuffix(explanation, "matched: none, answered by the WithNotFound handler\n"), true)
+}
+
+// TestExplainRoute tests the explanations served by explain routes
+func TestExplainRoute(t *testing.T) {
+	tree := explainTree()
+	mux := r.NewRoute("/debug").Add(r.ExplainRoute("/explain", tree)).Mount()
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/explain?method=DELETE&path=/users/1", nil))
+	assertCorrect(t, w.Code, http.StatusOK)
+	assertCorrect(t, strings.Contains(w.Body.String(), "matched: DELETE /users/{id}\n"), true)
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/explain", nil))
+	assertCorrect(t, w.Code, http.StatusBadRequest)
+}
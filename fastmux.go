@@ -0,0 +1,347 @@
+package simplerouter
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// MountFast does the same as [Route.Mount], but returns a handler matching the requests with a tree of
+// path segments instead of an http.ServeMux, for services whose benchmarks show the overhead of
+// http.ServeMux matters. It serves the requests as http.ServeMux would: the same patterns are accepted
+// (and rejected, panicking with the same messages), the most specific pattern is selected, path wildcards
+// are available with r.PathValue, HEAD requests are served by GET handlers, requests to paths registered
+// for other methods are answered with 405 Method Not Allowed and an Allow header, and unclean paths and
+// paths missing the trailing slash of a pattern registered for their method are redirected.
+// The middleware chains of the endpoints are built once when mounting, as with [Route.Mount].
+func (r *Route) MountFast(opts ...MountOption) http.Handler {
+	mux := newFastMux()
+	r.mountInto(mux, opts...)
+	return mux
+}
+
+// fastMux is the handler returned by [Route.MountFast].
+type fastMux struct {
+	// check rejects the invalid and conflicting patterns as http.ServeMux does.
+	check *http.ServeMux
+	root  *fastNode
+	// hosts are the roots of the patterns starting with a host.
+	hosts map[string]*fastNode
+}
+
+// fastNode is a path segment of a fastMux.
+type fastNode struct {
+	static map[string]*fastNode
+	param  *fastNode
+	// ends are the endpoints whose pattern ends at this segment.
+	ends []fastEndpoint
+	// rests are the endpoints matching one or more segments after this one, like "/files/{path...}".
+	rests []fastEndpoint
+}
+
+// fastEndpoint is a handler registered in a fastMux.
+type fastEndpoint struct {
+	method  string
+	pattern string
+	// wildcards are the names of the path wildcards of the pattern, aligned with its segments,
+	// empty for the static segments.
+	wildcards []string
+	// rest reports whether the last wildcard matches the rest of the path.
+	rest bool
+	// multi reports whether the pattern matches the rest of the path, ending in a slash or in a
+	// wildcard like "{path...}", and segments is its number of segments, counting the rest.
+	multi    bool
+	segments int
+	handler  http.Handler
+}
+
+// newFastMux returns an empty fastMux.
+func newFastMux() *fastMux {
+	return &fastMux{
+		check: http.NewServeMux(),
+		root:  &fastNode{},
+		hosts: map[string]*fastNode{},
+	}
+}
+
+// Handle registers the handler for the pattern, panicking as http.ServeMux does for invalid
+// or conflicting patterns.
+func (m *fastMux) Handle(pattern string, handler http.Handler) {
+	m.check.Handle(pattern, handler)
+
+	method, rest, found := strings.Cut(pattern, " ")
+	if !found {
+		method, rest = "", pattern
+	}
+	rest = strings.TrimLeft(rest, " \t")
+	i := strings.Index(rest, "/")
+	host, p := rest[:i], rest[i:]
+
+	root := m.root
+	if host != "" {
+		if m.hosts[host] == nil {
+			m.hosts[host] = &fastNode{}
+		}
+		root = m.hosts[host]
+	}
+
+	segments := strings.Split(p[1:], "/")
+	endpoint := fastEndpoint{method: method, pattern: pattern, segments: len(segments), handler: handler}
+	n := root
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch {
+		case last && segment == "":
+			endpoint.multi = true
+			n.rests = append(n.rests, endpoint)
+			return
+		case segment == "{$}":
+			endpoint.wildcards = append(endpoint.wildcards, "")
+			n = n.child("")
+		case strings.HasSuffix(segment, "...}"):
+			endpoint.wildcards = append(endpoint.wildcards, segment[1:len(segment)-4])
+			endpoint.rest, endpoint.multi = true, true
+			n.rests = append(n.rests, endpoint)
+			return
+		case strings.HasPrefix(segment, "{"):
+			endpoint.wildcards = append(endpoint.wildcards, segment[1:len(segment)-1])
+			if n.param == nil {
+				n.param = &fastNode{}
+			}
+			n = n.param
+		default:
+			endpoint.wildcards = append(endpoint.wildcards, "")
+			n = n.child(unescape(segment))
+		}
+	}
+	n.ends = append(n.ends, endpoint)
+}
+
+// child returns the static child node of the segment, creating it if needed.
+func (n *fastNode) child(segment string) *fastNode {
+	if n.static == nil {
+		n.static = map[string]*fastNode{}
+	}
+	if n.static[segment] == nil {
+		n.static[segment] = &fastNode{}
+	}
+	return n.static[segment]
+}
+
+// ServeHTTP serves the request with the handler of the most specific pattern matching it.
+func (m *fastMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.RequestURI == "*" {
+		if r.ProtoAtLeast(1, 1) {
+			w.Header().Set("Connection", "close")
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	host := r.Host
+	if len(m.hosts) > 0 {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+	// CONNECT requests are not canonicalized, and their path is empty in authority form.
+	escaped := r.URL.EscapedPath()
+	p := escaped
+	if r.Method != http.MethodConnect {
+		p = cleanPath(escaped)
+	}
+
+	endpoint := m.match(host, p, r.Method)
+	if (endpoint == nil || !endpoint.exact(p)) && p != "" && !strings.HasSuffix(p, "/") {
+		if slashed := m.match(host, p+"/", r.Method); slashed != nil && slashed.exact(p+"/") {
+			redirect(w, r, p+"/")
+			return
+		}
+	}
+	if p != escaped {
+		redirect(w, r, p)
+		return
+	}
+	if endpoint != nil {
+		endpoint.setPathValues(r, p[1:])
+		r.Pattern = endpoint.pattern
+		endpoint.handler.ServeHTTP(w, r)
+		return
+	}
+
+	allowed := map[string]bool{}
+	m.allowed(host, p, allowed)
+	if !strings.HasSuffix(p, "/") {
+		m.allowed(host, p+"/", allowed)
+	}
+	delete(allowed, "")
+	if len(allowed) > 0 {
+		if allowed[http.MethodGet] {
+			allowed[http.MethodHead] = true
+		}
+		methods := make([]string, 0, len(allowed))
+		for method := range allowed {
+			methods = append(methods, method)
+		}
+		slices.Sort(methods)
+		w.Header().Set("Allow", strings.Join(methods, ", "))
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// match returns the endpoint of the most specific pattern matching the method, the host and the escaped
+// path, trying the patterns with the host first, or nil if there is none.
+func (m *fastMux) match(host, p, method string) *fastEndpoint {
+	if p == "" {
+		return nil
+	}
+	if endpoint := m.hosts[host].match(p[1:], false, method); endpoint != nil {
+		return endpoint
+	}
+	return m.root.match(p[1:], false, method)
+}
+
+// allowed adds to methods the methods of the patterns matching the host and the escaped path.
+func (m *fastMux) allowed(host, p string, methods map[string]bool) {
+	if p == "" {
+		return
+	}
+	m.hosts[host].allowed(p[1:], false, methods)
+	m.root.allowed(p[1:], false, methods)
+}
+
+// exact reports whether the endpoint matches the escaped path exactly, as http.ServeMux defines it:
+// the patterns matching the rest of the path only match it exactly when the rest is empty.
+func (e *fastEndpoint) exact(p string) bool {
+	if !e.multi {
+		return true
+	}
+	return strings.HasSuffix(p, "/") && e.segments == strings.Count(p, "/")
+}
+
+// match returns the endpoint of the most specific pattern of the node matching the method and the escaped path,
+// without its leading slash, or nil if there is none. done reports whether the path has no segments left.
+// Static segments are more specific than wildcards, which are more specific than the patterns matching
+// the rest of the path.
+func (n *fastNode) match(p string, done bool, method string) *fastEndpoint {
+	if n == nil {
+		return nil
+	}
+	if done {
+		return pickEndpoint(n.ends, method)
+	}
+
+	segment, next, more := strings.Cut(p, "/")
+	if child := n.static[unescape(segment)]; child != nil {
+		if endpoint := child.match(next, !more, method); endpoint != nil {
+			return endpoint
+		}
+	}
+	if n.param != nil && segment != "" {
+		if endpoint := n.param.match(next, !more, method); endpoint != nil {
+			return endpoint
+		}
+	}
+	return pickEndpoint(n.rests, method)
+}
+
+// allowed adds to methods the methods of the patterns matching the escaped path, as [fastNode.match].
+func (n *fastNode) allowed(p string, done bool, methods map[string]bool) {
+	if n == nil {
+		return
+	}
+	if done {
+		for _, endpoint := range n.ends {
+			methods[endpoint.method] = true
+		}
+		return
+	}
+
+	segment, next, more := strings.Cut(p, "/")
+	if child := n.static[unescape(segment)]; child != nil {
+		child.allowed(next, !more, methods)
+	}
+	if n.param != nil && segment != "" {
+		n.param.allowed(next, !more, methods)
+	}
+	for _, endpoint := range n.rests {
+		methods[endpoint.method] = true
+	}
+}
+
+// setPathValues sets the values of the path wildcards of the endpoint from the escaped path
+// matched by its pattern, without its leading slash.
+func (e *fastEndpoint) setPathValues(r *http.Request, p string) {
+	for i, wildcard := range e.wildcards {
+		if e.rest && i == len(e.wildcards)-1 {
+			r.SetPathValue(wildcard, unescape(p))
+			return
+		}
+		segment, next, _ := strings.Cut(p, "/")
+		if wildcard != "" {
+			r.SetPathValue(wildcard, unescape(segment))
+		}
+		p = next
+	}
+}
+
+// unescape returns the unescaped path segment, or the segment itself if it is not escaped.
+func unescape(segment string) string {
+	if !strings.Contains(segment, "%") {
+		return segment
+	}
+	if unescaped, err := url.PathUnescape(segment); err == nil {
+		return unescaped
+	}
+	return segment
+}
+
+// pickEndpoint returns the endpoint registered for the method, the GET one for HEAD requests,
+// or the one registered for all methods, in that order.
+func pickEndpoint(endpoints []fastEndpoint, method string) *fastEndpoint {
+	var get, all *fastEndpoint
+	for i := range endpoints {
+		switch endpoints[i].method {
+		case method:
+			return &endpoints[i]
+		case http.MethodGet:
+			get = &endpoints[i]
+		case "":
+			all = &endpoints[i]
+		}
+	}
+	if method == http.MethodHead && get != nil {
+		return get
+	}
+	return all
+}
+
+// cleanPath returns the canonical form of the path as http.ServeMux does,
+// removing dot and empty segments but keeping the trailing slash.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	clean := path.Clean(p)
+	if strings.HasSuffix(p, "/") && clean != "/" {
+		clean += "/"
+	}
+	return clean
+}
+
+// redirect answers the request with a redirection to the path, keeping its query.
+func redirect(w http.ResponseWriter, r *http.Request, p string) {
+	u := &url.URL{Path: p, RawQuery: r.URL.RawQuery}
+	if unescaped, err := url.PathUnescape(p); err == nil {
+		u = &url.URL{Path: unescaped, RawPath: p, RawQuery: r.URL.RawQuery}
+	}
+	http.Redirect(w, r, u.String(), http.StatusTemporaryRedirect)
+}
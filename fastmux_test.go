@@ -0,0 +1,194 @@
+package simplerouter_test
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// pathValuesWriter creates a handler that writes its name, the pattern and the given path values
+func pathValuesWriter(name string, wildcards ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s %s", name, req.Pattern)
+		for _, wildcard := range wildcards {
+			fmt.Fprintf(w, " %s=%s", wildcard, req.PathValue(wildcard))
+		}
+	}
+}
+
+// TestMountFast tests that the fast handler serves requests as the http.ServeMux of Mount does
+func TestMountFast(t *testing.T) {
+	route := r.NewRoute("").Add(
+		r.NewRoute("/{$}").Add(r.Get(pathValuesWriter("index"))),
+		r.NewRoute("/users").Add(
+			r.Get(pathValuesWriter("list users")),
+			r.Post(pathValuesWriter("create user")),
+			r.NewRoute("/me").Add(r.Get(pathValuesWriter("me")), r.Head(pathValuesWriter("head me"))),
+			r.NewRoute("/{id}").Add(
+				r.Get(pathValuesWriter("get user", "id")),
+				r.NewRoute("/posts/{post}").Add(r.Delete(pathValuesWriter("delete post", "id", "post"))),
+			),
+		),
+		r.NewRoute("/files/{path...}").Add(r.Get(pathValuesWriter("file", "path"))),
+		r.NewRoute("/static/").Add(r.All(pathValuesWriter("static"))),
+		r.NewRoute("/items/{id}").Add(r.Post(pathValuesWriter("create item", "id"))),
+		r.NewRoute("/items/new").Add(r.Get(pathValuesWriter("new item form"))),
+		r.NewRoute("admin.example.com/users").Add(r.Get(pathValuesWriter("admin users"))),
+	)
+	mux, fast := route.Mount(), route.MountFast()
+
+	requests := []struct {
+		method string
+		target string
+		host   string
+	}{
+		{method: http.MethodGet, target: "/"},
+		{method: http.MethodGet, target: "/users"},
+		{method: http.MethodPost, target: "/users"},
+		{method: http.MethodDelete, target: "/users"},
+		{method: http.MethodGet, target: "/users/me"},
+		{method: http.MethodGet, target: "/users/42"},
+		{method: http.MethodHead, target: "/users/42"},
+		{method: http.MethodHead, target: "/users"},
+		{method: http.MethodHead, target: "/users/me"},
+		{method: http.MethodGet, target: "/users/a%2Fb"},
+		{method: http.MethodDelete, target: "/users/42/posts/7"},
+		{method: http.MethodGet, target: "/users/42/posts/7"},
+		{method: http.MethodGet, target: "/users/"},
+		{method: http.MethodGet, target: "/files/a/b/c.txt"},
+		{method: http.MethodGet, target: "/files/"},
+		{method: http.MethodGet, target: "/files"},
+		{method: http.MethodPut, target: "/static/css/site.css"},
+		{method: http.MethodGet, target: "/static"},
+		{method: http.MethodGet, target: "/static?v=1"},
+		{method: http.MethodGet, target: "/users/../files/x"},
+		{method: http.MethodGet, target: "//users"},
+		{method: http.MethodGet, target: "/items/new"},
+		{method: http.MethodPost, target: "/items/new"},
+		{method: http.MethodPut, target: "/items/new"},
+		{method: http.MethodGet, target: "/users", host: "admin.example.com:8080"},
+		{method: http.MethodGet, target: "/users/1", host: "admin.example.com"},
+		{method: http.MethodGet, target: "/missing"},
+	}
+
+	for _, tt := range requests {
+		t.Run(tt.method+" "+tt.host+tt.target, func(t *testing.T) {
+			want, got := httptest.NewRecorder(), httptest.NewRecorder()
+			newRequest := func() *http.Request {
+				req := httptest.NewRequest(tt.method, tt.target, nil)
+				if tt.host != "" {
+					req.Host = tt.host
+				}
+				return req
+			}
+			mux.ServeHTTP(want, newRequest())
+			fast.ServeHTTP(got, newRequest())
+
+			assertCorrect(t, got.Code, want.Code)
+			assertCorrect(t, got.Body.String(), want.Body.String())
+			assertCorrect(t, got.Header().Get("Allow"), want.Header().Get("Allow"))
+			assertCorrect(t, got.Header().Get("Location"), want.Header().Get("Location"))
+		})
+	}
+}
+
+// TestMountFastRedirects tests that the fast handler redirects the paths missing a trailing slash as the
+// http.ServeMux of Mount does, only to the patterns registered for the method of the request
+func TestMountFastRedirects(t *testing.T) {
+	routes := func() []*r.Route {
+		return []*r.Route{
+			r.NewRoute("/files/").Add(r.Post(pathValuesWriter("upload"))),
+			r.NewRoute("/a/b/{$}").Add(r.Get(pathValuesWriter("b index"))),
+			r.NewRoute("/docs/").Add(r.Get(pathValuesWriter("docs"))),
+			r.NewRoute("/v1/users/{id}").Add(r.Get(pathValuesWriter("user", "id"))),
+		}
+	}
+	trees := map[string]*r.Route{
+		"without root": r.NewRoute("").Add(routes()...),
+		"with root":    r.NewRoute("").Add(append(routes(), r.NewRoute("/").Add(r.Get(pathValuesWriter("root"))))...),
+	}
+
+	requests := []struct {
+		method string
+		target string
+	}{
+		{method: http.MethodGet, target: "/files"},
+		{method: http.MethodPost, target: "/files"},
+		{method: http.MethodPost, target: "/files/x"},
+		{method: http.MethodGet, target: "/a/b"},
+		{method: http.MethodGet, target: "/a/b/"},
+		{method: http.MethodPost, target: "/a/b"},
+		{method: http.MethodGet, target: "/a/b/c"},
+		{method: http.MethodGet, target: "/docs"},
+		{method: http.MethodGet, target: "/docs/../docs"},
+		{method: http.MethodGet, target: "/v1//users/1"},
+		{method: http.MethodGet, target: "/v1/users"},
+		{method: http.MethodGet, target: "*"},
+	}
+
+	for name, route := range trees {
+		mux, fast := route.Mount(), route.MountFast()
+		for _, tt := range requests {
+			t.Run(name+" "+tt.method+" "+tt.target, func(t *testing.T) {
+				want, got := httptest.NewRecorder(), httptest.NewRecorder()
+				mux.ServeHTTP(want, httptest.NewRequest(tt.method, tt.target, nil))
+				fast.ServeHTTP(got, httptest.NewRequest(tt.method, tt.target, nil))
+
+				assertCorrect(t, got.Code, want.Code)
+				assertCorrect(t, got.Body.String(), want.Body.String())
+				assertCorrect(t, got.Header().Get("Allow"), want.Header().Get("Allow"))
+				assertCorrect(t, got.Header().Get("Location"), want.Header().Get("Location"))
+			})
+		}
+	}
+}
+
+// TestMountFastWithConnect tests that the fast handler answers CONNECT requests in authority form,
+// whose path is empty, as the http.ServeMux of Mount does
+func TestMountFastWithConnect(t *testing.T) {
+	connect := func(t *testing.T, handler http.Handler) *http.Response {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		fmt.Fprint(conn, "CONNECT example.com:443 HTTP/1.1\r\nHost: example.com:443\r\n\r\n")
+		resp, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: http.MethodConnect})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	routes := map[string]*r.Route{
+		"root":    r.NewRoute("/").Add(r.Get(pathValuesWriter("root"))),
+		"no root": r.NewRoute("/users").Add(r.Get(pathValuesWriter("users"))),
+	}
+	for name, route := range routes {
+		t.Run(name, func(t *testing.T) {
+			want, got := connect(t, route.Mount()), connect(t, route.MountFast())
+
+			assertCorrect(t, got.StatusCode, want.StatusCode)
+			assertCorrect(t, got.Header.Get("Allow"), want.Header.Get("Allow"))
+		})
+	}
+}
+
+// TestMountFastWithConflicts tests that conflicting patterns cause the same panic as Mount
+func TestMountFastWithConflicts(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MountFast() with conflicting routes did not panic")
+		}
+	}()
+	r.NewRoute("/users").Add(r.Get(listUsers), r.Get(listUsers)).MountFast()
+}
@@ -0,0 +1,42 @@
+package gateway
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Selector chooses the backend serving a request among the healthy backends of an upstream,
+// which are never empty. Implementations must be safe for concurrent use.
+type Selector interface {
+	Select(r *http.Request, backends []*Backend) *Backend
+}
+
+// SelectorFunc adapts a function to a [Selector].
+type SelectorFunc func(r *http.Request, backends []*Backend) *Backend
+
+// Select calls f.
+func (f SelectorFunc) Select(r *http.Request, backends []*Backend) *Backend {
+	return f(r, backends)
+}
+
+// RoundRobin returns a Selector choosing the backends in turns.
+func RoundRobin() Selector {
+	var next atomic.Uint64
+	return SelectorFunc(func(r *http.Request, backends []*Backend) *Backend {
+		return backends[(next.Add(1)-1)%uint64(len(backends))]
+	})
+}
+
+// LeastConnections returns a Selector choosing the backend with the fewest requests being proxied,
+// the first one configured in case of a tie.
+func LeastConnections() Selector {
+	return SelectorFunc(func(r *http.Request, backends []*Backend) *Backend {
+		best := backends[0]
+		for _, b := range backends[1:] {
+			if b.ActiveRequests() < best.ActiveRequests() {
+				best = b
+			}
+		}
+		return best
+	})
+}
@@ -0,0 +1,38 @@
+package gateway_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/gateway"
+)
+
+// newBackends returns backends with the given hosts
+func newBackends(hosts ...string) []*gateway.Backend {
+	backends := make([]*gateway.Backend, len(hosts))
+	for i, host := range hosts {
+		backends[i] = &gateway.Backend{URL: &url.URL{Scheme: "http", Host: host}}
+	}
+	return backends
+}
+
+// TestRoundRobin tests that backends are chosen in turns
+func TestRoundRobin(t *testing.T) {
+	selector := gateway.RoundRobin()
+	backends := newBackends("a", "b", "c")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	for _, expected := range []string{"a", "b", "c", "a"} {
+		assertCorrect(t, selector.Select(req, backends).URL.Host, expected)
+	}
+}
+
+// TestLeastConnections tests that the first backend is chosen when all are idle
+func TestLeastConnections(t *testing.T) {
+	selector := gateway.LeastConnections()
+	backends := newBackends("a", "b")
+
+	assertCorrect(t, selector.Select(httptest.NewRequest(http.MethodGet, "/", nil), backends).URL.Host, "a")
+}
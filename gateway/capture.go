@@ -0,0 +1,103 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+
+	"github.com/carlos-el/simplerouter/har"
+)
+
+// CaptureSink receives the exchanges captured by proxy routes, see [Capture].
+// It is called concurrently by the requests being served.
+type CaptureSink interface {
+	Capture(entry har.Entry) error
+}
+
+// CaptureSinkFunc is a function used as a [CaptureSink].
+type CaptureSinkFunc func(entry har.Entry) error
+
+// Capture calls f(entry).
+func (f CaptureSinkFunc) Capture(entry har.Entry) error {
+	return f(entry)
+}
+
+// JSONLinesSink is a [CaptureSink] writing each entry as a line of JSON, so captures can be streamed
+// to a file and processed line by line.
+type JSONLinesSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLinesSink returns a sink writing the entries to w.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{w: w}
+}
+
+// Capture writes the entry as a line of JSON.
+func (s *JSONLinesSink) Capture(entry har.Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// CaptureOptions configures the traffic captured by a proxy route.
+type CaptureOptions struct {
+	// Sink receives the captured exchanges.
+	Sink CaptureSink
+	// SampleRate is the fraction of the requests captured, between 0 and 1. All of them are captured if zero.
+	SampleRate float64
+	// MaxBodySize is the number of bytes of each body stored, 64 KiB if zero. Longer bodies are truncated.
+	MaxBodySize int
+	// Redaction lists the values hidden from the captured exchanges, besides the credentials
+	// of [har.DefaultRedactedHeaders].
+	Redaction har.Redaction
+}
+
+// defaultCaptureBodySize is the default value of [CaptureOptions.MaxBodySize].
+const defaultCaptureBodySize = 64 << 10
+
+// Capture records a sample of the requests served by the proxy route and their responses as HAR entries,
+// sent to the sink of the options for later analysis or replay. The response is captured as sent to
+// the client, after the policies of the route, including the responses of failed requests.
+// It panics if the options have no sink or their sample rate is not between 0 and 1.
+func Capture(opts CaptureOptions) ProxyOption {
+	if opts.Sink == nil {
+		panic("opts.Sink cannot be nil")
+	}
+	if opts.SampleRate < 0 || opts.SampleRate > 1 {
+		panic("opts.SampleRate must be between 0 and 1")
+	}
+	if opts.SampleRate == 0 {
+		opts.SampleRate = 1
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = defaultCaptureBodySize
+	}
+	return func(c *proxyConfig) { c.capture = &opts }
+}
+
+// wrap returns a handler capturing a sample of the exchanges served by next.
+func (opts *CaptureOptions) wrap(next http.HandlerFunc) http.HandlerFunc {
+	recorded := har.Record(next, opts.MaxBodySize, func(r *http.Request, entry har.Entry) {
+		opts.Redaction.Apply(&entry)
+		if err := opts.Sink.Capture(entry); err != nil {
+			slog.ErrorContext(r.Context(), "traffic capture failed", "error", err)
+		}
+	})
+	return func(w http.ResponseWriter, r *http.Request) {
+		if opts.SampleRate < 1 && rand.Float64() >= opts.SampleRate {
+			next(w, r)
+			return
+		}
+		recorded.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,108 @@
+package gateway_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/gateway"
+	"github.com/carlos-el/simplerouter/har"
+)
+
+// TestCapture tests the exchanges captured by proxy routes
+func TestCapture(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(append([]byte("echo "), body...))
+	}))
+	t.Cleanup(backend.Close)
+
+	registry := gateway.NewRegistry()
+	registry.Register("echo", gateway.UpstreamOptions{Backends: []string{backend.URL}})
+
+	var mu sync.Mutex
+	entries := []har.Entry{}
+	sink := gateway.CaptureSinkFunc(func(entry har.Entry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, entry)
+		return nil
+	})
+	handler := registry.Proxy("echo", gateway.Capture(gateway.CaptureOptions{
+		Sink:        sink,
+		MaxBodySize: 8,
+		Redaction:   har.Redaction{Headers: []string{"X-Api-Key"}, Query: []string{"token"}},
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/items?token=abc&page=2", strings.NewReader("hello"))
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Set("X-Api-Key", "abc")
+	req.Header.Set("Content-Type", "text/plain")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assertCorrect(t, w.Body.String(), "echo hello")
+	if len(entries) != 1 {
+		t.Fatalf("captured %d entries, want 1", len(entries))
+	}
+	entry := entries[0]
+
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{name: "method", got: entry.Request.Method, expected: http.MethodPost},
+		{name: "url", got: entry.Request.URL, expected: "http://example.com/items?page=2&token=[REDACTED]"},
+		{name: "request body", got: entry.Request.PostData.Text, expected: "hello"},
+		{name: "request body size", got: entry.Request.BodySize, expected: int64(5)},
+		{name: "authorization", got: header(entry.Request.Headers, "Authorization"), expected: har.Redacted},
+		{name: "configured header", got: header(entry.Request.Headers, "X-Api-Key"), expected: har.Redacted},
+		{name: "status", got: entry.Response.Status, expected: http.StatusOK},
+		{name: "response body", got: entry.Response.Content.Text, expected: "echo hel"},
+		{name: "response body size", got: entry.Response.Content.Size, expected: int64(10)},
+		{name: "truncation comment", got: entry.Response.Content.Comment, expected: "truncated to 8 bytes"},
+		{name: "set cookie", got: header(entry.Response.Headers, "Set-Cookie"), expected: har.Redacted},
+		{name: "response cookie", got: entry.Response.Cookies[0].Value, expected: har.Redacted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCorrect(t, tt.got, tt.expected)
+		})
+	}
+}
+
+// TestCaptureFailedRequest tests that the responses of failed requests are captured
+func TestCaptureFailedRequest(t *testing.T) {
+	var buf bytes.Buffer
+	registry := gateway.NewRegistry()
+	handler := registry.Proxy("missing", gateway.Capture(gateway.CaptureOptions{Sink: gateway.NewJSONLinesSink(&buf)}))
+
+	code, _ := get(handler, "/items")
+	assertCorrect(t, code, http.StatusBadGateway)
+
+	var entry har.Entry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	assertCorrect(t, entry.Response.Status, http.StatusBadGateway)
+	assertCorrect(t, strings.Count(buf.String(), "\n"), 1)
+}
+
+// header returns the value of the first header with the given name
+func header(pairs []har.NameValue, name string) string {
+	for _, pair := range pairs {
+		if pair.Name == name {
+			return pair.Value
+		}
+	}
+	return ""
+}
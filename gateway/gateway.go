@@ -0,0 +1,319 @@
+// Package gateway turns simplerouter trees into lightweight API gateways. Proxy routes reference
+// named upstreams of a [Registry], each one load balancing the requests between several backends
+// and leaving out the ones failing their health checks:
+//
+//	registry := gateway.NewRegistry()
+//	registry.Register("users", gateway.UpstreamOptions{
+//		Backends:   []string{"http://10.0.0.1:8080", "http://10.0.0.2:8080"},
+//		HealthPath: "/healthz",
+//	})
+//	go registry.Run(ctx)
+//
+//	router := simplerouter.NewRoute("/users/").Add(simplerouter.All(registry.Proxy("users")))
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// UpstreamOptions configures an upstream of a [Registry].
+type UpstreamOptions struct {
+	// Backends lists the base URLs of the servers of the upstream, like "http://10.0.0.1:8080".
+	Backends []string
+	// Selector chooses the backend serving each request, [RoundRobin] if nil.
+	Selector Selector
+	// HealthPath is the path requested to check the health of the backends. If empty,
+	// backends are not checked and always considered healthy.
+	HealthPath string
+	// HealthInterval is the time between health checks, 10 seconds if zero.
+	HealthInterval time.Duration
+	// HealthTimeout is the timeout of each health check request, 2 seconds if zero.
+	HealthTimeout time.Duration
+	// TLSConfig configures the TLS connections to https backends, like custom CAs or client
+	// certificates for mutual TLS, see [LoadTLSConfig]. The default configuration is used if nil.
+	TLSConfig *tls.Config
+}
+
+// Backend is a server of an upstream. It is safe for concurrent use.
+type Backend struct {
+	// URL is the base URL of the backend.
+	URL *url.URL
+
+	id      string
+	healthy atomic.Bool
+	active  atomic.Int64
+	proxy   *httputil.ReverseProxy
+}
+
+// Healthy reports whether the backend passed its last health check.
+func (b *Backend) Healthy() bool {
+	return b.healthy.Load()
+}
+
+// ActiveRequests returns the number of requests being proxied to the backend.
+func (b *Backend) ActiveRequests() int64 {
+	return b.active.Load()
+}
+
+// Upstream is a named group of backends serving the same service.
+type Upstream struct {
+	// Name identifies the upstream in the registry.
+	Name string
+	// Backends are the backends of the upstream, in the order they were configured.
+	Backends []*Backend
+
+	selector  Selector
+	opts      UpstreamOptions
+	transport *http.Transport
+	client    *http.Client
+}
+
+// HealthyBackends returns the backends of the upstream that passed their last health check.
+func (u *Upstream) HealthyBackends() []*Backend {
+	healthy := []*Backend{}
+	for _, b := range u.Backends {
+		if b.Healthy() {
+			healthy = append(healthy, b)
+		}
+	}
+	return healthy
+}
+
+// CheckHealth requests the health path of every backend of the upstream concurrently,
+// marking them as healthy if they answer with a 2xx or 3xx status code. It does nothing
+// if the upstream has no health path.
+func (u *Upstream) CheckHealth(ctx context.Context) {
+	if u.opts.HealthPath == "" {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, b := range u.Backends {
+		wg.Go(func() {
+			b.healthy.Store(u.checkBackend(ctx, b))
+		})
+	}
+	wg.Wait()
+}
+
+// checkBackend reports whether the backend answers its health check successfully.
+func (u *Upstream) checkBackend(ctx context.Context, b *Backend) bool {
+	ctx, cancel := context.WithTimeout(ctx, u.opts.HealthTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.URL.JoinPath(u.opts.HealthPath).String(), nil)
+	if err != nil {
+		return false
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode >= 200 && resp.StatusCode < 400
+}
+
+// Registry holds the upstreams referenced by proxy routes. It is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	upstreams map[string]*Upstream
+}
+
+// NewRegistry returns an empty [Registry].
+func NewRegistry() *Registry {
+	return &Registry{upstreams: map[string]*Upstream{}}
+}
+
+// Register adds an upstream to the registry under the given name, replacing the upstream
+// registered with the same name if any, so backends can be changed while serving.
+// It returns an error if no backend is given or if a backend URL is not valid.
+func (reg *Registry) Register(name string, opts UpstreamOptions) (*Upstream, error) {
+	if len(opts.Backends) == 0 {
+		return nil, fmt.Errorf("upstream %q has no backends", name)
+	}
+	if opts.Selector == nil {
+		opts.Selector = RoundRobin()
+	}
+	if opts.HealthInterval == 0 {
+		opts.HealthInterval = 10 * time.Second
+	}
+	if opts.HealthTimeout == 0 {
+		opts.HealthTimeout = 2 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.TLSConfig != nil {
+		transport.TLSClientConfig = opts.TLSConfig
+	}
+	u := &Upstream{
+		Name:      name,
+		selector:  opts.Selector,
+		opts:      opts,
+		transport: transport,
+		client:    &http.Client{Transport: transport},
+	}
+	for _, rawURL := range opts.Backends {
+		target, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("upstream %q: %w", name, err)
+		}
+		if target.Scheme != "http" && target.Scheme != "https" {
+			return nil, fmt.Errorf("upstream %q: backend %q must be an http or https URL", name, rawURL)
+		}
+		b := &Backend{URL: target, id: backendID(target)}
+		b.healthy.Store(true)
+		b.proxy = u.newReverseProxy(b)
+		u.Backends = append(u.Backends, b)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.upstreams[name] = u
+	return u, nil
+}
+
+// Upstream returns the upstream registered under the given name, and whether it was found.
+func (reg *Registry) Upstream(name string) (*Upstream, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	u, ok := reg.upstreams[name]
+	return u, ok
+}
+
+// Run checks the health of the backends of every upstream at their health interval
+// until ctx is done. Upstreams registered while running are checked too.
+func (reg *Registry) Run(ctx context.Context) {
+	lastChecks := map[*Upstream]time.Time{}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		reg.mu.RLock()
+		upstreams := make([]*Upstream, 0, len(reg.upstreams))
+		for _, u := range reg.upstreams {
+			upstreams = append(upstreams, u)
+		}
+		reg.mu.RUnlock()
+
+		now := time.Now()
+		for _, u := range upstreams {
+			if now.Sub(lastChecks[u]) >= u.opts.HealthInterval {
+				lastChecks[u] = now
+				go u.CheckHealth(ctx)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Proxy returns a handler proxying the requests to a healthy backend of the upstream registered under
+// the given name, chosen by its selector. The upstream is looked up on each request, so it can be
+// registered after the route is mounted. Requests are answered with a 502 Bad Gateway if the upstream
+// is not registered or the backend fails, and with a 503 Service Unavailable if no backend is healthy.
+// Request paths are forwarded unchanged, appended to the path of the backend URL, unless rewritten
+// by the opts of the route, which also declare the rules modifying the headers and the session affinity.
+func (reg *Registry) Proxy(name string, opts ...ProxyOption) http.HandlerFunc {
+	config := &proxyConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		u, ok := reg.Upstream(name)
+		if !ok {
+			slog.ErrorContext(r.Context(), "upstream not registered", "upstream", name)
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+			return
+		}
+
+		healthy := u.HealthyBackends()
+		if len(healthy) == 0 {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+
+		var b *Backend
+		if config.sticky != nil {
+			b = config.sticky.selectBackend(w, r, u, healthy)
+		} else {
+			b = u.selector.Select(r, healthy)
+		}
+		if b == nil {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+
+		b.active.Add(1)
+		defer b.active.Add(-1)
+		b.proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), proxyConfigKey{}, config)))
+	}
+
+	if config.capture != nil {
+		return config.capture.wrap(handler)
+	}
+	return handler
+}
+
+// newReverseProxy returns the reverse proxy forwarding requests to the backend,
+// applying the policies of the proxy route serving them.
+// If the upstream has health checks, backends failing to answer are marked as unhealthy
+// until their next successful check.
+func (u *Upstream) newReverseProxy(b *Backend) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Transport: u.transport,
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			config := configFrom(pr.In.Context())
+			for _, rewrite := range config.pathRewrites {
+				pr.Out.URL.Path = rewrite.Pattern.ReplaceAllString(pr.Out.URL.Path, rewrite.Replacement)
+				pr.Out.URL.RawPath = ""
+			}
+			pr.SetURL(b.URL)
+			pr.SetXForwarded()
+			for _, rules := range config.requestHeaders {
+				rules.apply(pr.Out.Header)
+			}
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			config := configFrom(resp.Request.Context())
+			for _, rules := range config.responseHeaders {
+				rules.apply(resp.Header)
+			}
+			for _, t := range config.transformers {
+				if err := t.Transform(resp); err != nil {
+					return &transformError{err}
+				}
+			}
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			slog.ErrorContext(r.Context(), "upstream request failed",
+				"upstream", u.Name,
+				"backend", b.URL.String(),
+				"error", err,
+			)
+			var transformErr *transformError
+			if u.opts.HealthPath != "" && !errors.As(err, &transformErr) {
+				b.healthy.Store(false)
+			}
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package gateway_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/gateway"
+)
+
+func assertCorrect(t testing.TB, got, want any) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+// newBackend starts a backend answering with its name and the path requested,
+// and its health endpoint with the status stored in health
+func newBackend(t *testing.T, name string, health *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/healthz" {
+			w.WriteHeader(*health)
+			return
+		}
+		w.Write([]byte(name + " " + req.URL.Path))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// get serves a GET request to the handler, returning the status code and body
+func get(handler http.Handler, path string) (int, string) {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	body, _ := io.ReadAll(w.Body)
+	return w.Code, string(body)
+}
+
+// TestProxy tests requests proxied to the backends of an upstream
+func TestProxy(t *testing.T) {
+	healthA, healthB := http.StatusOK, http.StatusOK
+	backendA := newBackend(t, "a", &healthA)
+	backendB := newBackend(t, "b", &healthB)
+
+	registry := gateway.NewRegistry()
+	upstream, err := registry.Register("users", gateway.UpstreamOptions{
+		Backends:   []string{backendA.URL, backendB.URL},
+		HealthPath: "/healthz",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := r.NewRoute("/users/").Add(r.All(registry.Proxy("users"))).Mount()
+
+	_, first := get(mux, "/users/1")
+	_, second := get(mux, "/users/2")
+	assertCorrect(t, first, "a /users/1")
+	assertCorrect(t, second, "b /users/2")
+
+	healthA = http.StatusInternalServerError
+	upstream.CheckHealth(context.Background())
+	for range 2 {
+		_, body := get(mux, "/users/3")
+		assertCorrect(t, body, "b /users/3")
+	}
+
+	healthB = http.StatusServiceUnavailable
+	upstream.CheckHealth(context.Background())
+	status, _ := get(mux, "/users/4")
+	assertCorrect(t, status, http.StatusServiceUnavailable)
+
+	healthA = http.StatusOK
+	upstream.CheckHealth(context.Background())
+	_, body := get(mux, "/users/5")
+	assertCorrect(t, body, "a /users/5")
+}
+
+// TestProxyWithUnregisteredUpstream tests requests to upstreams not registered
+func TestProxyWithUnregisteredUpstream(t *testing.T) {
+	status, _ := get(gateway.NewRegistry().Proxy("missing"), "/")
+
+	assertCorrect(t, status, http.StatusBadGateway)
+}
+
+// TestProxyWithFailingBackend tests that backends failing to answer are marked as unhealthy
+func TestProxyWithFailingBackend(t *testing.T) {
+	health := http.StatusOK
+	backend := newBackend(t, "a", &health)
+	backend.Close()
+
+	registry := gateway.NewRegistry()
+	upstream, _ := registry.Register("users", gateway.UpstreamOptions{
+		Backends:   []string{backend.URL},
+		HealthPath: "/healthz",
+	})
+
+	status, _ := get(registry.Proxy("users"), "/")
+	assertCorrect(t, status, http.StatusBadGateway)
+	assertCorrect(t, len(upstream.HealthyBackends()), 0)
+}
+
+// TestRegister tests the upstreams rejected by the registry
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends []string
+	}{
+		{name: "no backends", backends: nil},
+		{name: "invalid URL", backends: []string{"http://[::1"}},
+		{name: "unsupported scheme", backends: []string{"ftp://10.0.0.1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := gateway.NewRegistry()
+			if _, err := registry.Register("users", gateway.UpstreamOptions{Backends: tt.backends}); err == nil {
+				t.Error("Register() returned no error")
+			}
+			if _, ok := registry.Upstream("users"); ok {
+				t.Error("Register() registered an invalid upstream")
+			}
+		})
+	}
+}
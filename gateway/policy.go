@@ -0,0 +1,99 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+)
+
+// ProxyOption configures a proxy route, see [Registry.Proxy].
+type ProxyOption func(*proxyConfig)
+
+// proxyConfig holds the policies of a proxy route.
+type proxyConfig struct {
+	requestHeaders  []HeaderRules
+	responseHeaders []HeaderRules
+	pathRewrites    []PathRewrite
+	sticky          *sticky
+	transformers    []ResponseTransformer
+	capture         *CaptureOptions
+}
+
+// proxyConfigKey is the context key storing the policies of the proxy route serving the request.
+type proxyConfigKey struct{}
+
+// configFrom returns the policies of the proxy route stored in ctx, or empty policies if there are none.
+func configFrom(ctx context.Context) *proxyConfig {
+	if config, ok := ctx.Value(proxyConfigKey{}).(*proxyConfig); ok {
+		return config
+	}
+	return &proxyConfig{}
+}
+
+// HeaderRewrite replaces the matches of Pattern in the values of the header Name with Replacement,
+// which can reference the groups of the pattern as in regexp.Regexp.ReplaceAllString.
+type HeaderRewrite struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// HeaderRules modifies the headers of proxied requests or responses.
+// Rules are applied in the order of the fields: Remove, Set, Add and Rewrite.
+type HeaderRules struct {
+	// Remove lists the headers deleted.
+	Remove []string
+	// Set maps headers to the value replacing their values.
+	Set map[string]string
+	// Add maps headers to a value added to their values.
+	Add map[string]string
+	// Rewrite lists the replacements made in header values.
+	Rewrite []HeaderRewrite
+}
+
+// apply applies the rules to the header.
+func (rules HeaderRules) apply(h http.Header) {
+	for _, name := range rules.Remove {
+		h.Del(name)
+	}
+	for name, value := range rules.Set {
+		h.Set(name, value)
+	}
+	for name, value := range rules.Add {
+		h.Add(name, value)
+	}
+	for _, rewrite := range rules.Rewrite {
+		values := h.Values(rewrite.Name)
+		for i, value := range values {
+			values[i] = rewrite.Pattern.ReplaceAllString(value, rewrite.Replacement)
+		}
+	}
+}
+
+// PathRewrite replaces the matches of Pattern in the path of proxied requests with Replacement,
+// which can reference the groups of the pattern as in regexp.Regexp.ReplaceAllString.
+type PathRewrite struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// RequestHeaders applies the rules to the headers of the requests sent to the upstream.
+func RequestHeaders(rules HeaderRules) ProxyOption {
+	return func(c *proxyConfig) { c.requestHeaders = append(c.requestHeaders, rules) }
+}
+
+// ResponseHeaders applies the rules to the headers of the responses received from the upstream.
+func ResponseHeaders(rules HeaderRules) ProxyOption {
+	return func(c *proxyConfig) { c.responseHeaders = append(c.responseHeaders, rules) }
+}
+
+// RewritePath rewrites the paths of the requests sent to the upstream, like stripping the prefix
+// of the proxy route with regexp.MustCompile("^/users"). Rewrites are applied in order.
+func RewritePath(pattern *regexp.Regexp, replacement string) ProxyOption {
+	if pattern == nil {
+		panic("pattern parameter cannot be nil")
+	}
+	return func(c *proxyConfig) {
+		c.pathRewrites = append(c.pathRewrites, PathRewrite{Pattern: pattern, Replacement: replacement})
+	}
+}
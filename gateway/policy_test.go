@@ -0,0 +1,55 @@
+package gateway_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/gateway"
+)
+
+// TestProxyPolicies tests the header and path rules applied by proxy routes
+func TestProxyPolicies(t *testing.T) {
+	var received *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received = req
+		w.Header().Set("Server", "backend/1.0")
+		w.Header().Set("Location", "http://internal:8080/v2/users/1")
+		w.Header().Set("X-Internal", "secret")
+	}))
+	defer backend.Close()
+
+	registry := gateway.NewRegistry()
+	registry.Register("users", gateway.UpstreamOptions{Backends: []string{backend.URL}})
+	handler := registry.Proxy("users",
+		gateway.RewritePath(regexp.MustCompile(`^/api/users`), "/v2/users"),
+		gateway.RequestHeaders(gateway.HeaderRules{
+			Remove: []string{"Cookie"},
+			Set:    map[string]string{"X-Gateway": "simplerouter"},
+			Add:    map[string]string{"X-Tag": "b"},
+		}),
+		gateway.ResponseHeaders(gateway.HeaderRules{
+			Remove: []string{"X-Internal"},
+			Rewrite: []gateway.HeaderRewrite{
+				{Name: "Location", Pattern: regexp.MustCompile(`^http://internal:8080/v2`), Replacement: "https://example.com/api"},
+			},
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/1?fields=name", nil)
+	req.Header.Set("Cookie", "session=1")
+	req.Header.Set("X-Tag", "a")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assertCorrect(t, received.URL.Path, "/v2/users/1")
+	assertCorrect(t, received.URL.RawQuery, "fields=name")
+	assertCorrect(t, received.Header.Get("Cookie"), "")
+	assertCorrect(t, received.Header.Get("X-Gateway"), "simplerouter")
+	assertCorrect(t, len(received.Header.Values("X-Tag")), 2)
+
+	assertCorrect(t, w.Header().Get("Server"), "backend/1.0")
+	assertCorrect(t, w.Header().Get("X-Internal"), "")
+	assertCorrect(t, w.Header().Get("Location"), "https://example.com/api/users/1")
+}
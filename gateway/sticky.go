@@ -0,0 +1,111 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+)
+
+// AffinityFallback decides how sticky proxy routes serve requests whose backend is not available,
+// because it is unhealthy or was removed from the upstream.
+type AffinityFallback int
+
+const (
+	// Rebalance serves the request with another healthy backend, which becomes the new one of the client.
+	Rebalance AffinityFallback = iota
+	// FailRequest answers the request with a 503 Service Unavailable, for backends keeping state
+	// that cannot be recovered by other backends.
+	FailRequest
+)
+
+// sticky configures the session affinity of a proxy route.
+type sticky struct {
+	cookie   string
+	header   string
+	fallback AffinityFallback
+}
+
+// StickyCookie pins each client to a backend with a cookie with the given name, set on the first
+// response, so all the requests of a client are served by the same backend while it is available.
+// The cookie stores an opaque ID of the backend, not its address.
+func StickyCookie(name string, fallback AffinityFallback) ProxyOption {
+	if name == "" {
+		panic("name parameter cannot be empty")
+	}
+	return func(c *proxyConfig) { c.sticky = &sticky{cookie: name, fallback: fallback} }
+}
+
+// StickyHeader pins the requests with the same value of the header with the given name (like a user
+// or tenant ID) to the same backend, chosen by hashing the value among the backends of the upstream.
+// Requests without the header are balanced by the selector of the upstream.
+func StickyHeader(name string, fallback AffinityFallback) ProxyOption {
+	if name == "" {
+		panic("name parameter cannot be empty")
+	}
+	return func(c *proxyConfig) { c.sticky = &sticky{header: name, fallback: fallback} }
+}
+
+// backendID returns the opaque ID identifying the backend with the given URL in sticky cookies.
+func backendID(target *url.URL) string {
+	sum := sha256.Sum256([]byte(target.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// selectBackend chooses the backend serving the request among the healthy ones, following the session
+// affinity of the route if any. It returns nil if the request must fail because its backend is lost.
+func (s *sticky) selectBackend(w http.ResponseWriter, r *http.Request, u *Upstream, healthy []*Backend) *Backend {
+	if s.header != "" {
+		value := r.Header.Get(s.header)
+		if value == "" {
+			return u.selector.Select(r, healthy)
+		}
+		if b := rendezvous(value, u.Backends); b.Healthy() {
+			return b
+		}
+		if s.fallback == FailRequest {
+			return nil
+		}
+		return rendezvous(value, healthy)
+	}
+
+	cookie, err := r.Cookie(s.cookie)
+	if err == nil {
+		for _, b := range healthy {
+			if b.id == cookie.Value {
+				return b
+			}
+		}
+		if s.fallback == FailRequest {
+			return nil
+		}
+	}
+
+	b := u.selector.Select(r, healthy)
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.cookie,
+		Value:    b.id,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return b
+}
+
+// rendezvous returns the backend with the highest hash for the value, so values keep their backend
+// when other backends are added or removed.
+func rendezvous(value string, backends []*Backend) *Backend {
+	var best *Backend
+	var bestScore uint64
+	for _, b := range backends {
+		h := fnv.New64a()
+		h.Write([]byte(b.id))
+		h.Write([]byte(value))
+		if score := h.Sum64(); best == nil || score > bestScore {
+			best, bestScore = b, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,98 @@
+package gateway_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/gateway"
+)
+
+// TestStickyCookie tests that clients keep their backend and the fallbacks when it is lost
+func TestStickyCookie(t *testing.T) {
+	tests := []struct {
+		name              string
+		fallback          gateway.AffinityFallback
+		expectedStatus    int
+		expectedBackend   string
+		expectedCookieSet bool
+	}{
+		{name: "rebalance", fallback: gateway.Rebalance, expectedStatus: http.StatusOK, expectedBackend: "b", expectedCookieSet: true},
+		{name: "fail request", fallback: gateway.FailRequest, expectedStatus: http.StatusServiceUnavailable, expectedCookieSet: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthA, healthB := http.StatusOK, http.StatusOK
+			backendA := newBackend(t, "a", &healthA)
+			backendB := newBackend(t, "b", &healthB)
+
+			registry := gateway.NewRegistry()
+			upstream, _ := registry.Register("app", gateway.UpstreamOptions{
+				Backends:   []string{backendA.URL, backendB.URL},
+				HealthPath: "/healthz",
+			})
+			handler := registry.Proxy("app", gateway.StickyCookie("backend", tt.fallback))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			cookies := w.Result().Cookies()
+			if len(cookies) != 1 {
+				t.Fatalf("Got %d cookies, want 1", len(cookies))
+			}
+			assertCorrect(t, w.Body.String(), "a /")
+
+			for range 3 {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.AddCookie(cookies[0])
+				w = httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+				assertCorrect(t, w.Body.String(), "a /")
+				assertCorrect(t, len(w.Result().Cookies()), 0)
+			}
+
+			healthA = http.StatusInternalServerError
+			upstream.CheckHealth(context.Background())
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(cookies[0])
+			w = httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			if tt.expectedBackend != "" {
+				assertCorrect(t, w.Body.String(), tt.expectedBackend+" /")
+			}
+			assertCorrect(t, len(w.Result().Cookies()) == 1, tt.expectedCookieSet)
+		})
+	}
+}
+
+// TestStickyHeader tests that requests with the same header value keep their backend
+func TestStickyHeader(t *testing.T) {
+	health := http.StatusOK
+	backends := []string{
+		newBackend(t, "a", &health).URL,
+		newBackend(t, "b", &health).URL,
+		newBackend(t, "c", &health).URL,
+	}
+
+	registry := gateway.NewRegistry()
+	registry.Register("app", gateway.UpstreamOptions{Backends: backends})
+	handler := registry.Proxy("app", gateway.StickyHeader("X-Tenant", gateway.Rebalance))
+
+	seen := map[string]string{}
+	for range 3 {
+		for _, tenant := range []string{"t1", "t2", "t3", "t4"} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Tenant", tenant)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if previous, ok := seen[tenant]; ok {
+				assertCorrect(t, w.Body.String(), previous)
+			}
+			seen[tenant] = w.Body.String()
+		}
+	}
+}
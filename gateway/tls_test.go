@@ -0,0 +1,71 @@
+package gateway_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/gateway"
+)
+
+// TestUpstreamWithCustomCA tests proxying to backends with certificates of a custom CA
+func TestUpstreamWithCustomCA(t *testing.T) {
+	backend := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("secure"))
+	}))
+	defer backend.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: backend.Certificate().Raw}), 0o600)
+
+	tests := []struct {
+		name           string
+		caFile         string
+		expectedStatus int
+	}{
+		{name: "trusted CA", caFile: caFile, expectedStatus: http.StatusOK},
+		{name: "system CAs", caFile: "", expectedStatus: http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := gateway.LoadTLSConfig(tt.caFile, "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			registry := gateway.NewRegistry()
+			registry.Register("secure", gateway.UpstreamOptions{Backends: []string{backend.URL}, TLSConfig: config})
+
+			status, _ := get(registry.Proxy("secure"), "/")
+			assertCorrect(t, status, tt.expectedStatus)
+		})
+	}
+}
+
+// TestLoadTLSConfigWithInvalidFiles tests the errors of configurations with invalid files
+func TestLoadTLSConfigWithInvalidFiles(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, nil, 0o600)
+
+	tests := []struct {
+		name     string
+		caFile   string
+		certFile string
+		keyFile  string
+	}{
+		{name: "missing CA file", caFile: "missing.pem"},
+		{name: "CA file without certificates", caFile: empty},
+		{name: "invalid client certificate", certFile: empty, keyFile: empty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := gateway.LoadTLSConfig(tt.caFile, tt.certFile, tt.keyFile); err == nil {
+				t.Error("LoadTLSConfig() returned no error")
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// ResponseTransformer transforms the responses received from an upstream before they are sent to
+// the client, see [TransformResponse]. Returning an error answers the request with a 502 Bad Gateway.
+type ResponseTransformer interface {
+	Transform(resp *http.Response) error
+}
+
+// TransformerFunc adapts a function to a [ResponseTransformer].
+type TransformerFunc func(resp *http.Response) error
+
+// Transform calls f.
+func (f TransformerFunc) Transform(resp *http.Response) error {
+	return f(resp)
+}
+
+// transformError is an error returned by a ResponseTransformer, which does not mean the backend failed.
+type transformError struct {
+	err error
+}
+
+func (e *transformError) Error() string { return "transforming response: " + e.err.Error() }
+
+func (e *transformError) Unwrap() error { return e.err }
+
+// TransformResponse applies the transformers to the responses of the proxy route, in order,
+// after the rules of [ResponseHeaders].
+func TransformResponse(transformers ...ResponseTransformer) ProxyOption {
+	for _, t := range transformers {
+		if t == nil {
+			panic("transformers parameter cannot contain nil transformers")
+		}
+	}
+	return func(c *proxyConfig) { c.transformers = append(c.transformers, transformers...) }
+}
+
+// MapStatus returns a ResponseTransformer replacing the status codes of the keys of statuses
+// with their values, like mapping the 404 of an upstream to a 410.
+func MapStatus(statuses map[int]int) ResponseTransformer {
+	return TransformerFunc(func(resp *http.Response) error {
+		if status, ok := statuses[resp.StatusCode]; ok {
+			resp.StatusCode = status
+			resp.Status = strconv.Itoa(status) + " " + http.StatusText(status)
+		}
+		return nil
+	})
+}
+
+// RenameHeaders returns a ResponseTransformer renaming the headers of the keys of names to their values.
+func RenameHeaders(names map[string]string) ResponseTransformer {
+	return TransformerFunc(func(resp *http.Response) error {
+		for from, to := range names {
+			if values := resp.Header.Values(from); len(values) > 0 {
+				resp.Header.Del(from)
+				resp.Header[http.CanonicalHeaderKey(to)] = values
+			}
+		}
+		return nil
+	})
+}
+
+// defaultTransformBodySize is the default maximum size of the bodies transformed by [RenameJSONFields].
+const defaultTransformBodySize = 1 << 20
+
+// RenameJSONFields returns a ResponseTransformer renaming the fields of the keys of names to their
+// values in JSON responses, in the top level object or in the objects of a top level array.
+// Responses that are not JSON, or are encoded (like gzip), are left untouched.
+// The fields of the transformed objects are encoded in alphabetical order, and their numbers are kept
+// as received, so large integers like IDs are not rounded. The responses are read in memory, up to
+// maxSize bytes, 1 MiB if zero: longer responses are answered with a 502 Bad Gateway.
+func RenameJSONFields(names map[string]string, maxSize int64) ResponseTransformer {
+	if maxSize <= 0 {
+		maxSize = defaultTransformBodySize
+	}
+	return TransformerFunc(func(resp *http.Response) error {
+		mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if mediaType != "application/json" || resp.Header.Get("Content-Encoding") != "" {
+			return nil
+		}
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		if int64(len(body)) > maxSize {
+			return fmt.Errorf("response body larger than %d bytes", maxSize)
+		}
+
+		doc, err := decodeJSON(body)
+		if err != nil {
+			// Not valid JSON, send it as it was received.
+			setBody(resp, body)
+			return nil
+		}
+		switch v := doc.(type) {
+		case map[string]any:
+			renameFields(v, names)
+		case []any:
+			for _, item := range v {
+				if object, ok := item.(map[string]any); ok {
+					renameFields(object, names)
+				}
+			}
+		}
+
+		transformed, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		setBody(resp, transformed)
+		return nil
+	})
+}
+
+// decodeJSON decodes the JSON document, keeping its numbers as json.Number.
+func decodeJSON(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("invalid data after the JSON document")
+	}
+	return doc, nil
+}
+
+// renameFields renames the fields of the object.
+func renameFields(object map[string]any, names map[string]string) {
+	for from, to := range names {
+		if value, ok := object[from]; ok {
+			delete(object, from)
+			object[to] = value
+		}
+	}
+}
+
+// setBody replaces the body of the response, updating its length.
+func setBody(resp *http.Response, body []byte) {
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+}
@@ -0,0 +1,128 @@
+package gateway_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/gateway"
+)
+
+// newJSONBackend starts a backend answering with the given status and JSON body
+func newJSONBackend(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Upstream-Id", "42")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestTransformResponse tests the transformations of proxied responses
+func TestTransformResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		body           string
+		transformers   []gateway.ResponseTransformer
+		expectedStatus int
+		expectedBody   string
+		expectedHeader string
+	}{
+		{
+			name:           "status mapping",
+			status:         http.StatusNotFound,
+			body:           `{}`,
+			transformers:   []gateway.ResponseTransformer{gateway.MapStatus(map[int]int{http.StatusNotFound: http.StatusGone})},
+			expectedStatus: http.StatusGone,
+			expectedBody:   `{}`,
+		},
+		{
+			name:           "header renames",
+			status:         http.StatusOK,
+			body:           `{}`,
+			transformers:   []gateway.ResponseTransformer{gateway.RenameHeaders(map[string]string{"X-Upstream-Id": "X-Request-Source"})},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{}`,
+			expectedHeader: "42",
+		},
+		{
+			name:           "object field renames",
+			status:         http.StatusOK,
+			body:           `{"user_name":"ada","id":1}`,
+			transformers:   []gateway.ResponseTransformer{gateway.RenameJSONFields(map[string]string{"user_name": "userName"}, 0)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":1,"userName":"ada"}`,
+		},
+		{
+			name:           "array field renames",
+			status:         http.StatusOK,
+			body:           `[{"user_name":"ada"},{"user_name":"alan"},3]`,
+			transformers:   []gateway.ResponseTransformer{gateway.RenameJSONFields(map[string]string{"user_name": "userName"}, 0)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"userName":"ada"},{"userName":"alan"},3]`,
+		},
+		{
+			name:           "large integers",
+			status:         http.StatusOK,
+			body:           `{"user_name":"ada","id":9007199254740993,"score":1.50}`,
+			transformers:   []gateway.ResponseTransformer{gateway.RenameJSONFields(map[string]string{"user_name": "userName"}, 0)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":9007199254740993,"score":1.50,"userName":"ada"}`,
+		},
+		{
+			name:           "body too large",
+			status:         http.StatusOK,
+			body:           `{"user_name":"ada"}`,
+			transformers:   []gateway.ResponseTransformer{gateway.RenameJSONFields(map[string]string{"user_name": "userName"}, 10)},
+			expectedStatus: http.StatusBadGateway,
+			expectedBody:   "Bad Gateway\n",
+		},
+		{
+			name:           "trailing data",
+			status:         http.StatusOK,
+			body:           `{"user_name":"ada"} {}`,
+			transformers:   []gateway.ResponseTransformer{gateway.RenameJSONFields(map[string]string{"user_name": "userName"}, 0)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"user_name":"ada"} {}`,
+		},
+		{
+			name:           "invalid JSON",
+			status:         http.StatusOK,
+			body:           `{"user_name":`,
+			transformers:   []gateway.ResponseTransformer{gateway.RenameJSONFields(map[string]string{"user_name": "userName"}, 0)},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"user_name":`,
+		},
+		{
+			name:   "failing transformer",
+			status: http.StatusOK,
+			body:   `{}`,
+			transformers: []gateway.ResponseTransformer{gateway.TransformerFunc(func(resp *http.Response) error {
+				return errors.New("boom")
+			})},
+			expectedStatus: http.StatusBadGateway,
+			expectedBody:   "Bad Gateway\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newJSONBackend(t, tt.status, tt.body)
+			registry := gateway.NewRegistry()
+			upstream, _ := registry.Register("api", gateway.UpstreamOptions{Backends: []string{backend.URL}, HealthPath: "/healthz"})
+
+			w := httptest.NewRecorder()
+			registry.Proxy("api", gateway.TransformResponse(tt.transformers...)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, w.Header().Get("X-Request-Source"), tt.expectedHeader)
+			assertCorrect(t, len(upstream.HealthyBackends()), 1)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package simplerouter
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// MiddlewareGroup is a set of endpoints sharing the same effective middleware chain.
+type MiddlewareGroup struct {
+	// Middlewares are the names of the middlewares of the chain in execution order, see [FuncName].
+	Middlewares []string `json:"middlewares"`
+	// Patterns are the patterns of the endpoints of the group, including the ones of their aliases.
+	Patterns []string `json:"patterns"`
+}
+
+// MiddlewareGroups groups the endpoints of the route tree by their effective middleware chain, so all
+// the routes behind the same chain (like authentication and auditing) and the public ones can be reviewed
+// together. Middlewares are compared by name, and groups are listed in the order of their first endpoint.
+func (r *Route) MiddlewareGroups() []MiddlewareGroup {
+	groups := []MiddlewareGroup{}
+	index := map[string]int{}
+	for _, endpoint := range r.Endpoints() {
+		names := make([]string, len(endpoint.Middlewares))
+		for i, mw := range endpoint.Middlewares {
+			names[i] = FuncName(mw)
+		}
+
+		key := strings.Join(names, "\x00")
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, MiddlewareGroup{Middlewares: names, Patterns: []string{}})
+		}
+		for _, path := range append([]string{endpoint.Path}, endpoint.Aliases...) {
+			groups[i].Patterns = append(groups[i].Patterns, strings.TrimSpace(endpoint.Method+" "+path))
+		}
+	}
+	return groups
+}
+
+// MiddlewareGroupsText renders the groups of [Route.MiddlewareGroups] as plain text, each chain followed
+// by the patterns behind it:
+//
+//	auth, audit
+//	  GET /api/users
+//	  DELETE /api/users/{id}
+//
+//	(no middlewares)
+//	  GET /healthz
+func (r *Route) MiddlewareGroupsText() string {
+	var b strings.Builder
+	for i, group := range r.MiddlewareGroups() {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if len(group.Middlewares) == 0 {
+			b.WriteString("(no middlewares)\n")
+		} else {
+			b.WriteString(strings.Join(group.Middlewares, ", ") + "\n")
+		}
+		for _, pattern := range group.Patterns {
+			b.WriteString("  " + pattern + "\n")
+		}
+	}
+	return b.String()
+}
+
+// MiddlewareGroupsJSON renders the groups of [Route.MiddlewareGroups] as an indented JSON array.
+func (r *Route) MiddlewareGroupsJSON() []byte {
+	out, _ := json.MarshalIndent(r.MiddlewareGroups(), "", "  ")
+	return out
+}
@@ -0,0 +1,53 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// groupsTree is a tree with public and authenticated routes
+func groupsTree() *r.Route {
+	return r.NewRoute("/api").Add(
+		r.NewRoute("/users").Use(authMiddleware).Add(
+			r.Get(listUsers),
+			r.NewRoute("/{id}").Alias("/people/{id}").Add(r.Delete(listUsers)),
+		),
+		r.NewRoute("/healthz").Add(r.Get(listUsers)),
+		r.NewRoute("/admin").Use(authMiddleware).Add(r.Post(listUsers)),
+		r.NewRoute("/login").Add(r.Post(func(w http.ResponseWriter, req *http.Request) {})),
+	)
+}
+
+// TestMiddlewareGroupsText tests the text rendering of the endpoints grouped by middleware chain
+func TestMiddlewareGroupsText(t *testing.T) {
+	expected := "simplerouter_test.authMiddleware\n" +
+		"  GET /api/users\n" +
+		"  DELETE /api/users/{id}\n" +
+		"  DELETE /api/users/people/{id}\n" +
+		"  POST /api/admin\n" +
+		"\n" +
+		"(no middlewares)\n" +
+		"  GET /api/healthz\n" +
+		"  POST /api/login\n"
+
+	assertCorrect(t, groupsTree().MiddlewareGroupsText(), expected)
+}
+
+// TestMiddlewareGroupsJSON tests the JSON rendering of the endpoints grouped by middleware chain
+func TestMiddlewareGroupsJSON(t *testing.T) {
+	tree := r.NewRoute("/api").Use(authMiddleware).Add(r.NewRoute("/users").Add(r.Get(listUsers)))
+	expected := `[
+  {
+    "middlewares": [
+      "simplerouter_test.authMiddleware"
+    ],
+    "patterns": [
+      "GET /api/users"
+    ]
+  }
+]`
+
+	assertCorrect(t, string(tree.MiddlewareGroupsJSON()), expected)
+}
@@ -0,0 +1,172 @@
+package simplerouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// HandlerE is an http handler that returns an error instead of writing it to the response,
+// so the middlewares wrapping it can handle it. It implements http.Handler, see [HandlerE.ServeHTTP].
+type HandlerE func(w http.ResponseWriter, r *http.Request) error
+
+// MiddlewareE is a middleware wrapping a [HandlerE], so it can handle, wrap or return
+// the errors of the next handlers. Use [MiddlewareE.Middleware] or [Route.UseE] to attach it to routes.
+type MiddlewareE func(next HandlerE) HandlerE
+
+// errorSlotKey is the context key storing where the error of the next handlers is reported to
+// the closest [MiddlewareE] wrapping them.
+type errorSlotKey struct{}
+
+// ServeHTTP calls h and reports its error to the closest [MiddlewareE] wrapping it, even through
+// http.Handler middlewares. If there is none, the error is answered with a 500 Internal Server Error,
+// or as by [BindMiddleware] if it was returned by [Bind].
+func (h HandlerE) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h(w, r); err != nil {
+		reportError(w, r, err)
+	}
+}
+
+// Middleware adapts mw to a [Middleware]. The errors of the handlers wrapped by the returned middleware
+// are returned to mw, including the ones of [HandlerE] handlers behind other http.Handler middlewares.
+// The errors returned by mw are reported in the same way, see [HandlerE.ServeHTTP].
+func (mw MiddlewareE) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return mw(func(w http.ResponseWriter, r *http.Request) error {
+			return serveE(next, w, r)
+		})
+	}
+}
+
+// AdaptMiddleware adapts mw to a [MiddlewareE]. The errors of the next handlers go through mw
+// and are returned by the adapted middleware, so http.Handler middlewares can be part of error chains.
+func AdaptMiddleware(mw Middleware) MiddlewareE {
+	return func(next HandlerE) HandlerE {
+		h := mw(next)
+		return func(w http.ResponseWriter, r *http.Request) error {
+			return serveE(h, w, r)
+		}
+	}
+}
+
+// UseE adds middlewares wrapping [HandlerE] handlers, adapted with [MiddlewareE.Middleware].
+// They can be mixed with the middlewares added by [Route.Use], errors go through both of them.
+func (r *Route) UseE(middlewares ...MiddlewareE) *Route {
+	adapted := make([]Middleware, len(middlewares))
+	for i, mw := range middlewares {
+		if mw == nil {
+			panic("middlewares parameter cannot contain nil middlewares")
+		}
+		adapted[i] = mw.Middleware()
+	}
+	return r.Use(adapted...)
+}
+
+// ErrorHandler writes the response of the errors returned by [HandlerE] handlers, like mapping them
+// to status codes, see [Route.OnError].
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// OnError sets the handler of the errors returned by the [HandlerE] handlers of the route and its child
+// routes, so handlers can return their errors and a single place answers them. It handles the errors
+// not handled by the middlewares of the routes, which are answered with a 500 Internal Server Error
+// otherwise. Child routes can set their own error handler, replacing the one of their parents.
+func (r *Route) OnError(onError ErrorHandler) *Route {
+	if onError == nil {
+		panic("onError parameter cannot be nil")
+	}
+	r.Metadata.OnError = onError
+	return r
+}
+
+// handleErrors returns a middleware answering the errors reported by the handlers it wraps with onError.
+func handleErrors(onError ErrorHandler) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := serveE(next, w, r); err != nil {
+				onError(w, r, err)
+			}
+		})
+	}
+}
+
+// routeE returns a Route with the handler associated to the method and no path,
+// recording the construction as op in the build log.
+func routeE(op, method string, handler HandlerE) *Route {
+	if handler == nil {
+		panic("handler parameter cannot be nil")
+	}
+	return (&Route{Handler: handler.ServeHTTP, Method: method}).recordBuild(op, func() string { return FuncName(handler) })
+}
+
+// Returns a Route with the error-returning handler associated to the GET http method and no path.
+func GetE(handler HandlerE) *Route {
+	return routeE("GetE", http.MethodGet, handler)
+}
+
+// Returns a Route with the error-returning handler associated to the HEAD http method and no path.
+func HeadE(handler HandlerE) *Route {
+	return routeE("HeadE", http.MethodHead, handler)
+}
+
+// Returns a Route with the error-returning handler associated to the POST http method and no path.
+func PostE(handler HandlerE) *Route {
+	return routeE("PostE", http.MethodPost, handler)
+}
+
+// Returns a Route with the error-returning handler associated to the PUT http method and no path.
+func PutE(handler HandlerE) *Route {
+	return routeE("PutE", http.MethodPut, handler)
+}
+
+// Returns a Route with the error-returning handler associated to the PATCH http method and no path.
+func PatchE(handler HandlerE) *Route {
+	return routeE("PatchE", http.MethodPatch, handler)
+}
+
+// Returns a Route with the error-returning handler associated to the DELETE http method and no path.
+func DeleteE(handler HandlerE) *Route {
+	return routeE("DeleteE", http.MethodDelete, handler)
+}
+
+// Returns a Route with the error-returning handler associated to the CONNECT http method and no path.
+func ConnectE(handler HandlerE) *Route {
+	return routeE("ConnectE", http.MethodConnect, handler)
+}
+
+// Returns a Route with the error-returning handler associated to the OPTIONS http method and no path.
+func OptionsE(handler HandlerE) *Route {
+	return routeE("OptionsE", http.MethodOptions, handler)
+}
+
+// Returns a Route with the error-returning handler associated to the TRACE http method and no path.
+func TraceE(handler HandlerE) *Route {
+	return routeE("TraceE", http.MethodTrace, handler)
+}
+
+// Returns a Route with the error-returning handler associated and no path or method, see [All].
+func AllE(handler HandlerE) *Route {
+	return routeE("AllE", "", handler)
+}
+
+// serveE serves the request with h, returning the error reported by the handlers it wraps.
+func serveE(h http.Handler, w http.ResponseWriter, r *http.Request) error {
+	var err error
+	h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), errorSlotKey{}, &err)))
+	return err
+}
+
+// reportError reports err to the closest [MiddlewareE] wrapping the handler, or answers it if there is none.
+func reportError(w http.ResponseWriter, r *http.Request, err error) {
+	if slot, ok := r.Context().Value(errorSlotKey{}).(*error); ok {
+		*slot = err
+		return
+	}
+
+	var bindErr *BindError
+	var validationErr *ValidationError
+	if errors.As(err, &bindErr) || errors.As(err, &validationErr) {
+		writeBindError(w, r, err)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
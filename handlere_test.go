@@ -0,0 +1,134 @@
+package simplerouter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+var errNotFound = errors.New("not found")
+
+// statusMapper creates a middleware answering errNotFound errors with a 404 and returning the others
+func statusMapper(tracker *[]string) r.MiddlewareE {
+	return func(next r.HandlerE) r.HandlerE {
+		return func(w http.ResponseWriter, req *http.Request) error {
+			err := next(w, req)
+			*tracker = append(*tracker, "mapper")
+			if errors.Is(err, errNotFound) {
+				http.Error(w, "missing", http.StatusNotFound)
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// failingHandler creates a handler returning err
+func failingHandler(err error) r.HandlerE {
+	return func(w http.ResponseWriter, req *http.Request) error {
+		if err == nil {
+			w.Write([]byte("ok"))
+		}
+		return err
+	}
+}
+
+// TestUseE tests that errors go through mixed chains of middlewares
+func TestUseE(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedStatus  int
+		expectedBody    string
+		expectedTracker []string
+	}{
+		{name: "no error", err: nil, expectedStatus: http.StatusOK, expectedBody: "ok", expectedTracker: []string{"m1", "mapper"}},
+		{name: "handled error", err: errNotFound, expectedStatus: http.StatusNotFound, expectedBody: "missing\n", expectedTracker: []string{"m1", "mapper"}},
+		{name: "unhandled error", err: errors.New("boom"), expectedStatus: http.StatusInternalServerError, expectedBody: "Internal Server Error\n", expectedTracker: []string{"m1", "mapper"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := []string{}
+			mux := r.NewRoute("/").UseE(statusMapper(&tracker)).Use(middlewareTracker("m1", &tracker)).Add(
+				r.Get(failingHandler(tt.err).ServeHTTP),
+			).Mount()
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, len(tracker), len(tt.expectedTracker))
+			for i := range tracker {
+				assertCorrect(t, tracker[i], tt.expectedTracker[i])
+			}
+		})
+	}
+}
+
+// TestAdaptMiddleware tests that errors go through adapted http.Handler middlewares
+func TestAdaptMiddleware(t *testing.T) {
+	tracker := []string{}
+	handler := r.AdaptMiddleware(middlewareTracker("m1", &tracker))(failingHandler(errNotFound))
+
+	err := handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assertCorrect(t, err, errNotFound)
+	assertCorrect(t, len(tracker), 1)
+}
+
+// TestUseEWithNilMiddleware tests that adding nil middlewares causes a panic
+func TestUseEWithNilMiddleware(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected UseE(nil) to panic, but it didn't")
+		}
+	}()
+
+	r.NewRoute("/").UseE(nil)
+}
+
+// statusWriter creates an error handler writing the given status code and the error
+func statusWriter(status int) r.ErrorHandler {
+	return func(w http.ResponseWriter, req *http.Request, err error) {
+		http.Error(w, err.Error(), status)
+	}
+}
+
+// TestOnError tests that the errors of the tree are answered by the closest error handler
+func TestOnError(t *testing.T) {
+	tracker := []string{}
+	mux := r.NewRoute("").OnError(statusWriter(http.StatusBadRequest)).Add(
+		r.NewRoute("/items").Add(r.GetE(failingHandler(errors.New("bad item")))),
+		r.NewRoute("/ok").Add(r.PostE(failingHandler(nil))),
+		r.NewRoute("/mapped").UseE(statusMapper(&tracker)).Add(r.GetE(failingHandler(errNotFound))),
+		r.NewRoute("/admin").OnError(statusWriter(http.StatusForbidden)).Add(r.DeleteE(failingHandler(errors.New("denied")))),
+	).Mount()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "inherited handler", method: http.MethodGet, path: "/items", expectedStatus: http.StatusBadRequest, expectedBody: "bad item\n"},
+		{name: "no error", method: http.MethodPost, path: "/ok", expectedStatus: http.StatusOK, expectedBody: "ok"},
+		{name: "handled by middleware", method: http.MethodGet, path: "/mapped", expectedStatus: http.StatusNotFound, expectedBody: "missing\n"},
+		{name: "overridden handler", method: http.MethodDelete, path: "/admin", expectedStatus: http.StatusForbidden, expectedBody: "denied\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Package har records HTTP traffic in the HTTP Archive (HAR) 1.2 format, readable by browsers,
+// proxies and replay tools. It is used by the traffic capture of gateway proxy routes and
+// by the recordings of the tests of route trees.
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Version is the version of the HAR format written by this package.
+const Version = "1.2"
+
+// File is the top level object of a HAR file.
+type File struct {
+	Log Log `json:"log"`
+}
+
+// Log holds the entries of a HAR file.
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+// Creator identifies the application writing a HAR file.
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// NewFile returns a HAR file created by simplerouter with the given entries.
+func NewFile(entries ...Entry) File {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	return File{Log: Log{Version: Version, Creator: Creator{Name: "simplerouter", Version: Version}, Entries: entries}}
+}
+
+// WriteFile writes the HAR file as indented JSON to the named file.
+func (f File) WriteFile(name string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
+
+// Entry is an exchanged request and response.
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	// Time is the total time of the exchange in milliseconds.
+	Time     float64  `json:"time"`
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+	Cache    struct{} `json:"cache"`
+	Timings  Timings  `json:"timings"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+// NewEntry returns the entry of an exchange started at the given time and lasting duration.
+// Its time is reported as the wait time of the timings, the only one known by servers.
+func NewEntry(started time.Time, duration time.Duration, req Request, resp Response) Entry {
+	ms := float64(duration.Microseconds()) / 1000
+	return Entry{
+		StartedDateTime: started,
+		Time:            ms,
+		Request:         req,
+		Response:        resp,
+		Timings:         Timings{Send: 0, Wait: ms, Receive: 0},
+	}
+}
+
+// Request is the request of an entry.
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	// HeadersSize is always -1, as it is unknown.
+	HeadersSize int64 `json:"headersSize"`
+	// BodySize is the size of the body in bytes, before any truncation.
+	BodySize int64 `json:"bodySize"`
+}
+
+// NewRequest returns the request of an entry for r, with the given body.
+// The URL of server side requests is completed with their host.
+func NewRequest(r *http.Request, body []byte) Request {
+	u := *r.URL
+	if u.Host == "" {
+		u.Host = r.Host
+	}
+	if u.Scheme == "" {
+		u.Scheme = "http"
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+
+	req := Request{
+		Method:      r.Method,
+		URL:         u.String(),
+		HTTPVersion: r.Proto,
+		Cookies:     []Cookie{},
+		Headers:     nameValues(r.Header),
+		QueryString: nameValues(u.Query()),
+		HeadersSize: -1,
+		BodySize:    int64(len(body)),
+	}
+	for _, c := range r.Cookies() {
+		req.Cookies = append(req.Cookies, Cookie{Name: c.Name, Value: c.Value})
+	}
+	if len(body) > 0 {
+		text, _ := encodeBody(body)
+		req.PostData = &PostData{MimeType: r.Header.Get("Content-Type"), Text: text}
+	}
+	return req
+}
+
+// Response is the response of an entry.
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	// HeadersSize is always -1, as it is unknown.
+	HeadersSize int64 `json:"headersSize"`
+	// BodySize is the size of the body in bytes, before any truncation.
+	BodySize int64 `json:"bodySize"`
+}
+
+// NewResponse returns the response of an entry with the given status, header and body.
+func NewResponse(status int, header http.Header, body []byte) Response {
+	text, encoding := encodeBody(body)
+	resp := Response{
+		Status:      status,
+		StatusText:  http.StatusText(status),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []Cookie{},
+		Headers:     nameValues(header),
+		Content:     Content{Size: int64(len(body)), MimeType: header.Get("Content-Type"), Text: text, Encoding: encoding},
+		RedirectURL: header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    int64(len(body)),
+	}
+	for _, c := range (&http.Response{Header: header}).Cookies() {
+		resp.Cookies = append(resp.Cookies, Cookie{Name: c.Name, Value: c.Value, Path: c.Path, Domain: c.Domain, HTTPOnly: c.HttpOnly, Secure: c.Secure})
+	}
+	return resp
+}
+
+// Content is the body of a response.
+type Content struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	// Encoding is "base64" for bodies that are not valid UTF-8 text.
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// PostData is the body of a request.
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Cookie is a cookie of a request or response.
+type Cookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+// NameValue is a header or query parameter.
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Timings are the durations of the phases of an exchange in milliseconds, -1 if they do not apply.
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// nameValues returns the values of the header or query sorted by name.
+func nameValues(values map[string][]string) []NameValue {
+	pairs := []NameValue{}
+	for name, vs := range values {
+		for _, v := range vs {
+			pairs = append(pairs, NameValue{Name: name, Value: v})
+		}
+	}
+	slices.SortStableFunc(pairs, func(a, b NameValue) int { return strings.Compare(a.Name, b.Name) })
+	return pairs
+}
+
+// encodeBody returns the text of a body and its encoding, base64 if it is not valid UTF-8.
+func encodeBody(body []byte) (text, encoding string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
@@ -0,0 +1,102 @@
+package har_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlos-el/simplerouter/har"
+)
+
+func assertCorrect(t testing.TB, got, want any) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+// TestNewEntry tests the entries built from requests and responses
+func TestNewEntry(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/search?q=go&q=http", strings.NewReader(`{"a":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	header := http.Header{"Content-Type": {"image/png"}, "Location": {"/next"}}
+
+	entry := har.NewEntry(
+		time.Unix(0, 0),
+		1500*time.Microsecond,
+		har.NewRequest(req, []byte(`{"a":1}`)),
+		har.NewResponse(http.StatusSeeOther, header, []byte{0x89, 'P', 'N', 'G'}),
+	)
+
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{name: "url", got: entry.Request.URL, expected: "http://example.com/search?q=go&q=http"},
+		{name: "query", got: len(entry.Request.QueryString), expected: 2},
+		{name: "cookie", got: entry.Request.Cookies[0].Value, expected: "dark"},
+		{name: "post data", got: entry.Request.PostData.Text, expected: `{"a":1}`},
+		{name: "post data type", got: entry.Request.PostData.MimeType, expected: "application/json"},
+		{name: "status text", got: entry.Response.StatusText, expected: "See Other"},
+		{name: "redirect", got: entry.Response.RedirectURL, expected: "/next"},
+		{name: "binary body", got: entry.Response.Content.Text, expected: "iVBORw=="},
+		{name: "binary body encoding", got: entry.Response.Content.Encoding, expected: "base64"},
+		{name: "time", got: entry.Time, expected: 1.5},
+		{name: "wait", got: entry.Timings.Wait, expected: 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCorrect(t, tt.got, tt.expected)
+		})
+	}
+}
+
+// TestWriteFile tests the HAR files written
+func TestWriteFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "traffic.har")
+	if err := har.NewFile().WriteFile(name); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file har.File
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	assertCorrect(t, file.Log.Version, "1.2")
+	assertCorrect(t, strings.Contains(string(data), `"entries": []`), true)
+}
+
+// TestRedactionHeader tests the redacted copies of headers
+func TestRedactionHeader(t *testing.T) {
+	header := http.Header{"Authorization": {"Bearer secret"}, "X-Api-Key": {"key"}, "Accept": {"*/*"}}
+	redacted := har.Redaction{Headers: []string{"x-api-key"}}.Header(header)
+
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{name: "default header", got: redacted.Get("Authorization"), expected: har.Redacted},
+		{name: "configured header", got: redacted.Get("X-Api-Key"), expected: har.Redacted},
+		{name: "other header", got: redacted.Get("Accept"), expected: "*/*"},
+		{name: "original header", got: header.Get("Authorization"), expected: "Bearer secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCorrect(t, tt.got, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package har
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Record returns a handler serving the requests with next and passing the entry of each exchange to fn
+// once it is served, along with its request. The response is recorded as sent to the client, and the part
+// of the request body not read by next is read after it returns. Bodies are stored up to maxBodySize bytes,
+// or entirely if it is not positive, the truncated ones are commented.
+func Record(next http.Handler, maxBodySize int, fn func(r *http.Request, entry Entry)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		reqBody := &recordedBody{max: maxBodySize}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.TeeReader(r.Body, reqBody), r.Body}
+		}
+		rw := &recordWriter{ResponseWriter: w, body: recordedBody{max: maxBodySize}}
+		next.ServeHTTP(rw, r)
+		if r.Body != nil && maxBodySize > 0 {
+			io.CopyN(io.Discard, r.Body, int64(maxBodySize))
+		} else if r.Body != nil {
+			io.Copy(io.Discard, r.Body)
+		}
+		if rw.status == 0 {
+			rw.status, rw.header = http.StatusOK, w.Header().Clone()
+		}
+
+		req := NewRequest(r, reqBody.buf.Bytes())
+		req.BodySize = reqBody.size
+		resp := NewResponse(rw.status, rw.header, rw.body.buf.Bytes())
+		resp.BodySize = rw.body.size
+		resp.Content.Size = rw.body.size
+		if rw.body.truncated() {
+			resp.Content.Comment = fmt.Sprintf("truncated to %d bytes", maxBodySize)
+		}
+
+		entry := NewEntry(started, time.Since(started), req, resp)
+		if reqBody.truncated() {
+			entry.Comment = fmt.Sprintf("request body truncated to %d bytes", maxBodySize)
+		}
+		fn(r, entry)
+	})
+}
+
+// recordedBody stores the first max bytes written to it, or all of them if max is not positive,
+// counting all of them.
+type recordedBody struct {
+	buf  bytes.Buffer
+	max  int
+	size int64
+}
+
+// Write stores the part of p fitting in the body.
+func (b *recordedBody) Write(p []byte) (int, error) {
+	b.size += int64(len(p))
+	if b.max <= 0 {
+		b.buf.Write(p)
+	} else if room := b.max - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(room, len(p))])
+	}
+	return len(p), nil
+}
+
+// truncated reports whether bytes were left out of the body.
+func (b *recordedBody) truncated() bool {
+	return b.size > int64(b.buf.Len())
+}
+
+// recordWriter is an http.ResponseWriter recording the response written through it.
+type recordWriter struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   recordedBody
+}
+
+// WriteHeader records the status code and headers of the response and writes them.
+func (rw *recordWriter) WriteHeader(statusCode int) {
+	if rw.status == 0 && statusCode >= 200 {
+		rw.status = statusCode
+		rw.header = rw.Header().Clone()
+	}
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the bytes written to the response body.
+func (rw *recordWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying http.ResponseWriter, for http.ResponseController.
+func (rw *recordWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
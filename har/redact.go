@@ -0,0 +1,89 @@
+package har
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces the redacted values of entries.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedHeaders are the headers carrying credentials, redacted by [Redaction.Apply] in addition
+// to the configured ones.
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Redaction lists the values hidden from entries before they are stored, so secrets do not leak into them.
+type Redaction struct {
+	// Headers are the names of the request and response headers redacted, besides [DefaultRedactedHeaders].
+	Headers []string
+	// Query are the names of the query parameters redacted, like "api_key".
+	Query []string
+}
+
+// Apply replaces the redacted values of the entry with [Redacted]. The values of the cookies are
+// redacted along with the Cookie and Set-Cookie headers.
+func (rd Redaction) Apply(e *Entry) {
+	headers := rd.headers()
+	redactHeaders(e.Request.Headers, headers)
+	redactHeaders(e.Response.Headers, headers)
+	for i := range e.Request.Cookies {
+		e.Request.Cookies[i].Value = Redacted
+	}
+	for i := range e.Response.Cookies {
+		e.Response.Cookies[i].Value = Redacted
+	}
+
+	if len(rd.Query) == 0 {
+		return
+	}
+	for i, param := range e.Request.QueryString {
+		if slices.Contains(rd.Query, param.Name) {
+			e.Request.QueryString[i].Value = Redacted
+		}
+	}
+	if u, err := url.Parse(e.Request.URL); err == nil {
+		query := u.Query()
+		for _, name := range rd.Query {
+			if values, ok := query[name]; ok {
+				for i := range values {
+					values[i] = Redacted
+				}
+			}
+		}
+		u.RawQuery = query.Encode()
+		e.Request.URL = strings.ReplaceAll(u.String(), url.QueryEscape(Redacted), Redacted)
+	}
+}
+
+// Header returns a copy of h with the values of the redacted headers replaced with [Redacted], to hide
+// them from other records of the requests, like dumps and logs.
+func (rd Redaction) Header(h http.Header) http.Header {
+	headers := rd.headers()
+	h = h.Clone()
+	for name := range h {
+		if headers[http.CanonicalHeaderKey(name)] {
+			h[name] = []string{Redacted}
+		}
+	}
+	return h
+}
+
+// headers returns the set of the canonical names of the redacted headers.
+func (rd Redaction) headers() map[string]bool {
+	headers := map[string]bool{}
+	for _, name := range slices.Concat(DefaultRedactedHeaders, rd.Headers) {
+		headers[http.CanonicalHeaderKey(name)] = true
+	}
+	return headers
+}
+
+// redactHeaders replaces the values of the redacted headers.
+func redactHeaders(pairs []NameValue, redacted map[string]bool) {
+	for i, pair := range pairs {
+		if redacted[http.CanonicalHeaderKey(pair.Name)] {
+			pairs[i].Value = Redacted
+		}
+	}
+}
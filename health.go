@@ -0,0 +1,186 @@
+package simplerouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthCheck reports whether a dependency of the service is working, returning nil if it is.
+// It should give up once ctx is done.
+type HealthCheck func(ctx context.Context) error
+
+// healthCheck is a named check registered in [HealthChecks].
+type healthCheck struct {
+	name     string
+	check    HealthCheck
+	liveness bool
+}
+
+// HealthChecks holds the liveness and readiness checks of a service and serves their status, see [Health].
+// It is safe for concurrent use, so checks can be added after mounting its routes.
+type HealthChecks struct {
+	mu         sync.RWMutex
+	checks     []healthCheck
+	timeout    time.Duration
+	showErrors bool
+}
+
+// Health returns empty [HealthChecks], whose routes answer that the service is healthy until checks are added:
+//
+//	health := simplerouter.Health().
+//		AddCheck("database", db.PingContext).
+//		AddLivenessCheck("deadlock", detector.Check)
+//	router.Add(health.Route())
+//
+// Orchestrators like Kubernetes restart the service when its liveness route fails, and stop sending it
+// traffic while its readiness route fails.
+func Health() *HealthChecks {
+	return &HealthChecks{timeout: 5 * time.Second}
+}
+
+// AddCheck adds a readiness check, run by the readiness route: a failing dependency, like a database,
+// makes the service unready until it recovers, without restarting it.
+// It panics if name is empty or check is nil.
+func (h *HealthChecks) AddCheck(name string, check HealthCheck) *HealthChecks {
+	return h.add(name, check, false)
+}
+
+// AddLivenessCheck adds a liveness check, run by both routes: it should only fail when the service cannot
+// recover without a restart, like a deadlock, as it is restarted.
+// It panics if name is empty or check is nil.
+func (h *HealthChecks) AddLivenessCheck(name string, check HealthCheck) *HealthChecks {
+	return h.add(name, check, true)
+}
+
+// add adds a named check.
+func (h *HealthChecks) add(name string, check HealthCheck, liveness bool) *HealthChecks {
+	if name == "" || check == nil {
+		panic("name and check parameters cannot be empty")
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, healthCheck{name: name, check: check, liveness: liveness})
+	return h
+}
+
+// Timeout sets how long each check can run before it is considered failed, 5 seconds by default.
+func (h *HealthChecks) Timeout(d time.Duration) *HealthChecks {
+	if d <= 0 {
+		panic("d parameter must be greater than zero")
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.timeout = d
+	return h
+}
+
+// ShowErrors includes the errors of the failing checks in the responses of the health routes. They are
+// left out by default, as they can reveal internal details, like hosts or credentials, to any client
+// reaching the routes; show them when the routes are only reachable by the orchestrator or operators.
+func (h *HealthChecks) ShowErrors() *HealthChecks {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.showErrors = true
+	return h
+}
+
+// Route returns a Route serving the liveness route on "/healthz" and the readiness route on "/readyz",
+// see [HealthChecks.Liveness] and [HealthChecks.Readiness].
+func (h *HealthChecks) Route() *Route {
+	return NewRoute("").Add(
+		NewRoute("/healthz").Add(h.Liveness()),
+		NewRoute("/readyz").Add(h.Readiness()),
+	)
+}
+
+// Liveness returns a GET Route running the liveness checks, to be added under any path.
+// It is served during maintenances, see [Route.MaintenanceExempt].
+// It answers with a 200 OK if all of them pass, or a 503 Service Unavailable otherwise, and a JSON
+// object with the overall status and the status of each check, with its error if [HealthChecks.ShowErrors]
+// is set:
+//
+//	{"status":"fail","checks":{"deadlock":{"status":"fail","error":"worker stuck for 5m0s"}}}
+func (h *HealthChecks) Liveness() *Route {
+	return Get(h.serve(true)).NoIndex().MaintenanceExempt()
+}
+
+// Readiness returns a GET Route running all the checks, the readiness and the liveness ones,
+// answering as [HealthChecks.Liveness].
+func (h *HealthChecks) Readiness() *Route {
+	return Get(h.serve(false)).NoIndex().MaintenanceExempt()
+}
+
+// healthStatus is the status of a check in the responses of the health routes.
+type healthStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// serve returns a handler running the liveness checks, or all of them, concurrently.
+func (h *HealthChecks) serve(liveness bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.mu.RLock()
+		checks, timeout, showErrors := h.checks, h.timeout, h.showErrors
+		h.mu.RUnlock()
+
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			statuses = map[string]healthStatus{}
+			healthy  = true
+		)
+		for _, c := range checks {
+			if liveness && !c.liveness {
+				continue
+			}
+			wg.Go(func() {
+				err := runCheck(r.Context(), c.check, timeout)
+				status := healthStatus{Status: "ok"}
+				if err != nil {
+					status.Status = "fail"
+					if showErrors {
+						status.Error = err.Error()
+					}
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				statuses[c.name] = status
+				healthy = healthy && err == nil
+			})
+		}
+		wg.Wait()
+
+		response := struct {
+			Status string                  `json:"status"`
+			Checks map[string]healthStatus `json:"checks"`
+		}{Status: "ok", Checks: statuses}
+		code := http.StatusOK
+		if !healthy {
+			response.Status, code = "fail", http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// runCheck runs the check, failing it once the timeout expires even if it does not give up.
+func runCheck(ctx context.Context, check HealthCheck, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() { result <- check(ctx) }()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
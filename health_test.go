@@ -0,0 +1,98 @@
+package simplerouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestHealth tests the status of the liveness and readiness routes with passing and failing checks
+func TestHealth(t *testing.T) {
+	databaseErr := error(nil)
+	health := r.Health().
+		AddCheck("database", func(ctx context.Context) error { return databaseErr }).
+		AddLivenessCheck("worker", func(ctx context.Context) error { return nil })
+	mux := r.NewRoute("").Add(health.Route()).Mount()
+
+	tests := []struct {
+		name           string
+		databaseErr    error
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "live",
+			path:           "/healthz",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"ok","checks":{"worker":{"status":"ok"}}}`,
+		},
+		{
+			name:           "ready",
+			path:           "/readyz",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"ok","checks":{"database":{"status":"ok"},"worker":{"status":"ok"}}}`,
+		},
+		{
+			name:           "live with failing readiness check",
+			databaseErr:    errors.New("connection refused"),
+			path:           "/healthz",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"ok","checks":{"worker":{"status":"ok"}}}`,
+		},
+		{
+			name:           "unready with failing readiness check",
+			databaseErr:    errors.New("connection refused"),
+			path:           "/readyz",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"status":"fail","checks":{"database":{"status":"fail"},"worker":{"status":"ok"}}}`,
+		},
+		{
+			name:           "unready with failing readiness check without message",
+			databaseErr:    errors.New(""),
+			path:           "/readyz",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"status":"fail","checks":{"database":{"status":"fail"},"worker":{"status":"ok"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			databaseErr = tt.databaseErr
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody+"\n")
+			assertCorrect(t, w.Header().Get("Content-Type"), "application/json")
+			assertCorrect(t, w.Header().Get("X-Robots-Tag"), "noindex")
+		})
+	}
+}
+
+// TestHealthWithTimeout tests that checks running past the timeout fail, with the errors shown
+func TestHealthWithTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	health := r.Health().Timeout(10*time.Millisecond).ShowErrors().AddLivenessCheck("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	mux := r.NewRoute("/live").Add(health.Liveness()).Mount()
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/live", nil))
+
+	var body struct {
+		Checks map[string]struct{ Error string }
+	}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	assertCorrect(t, w.Code, http.StatusServiceUnavailable)
+	assertCorrect(t, body.Checks["stuck"].Error, context.DeadlineExceeded.Error())
+}
@@ -0,0 +1,28 @@
+// Package recorder provides the response recorder shared by the helpers serving synthetic requests,
+// like the self test of simplerouter and the routertest package.
+package recorder
+
+import (
+	"net/http"
+	"net/http/httptest"
+)
+
+// ResponseRecorder is an httptest.ResponseRecorder recording the final status code of the response:
+// informational status codes, like the 103 Early Hints written for the routes with preloaded assets,
+// are ignored, except 101 Switching Protocols, which ends the HTTP response.
+type ResponseRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+// New returns an initialized ResponseRecorder.
+func New() *ResponseRecorder {
+	return &ResponseRecorder{ResponseRecorder: httptest.NewRecorder()}
+}
+
+// WriteHeader records the status code, unless it is informational.
+func (r *ResponseRecorder) WriteHeader(code int) {
+	if code >= 100 && code <= 199 && code != http.StatusSwitchingProtocols {
+		return
+	}
+	r.ResponseRecorder.WriteHeader(code)
+}
@@ -0,0 +1,35 @@
+package recorder_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/internal/recorder"
+)
+
+// TestResponseRecorder tests that the informational status codes are not recorded
+func TestResponseRecorder(t *testing.T) {
+	tests := []struct {
+		name           string
+		statuses       []int
+		expectedStatus int
+	}{
+		{name: "early hints", statuses: []int{http.StatusEarlyHints, http.StatusNotFound}, expectedStatus: http.StatusNotFound},
+		{name: "implicit status", statuses: []int{http.StatusEarlyHints}, expectedStatus: http.StatusOK},
+		{name: "switching protocols", statuses: []int{http.StatusSwitchingProtocols}, expectedStatus: http.StatusSwitchingProtocols},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := recorder.New()
+			for _, status := range tt.statuses {
+				w.WriteHeader(status)
+			}
+			w.Write([]byte("body"))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("got %d want %d", w.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
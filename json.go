@@ -0,0 +1,85 @@
+package simplerouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// JSON returns a Route with a handler for the given HTTP method that decodes the JSON body of the
+// requests into a Req, calls fn with it, and encodes the returned Resp as the JSON response,
+// so endpoints can be written as plain functions:
+//
+//	func createUser(ctx context.Context, user User) (User, error) { ... }
+//
+//	router.Add(simplerouter.NewRoute("/users").Add(simplerouter.JSON(http.MethodPost, createUser)))
+//
+// The bodies of GET, HEAD and DELETE requests are not decoded, fn receives the zero value of Req.
+// Requests with a body that is not JSON are answered with [JSONError], with 415 Unsupported Media Type
+// if their content type is not JSON and 400 Bad Request if it does not decode. Struct requests are validated
+// as by [Bind], and answered with a 422 Unprocessable Entity if they are not valid. Responses are written with
+// 201 Created for POST requests and 200 OK otherwise. The errors returned by fn, and the ones encoding Resp,
+// are returned by the handler before the response is written, so they are handled by the error middlewares
+// and error handler of the route, see [Route.OnError].
+func JSON[Req, Resp any](method string, fn func(ctx context.Context, req Req) (Resp, error)) *Route {
+	if fn == nil {
+		panic("fn parameter cannot be nil")
+	}
+	handler := HandlerE(func(w http.ResponseWriter, r *http.Request) error {
+		var req Req
+		if hasJSONBody(r.Method) {
+			if !isJSON(r.Header.Get("Content-Type")) {
+				JSONError(w, r, http.StatusUnsupportedMediaType)
+				return nil
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				JSONError(w, r, http.StatusBadRequest)
+				return nil
+			}
+		}
+		if reflect.TypeFor[Req]().Kind() == reflect.Struct {
+			if err := validateBound(r, &req); err != nil {
+				writeBindError(w, r, err)
+				return nil
+			}
+		}
+
+		resp, err := fn(r.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		// The response is encoded before anything is written, so the encoding errors are answered by the
+		// error handler instead of a truncated body with a success status.
+		var body bytes.Buffer
+		if err := json.NewEncoder(&body).Encode(resp); err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_, err = w.Write(body.Bytes())
+		return err
+	})
+	return (&Route{Handler: handler.ServeHTTP, Method: method}).recordBuild("JSON", func() string { return FuncName(fn) })
+}
+
+// hasJSONBody reports whether the requests with the given method have a body decoded by [JSON].
+func hasJSONBody(method string) bool {
+	return method != http.MethodGet && method != http.MethodHead && method != http.MethodDelete
+}
+
+// isJSON reports whether the content type is JSON, like "application/json" or "application/problem+json".
+// Requests without content type are considered JSON.
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
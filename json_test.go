@@ -0,0 +1,82 @@
+package simplerouter_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// createUser is a typed handler creating users, failing for empty names
+func createUser(ctx context.Context, u user) (user, error) {
+	if u.Name == "" {
+		return user{}, errors.New("empty name")
+	}
+	u.ID = 1
+	return u, nil
+}
+
+// getUser is a typed handler without request body
+func getUser(ctx context.Context, _ struct{}) (user, error) {
+	return user{ID: 7, Name: "ada"}, nil
+}
+
+// TestJSON tests the requests decoded and the responses encoded by typed JSON handlers
+func TestJSON(t *testing.T) {
+	mux := r.NewRoute("").OnError(func(w http.ResponseWriter, req *http.Request, err error) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	}).Add(
+		r.NewRoute("/users").Add(
+			r.JSON(http.MethodPost, createUser),
+			r.JSON(http.MethodGet, getUser),
+		),
+		r.NewRoute("/scores").Add(r.JSON(http.MethodPost, func(ctx context.Context, _ struct{}) (float64, error) {
+			return math.NaN(), nil
+		})),
+	).Mount()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "created", method: http.MethodPost, contentType: "application/json", body: `{"name":"ada"}`, expectedStatus: http.StatusCreated, expectedBody: `{"id":1,"name":"ada"}` + "\n"},
+		{name: "without body", method: http.MethodGet, expectedStatus: http.StatusOK, expectedBody: `{"id":7,"name":"ada"}` + "\n"},
+		{name: "invalid body", method: http.MethodPost, contentType: "application/json", body: `{"name":`, expectedStatus: http.StatusBadRequest, expectedBody: `{"error":"Bad Request"}` + "\n"},
+		{name: "not JSON", method: http.MethodPost, contentType: "text/plain", body: `ada`, expectedStatus: http.StatusUnsupportedMediaType, expectedBody: `{"error":"Unsupported Media Type"}` + "\n"},
+		{name: "error", method: http.MethodPost, contentType: "application/json; charset=utf-8", body: `{}`, expectedStatus: http.StatusUnprocessableEntity, expectedBody: "empty name\n"},
+		{name: "unencodable response", method: http.MethodPost, path: "/scores", contentType: "application/json", body: `{}`, expectedStatus: http.StatusUnprocessableEntity, expectedBody: "json: unsupported value: NaN\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path == "" {
+				path = "/users"
+			}
+			req := httptest.NewRequest(tt.method, path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
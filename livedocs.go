@@ -0,0 +1,187 @@
+package simplerouter
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// liveDocsTemplate is the HTML reference page of the endpoints of a route tree, with a console
+// sending requests to the endpoints from the browser.
+var liveDocsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>API reference</title>
+  <style>
+    body { font-family: sans-serif; max-width: 960px; margin: 0 auto; padding: 1em; }
+    section { border: 1px solid #ddd; border-radius: 4px; margin: 1em 0; padding: 0.5em 1em; }
+    .method { font-weight: bold; font-family: monospace; margin-right: 0.5em; }
+    .path { font-family: monospace; }
+    .deprecated .path { text-decoration: line-through; }
+    .tag { background: #eee; border-radius: 3px; padding: 0 0.3em; margin-left: 0.3em; font-size: 0.8em; }
+    textarea { width: 100%; font-family: monospace; }
+    pre { background: #f6f6f6; padding: 0.5em; overflow: auto; }
+  </style>
+</head>
+<body>
+  <h1>API reference</h1>
+{{- range $i, $e := .}}
+  <section{{if $e.Deprecated}} class="deprecated"{{end}}>
+    <h2><span class="method">{{$e.Method}}</span><span class="path">{{$e.Path}}</span>{{range $e.Tags}}<span class="tag">{{.}}</span>{{end}}</h2>
+    {{- if $e.Summary}}
+    <p><strong>{{$e.Summary}}</strong></p>
+    {{- end}}
+    {{- if $e.Description}}
+    <p>{{$e.Description}}</p>
+    {{- end}}
+    <details>
+      <summary>Try it out</summary>
+      <form data-method="{{$e.Method}}" data-path="{{$e.Path}}">
+        {{- range $e.Params}}
+        <p><label>{{.}} <input name="{{.}}" required></label></p>
+        {{- end}}
+        {{- if eq $e.Method "ALL"}}
+        <p><label>Method <input name="method" value="GET"></label></p>
+        {{- end}}
+        <p><label>Query <input name="query" placeholder="a=1&amp;b=2"></label></p>
+        <p><label>Body<textarea name="body" rows="4">{{$e.Body}}</textarea></label></p>
+        <button>Send</button>
+        <pre hidden></pre>
+      </form>
+    </details>
+  </section>
+{{- end}}
+  <script>
+    document.querySelectorAll("form").forEach(form => form.addEventListener("submit", async event => {
+      event.preventDefault();
+      const data = new FormData(form);
+      const method = form.dataset.method === "ALL" ? data.get("method") : form.dataset.method;
+      let path = form.dataset.path.replace(/\{([^}.]+)(\.\.\.)?\}/g, (_, name, rest) =>
+        rest ? encodeURI(data.get(name)) : encodeURIComponent(data.get(name)));
+      if (data.get("query")) {
+        path += "?" + data.get("query");
+      }
+      const init = { method: method, headers: {} };
+      if (data.get("body") && method !== "GET" && method !== "HEAD") {
+        init.body = data.get("body");
+        init.headers["Content-Type"] = "application/json";
+      }
+      const output = form.querySelector("pre");
+      output.hidden = false;
+      try {
+        const response = await fetch(path, init);
+        output.textContent = response.status + " " + response.statusText + "\n\n" + await response.text();
+      } catch (error) {
+        output.textContent = String(error);
+      }
+    }));
+  </script>
+</body>
+</html>
+`))
+
+// liveDocsEndpoint is an endpoint documented in the page of [Route.Docs].
+type liveDocsEndpoint struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	// Params are the names of the path wildcards of the endpoint.
+	Params []string
+	// Body is the JSON request body of the first example of the endpoint, if any.
+	Body string
+}
+
+// Docs adds to the route a child route serving on path an HTML reference of the endpoints of the tree,
+// generated from their metadata like [Route.OpenAPI], with a console to try them out: requests are sent
+// from the browser to the same server, so they go through the same router and middlewares as any other.
+// Guard middlewares, like an authentication middleware, are applied to the documentation route only:
+//
+//	router.Docs("/docs", requireAdmin)
+//
+// The page is generated on the first request, so it includes the routes added after calling Docs.
+// The paths of the page include the ones of the parents of the route and the base path of the mounted
+// tree, see [WithBasePath], so the console reaches the endpoints wherever the tree is mounted.
+// The documentation route itself is left out of the page and marked with [Route.NoIndex].
+func (r *Route) Docs(path string, guard ...Middleware) *Route {
+	docs := NewRoute(path).NoIndex()
+	if len(guard) > 0 {
+		docs.Use(guard...)
+	}
+	// The pages are generated for each prefix the documentation route is served under.
+	var pages sync.Map
+	page := func(prefix string) []byte {
+		if b, ok := pages.Load(prefix); ok {
+			return b.([]byte)
+		}
+		tree := *r
+		tree.Routes = slices.DeleteFunc(slices.Clone(r.Routes), func(route *Route) bool { return route == docs })
+
+		var endpoints []liveDocsEndpoint
+		for _, endpoint := range tree.Endpoints() {
+			e := liveDocsEndpoint{
+				Method:      methodName(endpoint.Method),
+				Path:        prefix + openAPIPath(endpoint.Path),
+				Summary:     endpoint.Metadata.Summary,
+				Description: endpoint.Metadata.Description,
+				Tags:        endpoint.Metadata.Tags,
+				Deprecated:  endpoint.Metadata.Deprecated,
+			}
+			for _, match := range wildcardRegexp.FindAllStringSubmatch(endpoint.Path, -1) {
+				e.Params = append(e.Params, match[1])
+			}
+			for _, example := range endpoint.Metadata.Examples {
+				if example.Request != nil {
+					body, _ := json.MarshalIndent(example.Request, "", "  ")
+					e.Body = string(body)
+					break
+				}
+			}
+			endpoints = append(endpoints, e)
+		}
+
+		var b bytes.Buffer
+		liveDocsTemplate.Execute(&b, endpoints)
+		pages.Store(prefix, b.Bytes())
+		return b.Bytes()
+	}
+
+	docs.Add(
+		Get(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(page(docsPrefix(req, docs.chainPaths(r.chainPaths([]string{""})))))
+		}),
+	)
+	return r.Add(docs)
+}
+
+// docsPrefix returns the prefix of the paths of the page of [Route.Docs] served for the request: the
+// path its pattern adds to docsPaths, the paths of the documentation route in the documented tree,
+// without the host of the pattern.
+func docsPrefix(req *http.Request, docsPaths []string) string {
+	info := RouteInfoFrom(req.Context())
+	if info == nil {
+		return ""
+	}
+	prefix := info.BasePath
+	for _, p := range docsPaths {
+		if strings.HasSuffix(info.Pattern, p) {
+			prefix = strings.TrimSuffix(info.Pattern, p)
+			break
+		}
+	}
+	if i := strings.Index(prefix, "/"); i > 0 {
+		prefix = prefix[i:]
+	} else if i < 0 {
+		prefix = ""
+	}
+	return prefix
+}
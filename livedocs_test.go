@@ -0,0 +1,83 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestDocs tests the reference page served for the endpoints of the tree
+func TestDocs(t *testing.T) {
+	route := r.NewRoute("").Add(
+		r.NewRoute("/users/{id}").Add(
+			r.Put(handlerWriter("update user")).
+				Describe("Update user", "Replaces the user.").
+				Examples(r.Example{Request: map[string]string{"name": "ada"}}),
+		),
+	).Docs("/docs")
+	route.Add(r.NewRoute("/health").Add(r.Get(handlerWriter("ok"))))
+
+	w := httptest.NewRecorder()
+	route.Mount().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	assertCorrect(t, w.Code, http.StatusOK)
+	assertCorrect(t, w.Header().Get("Content-Type"), "text/html; charset=utf-8")
+	assertCorrect(t, w.Header().Get("X-Robots-Tag"), "noindex")
+	body := w.Body.String()
+	for _, expected := range []string{
+		`<span class="method">PUT</span><span class="path">/users/{id}</span>`,
+		`<strong>Update user</strong>`,
+		`<input name="id" required>`,
+		`&#34;name&#34;: &#34;ada&#34;`,
+		`<span class="path">/health</span>`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Body does not contain %q", expected)
+		}
+	}
+	if strings.Contains(body, `<span class="path">/docs</span>`) {
+		t.Errorf("Body documents the documentation route")
+	}
+}
+
+// TestDocsWithPrefix tests that the paths of the page include the parents of the route and the base path
+func TestDocsWithPrefix(t *testing.T) {
+	api := r.NewRoute("/v1").Add(r.NewRoute("/users").Add(r.Get(handlerWriter("users")))).Docs("/docs")
+	mux := r.NewRoute("/api").Add(api).Mount(r.WithBasePath("/app"))
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app/api/v1/docs", nil))
+
+	assertCorrect(t, w.Code, http.StatusOK)
+	assertCorrect(t, strings.Contains(w.Body.String(), `<span class="path">/app/api/v1/users</span>`), true)
+	assertCorrect(t, strings.Contains(w.Body.String(), `data-path="/app/api/v1/users"`), true)
+}
+
+// TestDocsWithGuard tests that guard middlewares protect the documentation route only
+func TestDocsWithGuard(t *testing.T) {
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+		})
+	}
+	mux := r.NewRoute("").Add(r.NewRoute("/users").Add(r.Get(handlerWriter("users")))).Docs("/docs", deny).Mount()
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/docs", expectedStatus: http.StatusForbidden},
+		{path: "/users", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assertCorrect(t, w.Code, tt.expectedStatus)
+		})
+	}
+}
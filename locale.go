@@ -0,0 +1,118 @@
+package simplerouter
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// Localize adds the translation of the path of the route to locale, like "/de/produkte" for "de".
+// Once mounted, the route and its child routes are also registered under path, as by [Route.Alias], and
+// the requests served through it get locale, see [LocaleFrom]. Child routes inherit the locale of the path
+// of their parent, unless they are localized themselves. Giving the main path of the route sets its locale:
+//
+//	router.Add(simplerouter.NewRoute("/en/products").
+//		Localize("en", "/en/products").
+//		Localize("de", "/de/produkte").
+//		Localize("fr", "/fr/produits").
+//		Name("products").
+//		Add(simplerouter.Get(listProducts)))
+//
+// The translations of named routes are built with [Route.LocalizedURL].
+// It panics if locale is empty.
+func (r *Route) Localize(locale, path string) *Route {
+	if locale == "" {
+		panic("locale parameter cannot be empty")
+	}
+	if path != r.Path {
+		r.Alias(path)
+	}
+	r.Metadata.Locales = maps.Clone(r.Metadata.Locales)
+	if r.Metadata.Locales == nil {
+		r.Metadata.Locales = map[string]string{}
+	}
+	r.Metadata.Locales[path] = locale
+	return r
+}
+
+// LocaleFrom returns the locale of the path serving the request, see [Route.Localize], or an empty
+// string if the path is not localized or the request is not served by a mounted route tree.
+func LocaleFrom(ctx context.Context) string {
+	if info := RouteInfoFrom(ctx); info != nil {
+		return info.Locale
+	}
+	return ""
+}
+
+// LocalizedURL builds the path of the route named name in the route tree as [Route.URL] does, using the
+// translations to locale of the paths of the route and its parents, so pages can link to their translations:
+//
+//	router.LocalizedURL("de", "product", "id", "42") // "/de/produkte/42"
+//
+// Routes without a translation to locale keep their main path. It returns an error if the name is not
+// defined, if none of the paths of the route and its parents is translated to locale or if a wildcard
+// value is missing.
+func (r *Route) LocalizedURL(locale, name string, params ...string) (string, error) {
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("params must be key-value pairs, got %d values", len(params))
+	}
+
+	path, found, localized := r.localizedPath(locale, name, "", false)
+	if !found {
+		return "", fmt.Errorf("route name %q is not defined", name)
+	}
+	if !localized {
+		return "", fmt.Errorf("route %q has no path translated to locale %q", name, locale)
+	}
+
+	values := map[string]string{}
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+	return buildPath(path, func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	})
+}
+
+// localizedPath recursively looks for the route named name, returning its full path built with the
+// translations to locale, whether it was found and whether any of the paths was translated.
+func (r *Route) localizedPath(locale, name, path string, localized bool) (string, bool, bool) {
+	chainedPath := path + r.Path
+	for _, p := range append([]string{r.Path}, r.Aliases...) {
+		if l, ok := r.Metadata.Locales[p]; ok && l == locale {
+			chainedPath, localized = path+p, true
+			break
+		}
+	}
+	if r.Metadata.Name == name {
+		return chainedPath, true, localized
+	}
+
+	for _, route := range r.Routes {
+		if p, found, l := route.localizedPath(locale, name, chainedPath, localized); found {
+			return p, true, l
+		}
+	}
+	return "", false, false
+}
+
+// pathLocales recursively adds the locales of the full paths of the route to locales, given the locales
+// of the full paths of its parent. Paths without locale inherit the one of their parent path.
+func (r *Route) pathLocales(parents map[string]string, locales map[string]string) {
+	chained := map[string]string{}
+	for _, path := range append([]string{r.Path}, r.Aliases...) {
+		for parentPath, locale := range parents {
+			if l, ok := r.Metadata.Locales[path]; ok {
+				locale = l
+			}
+			chained[parentPath+path] = locale
+			if locale != "" {
+				locales[parentPath+path] = locale
+			}
+		}
+	}
+	for _, route := range r.Routes {
+		route.pathLocales(chained, locales)
+	}
+}
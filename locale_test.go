@@ -0,0 +1,98 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// localizedTree returns a route tree with products translated to English, German and French,
+// writing the locale and pattern of the requests
+func localizedTree() *r.Route {
+	writeLocale := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(r.LocaleFrom(req.Context()) + " " + r.PatternFrom(req.Context())))
+	}
+	return r.NewRoute("").Add(
+		r.NewRoute("/en/products").
+			Localize("en", "/en/products").
+			Localize("de", "/de/produkte").
+			Localize("fr", "/fr/produits").
+			Name("products").
+			Add(
+				r.Get(writeLocale),
+				r.NewRoute("/{id}").Name("product").Add(r.Get(writeLocale)),
+				r.NewRoute("/{id}/reviews").Localize("de", "/{id}/bewertungen").Name("reviews").Add(r.Get(writeLocale)),
+			),
+		r.NewRoute("/api").Name("api").Add(r.Get(writeLocale)),
+	)
+}
+
+// TestLocalize tests the locales of the requests served through translated paths
+func TestLocalize(t *testing.T) {
+	mux := localizedTree().Mount()
+
+	tests := []struct {
+		path         string
+		expectedBody string
+	}{
+		{path: "/en/products", expectedBody: "en /en/products"},
+		{path: "/de/produkte", expectedBody: "de /de/produkte"},
+		{path: "/fr/produits/42", expectedBody: "fr /fr/produits/{id}"},
+		{path: "/de/produkte/42/bewertungen", expectedBody: "de /de/produkte/{id}/bewertungen"},
+		{path: "/fr/produits/42/reviews", expectedBody: "fr /fr/produits/{id}/reviews"},
+		{path: "/api", expectedBody: " /api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assertCorrect(t, w.Code, http.StatusOK)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+
+	// Subtrees keep the locales of their paths.
+	w := httptest.NewRecorder()
+	localizedTree().MountSubtree("/en/products").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/de/produkte/42", nil))
+	assertCorrect(t, w.Body.String(), "de /de/produkte/{id}")
+}
+
+// TestLocalizedURL tests the paths of the named routes built for each locale
+func TestLocalizedURL(t *testing.T) {
+	tree := localizedTree()
+
+	tests := []struct {
+		name          string
+		locale        string
+		route         string
+		params        []string
+		expected      string
+		expectedError string
+	}{
+		{name: "main locale", locale: "en", route: "products", expected: "/en/products"},
+		{name: "translated", locale: "de", route: "products", expected: "/de/produkte"},
+		{name: "translated parent", locale: "fr", route: "product", params: []string{"id", "42"}, expected: "/fr/produits/42"},
+		{name: "translated parent and child", locale: "de", route: "reviews", params: []string{"id", "42"}, expected: "/de/produkte/42/bewertungen"},
+		{name: "missing locale", locale: "es", route: "products", expectedError: `route "products" has no path translated to locale "es"`},
+		{name: "not localized", locale: "en", route: "api", expectedError: `route "api" has no path translated to locale "en"`},
+		{name: "unknown name", locale: "en", route: "orders", expectedError: `route name "orders" is not defined`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, err := tree.LocalizedURL(tt.locale, tt.route, tt.params...)
+			if tt.expectedError != "" {
+				if err == nil {
+					t.Fatalf("LocalizedURL() = %q, want error %q", url, tt.expectedError)
+				}
+				assertCorrect(t, err.Error(), tt.expectedError)
+				return
+			}
+			assertCorrect(t, err, nil)
+			assertCorrect(t, url, tt.expected)
+		})
+	}
+}
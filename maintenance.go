@@ -0,0 +1,110 @@
+package simplerouter
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MaintenanceMode switches the subtrees using its middleware to maintenance at runtime, see [Maintenance].
+// It is safe for concurrent use, so it can be toggled while requests are being served.
+type MaintenanceMode struct {
+	enabled atomic.Bool
+
+	mu          sync.RWMutex
+	contentType string
+	body        []byte
+	retryAfter  time.Duration
+}
+
+// Maintenance returns a disabled [MaintenanceMode]. Once enabled, the requests of the subtrees using its
+// middleware are answered with a 503 Service Unavailable until it is disabled, so deploys and migrations
+// can stop the traffic of parts of a service without stopping it:
+//
+//	maintenance := simplerouter.Maintenance().RetryAfter(10 * time.Minute)
+//	router.Add(simplerouter.NewRoute("/api").Use(maintenance.Middleware()).Add(...))
+//
+//	maintenance.Enable()
+//	defer maintenance.Disable()
+//	migrate(ctx)
+//
+// Routes marked with [Route.MaintenanceExempt], like the ones of [HealthChecks], are served as usual,
+// so orchestrators do not restart the service during the maintenance.
+func Maintenance() *MaintenanceMode {
+	body, _ := json.Marshal(map[string]string{"error": http.StatusText(http.StatusServiceUnavailable)})
+	return &MaintenanceMode{contentType: "application/json", body: append(body, '\n')}
+}
+
+// Enable starts the maintenance.
+func (m *MaintenanceMode) Enable() {
+	m.enabled.Store(true)
+}
+
+// Disable ends the maintenance.
+func (m *MaintenanceMode) Disable() {
+	m.enabled.Store(false)
+}
+
+// Enabled reports whether the maintenance is in progress.
+func (m *MaintenanceMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+// Body sets the body of the responses during the maintenance and its content type, like an HTML page
+// announcing when the service will be back. It is the JSON error written by [JSONError] by default.
+func (m *MaintenanceMode) Body(contentType string, body []byte) *MaintenanceMode {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.contentType, m.body = contentType, body
+	return m
+}
+
+// RetryAfter sets how long clients should wait before retrying their requests, sent in the Retry-After
+// header of the responses during the maintenance. The header is not sent by default.
+func (m *MaintenanceMode) RetryAfter(d time.Duration) *MaintenanceMode {
+	if d < 0 {
+		panic("d parameter cannot be negative")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retryAfter = d
+	return m
+}
+
+// Middleware returns a middleware answering the requests with a 503 Service Unavailable while the
+// maintenance is in progress, except the ones of the routes marked with [Route.MaintenanceExempt].
+func (m *MaintenanceMode) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !m.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if info := RouteInfoFrom(r.Context()); info != nil && info.Metadata.MaintenanceExempt {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			m.mu.RLock()
+			contentType, body, retryAfter := m.contentType, m.body, m.retryAfter
+			m.mu.RUnlock()
+			if retryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+			}
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Cache-Control", "no-store")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write(body)
+		})
+	}
+}
+
+// MaintenanceExempt keeps the route and its child routes served while the maintenance of a
+// [MaintenanceMode] is in progress, like health or status routes.
+func (r *Route) MaintenanceExempt() *Route {
+	r.Metadata.MaintenanceExempt = true
+	return r
+}
@@ -0,0 +1,50 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestMaintenance tests the responses of the subtrees in maintenance and of their exempt routes
+func TestMaintenance(t *testing.T) {
+	maintenance := r.Maintenance()
+	mux := r.NewRoute("").Use(maintenance.Middleware()).Add(
+		r.NewRoute("/api/users").Add(r.Get(listUsers)),
+		r.NewRoute("/status").MaintenanceExempt().Add(r.Get(handlerWriter("status"))),
+		r.Health().Route(),
+	).Mount()
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	assertCorrect(t, maintenance.Enabled(), false)
+	assertCorrect(t, serve("/api/users").Code, http.StatusOK)
+
+	maintenance.Enable()
+	assertCorrect(t, maintenance.Enabled(), true)
+	w := serve("/api/users")
+	assertCorrect(t, w.Code, http.StatusServiceUnavailable)
+	assertCorrect(t, w.Header().Get("Content-Type"), "application/json")
+	assertCorrect(t, w.Header().Get("Retry-After"), "")
+	assertCorrect(t, w.Body.String(), "{\"error\":\"Service Unavailable\"}\n")
+	assertCorrect(t, serve("/status").Body.String(), "status")
+	assertCorrect(t, serve("/healthz").Code, http.StatusOK)
+	assertCorrect(t, serve("/readyz").Code, http.StatusOK)
+
+	maintenance.Body("text/html; charset=utf-8", []byte("<h1>Back soon</h1>")).RetryAfter(90 * time.Second)
+	w = serve("/api/users")
+	assertCorrect(t, w.Code, http.StatusServiceUnavailable)
+	assertCorrect(t, w.Header().Get("Content-Type"), "text/html; charset=utf-8")
+	assertCorrect(t, w.Header().Get("Retry-After"), "90")
+	assertCorrect(t, w.Body.String(), "<h1>Back soon</h1>")
+
+	maintenance.Disable()
+	assertCorrect(t, serve("/api/users").Code, http.StatusOK)
+}
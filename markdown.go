@@ -0,0 +1,63 @@
+package simplerouter
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Markdown renders the endpoints of the route tree as a Markdown table with their method, path,
+// middlewares and handler, for inclusion in generated documentation.
+// Aliases are listed in the path column after the main path, and functions are named with [FuncName],
+// followed by the location where middlewares were attached if provenance is enabled, see [EnableProvenance].
+func (r *Route) Markdown() string {
+	var b strings.Builder
+	b.WriteString("| Method | Path | Middlewares | Handler |\n")
+	b.WriteString("| --- | --- | --- | --- |\n")
+
+	for _, endpoint := range r.Endpoints() {
+		paths := make([]string, 0, len(endpoint.Aliases)+1)
+		for _, path := range append([]string{endpoint.Path}, endpoint.Aliases...) {
+			paths = append(paths, "`"+path+"`")
+		}
+
+		middlewares := make([]string, len(endpoint.Middlewares))
+		for i, mw := range endpoint.Middlewares {
+			middlewares[i] = "`" + middlewareName(mw, endpoint.MiddlewareSources[i]) + "`"
+		}
+
+		cells := []string{
+			methodName(endpoint.Method),
+			strings.Join(paths, "<br>"),
+			strings.Join(middlewares, "<br>"),
+			"`" + FuncName(endpoint.Handler) + "`",
+		}
+		for i, cell := range cells {
+			cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	return b.String()
+}
+
+// FuncName returns the name of the function fn, like a Middleware or a handler, resolved through
+// runtime reflection and stripped of its package path (e.g. "main.getFooHandler").
+// Anonymous functions are named after the function declaring them (e.g. "main.main.func1").
+func FuncName(fn any) string {
+	value := reflect.ValueOf(fn)
+	if value.Kind() != reflect.Func || value.IsNil() {
+		return "nil"
+	}
+
+	f := runtime.FuncForPC(value.Pointer())
+	if f == nil {
+		return "unknown"
+	}
+
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,54 @@
+package simplerouter_test
+
+import (
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestMarkdown tests the Markdown table rendered from a route tree
+func TestMarkdown(t *testing.T) {
+	route := r.NewRoute("/api").Use(authMiddleware).Add(
+		r.NewRoute("/users").Alias("/members").Add(
+			r.Get(listUsers),
+			r.All(listUsers).Use(authMiddleware),
+		),
+		r.NewRoute("/a|b").Add(r.Post(listUsers)),
+	)
+
+	want := "| Method | Path | Middlewares | Handler |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| GET | `/api/users`<br>`/api/members` | `simplerouter_test.authMiddleware` | `simplerouter_test.listUsers` |\n" +
+		"| ALL | `/api/users`<br>`/api/members` | `simplerouter_test.authMiddleware`<br>`simplerouter_test.authMiddleware` | `simplerouter_test.listUsers` |\n" +
+		"| POST | `/api/a\\|b` | `simplerouter_test.authMiddleware` | `simplerouter_test.listUsers` |\n"
+
+	got := route.Markdown()
+	if got != want {
+		t.Errorf("Markdown() = %s, want %s", got, want)
+	}
+}
+
+// TestFuncName tests the names resolved for functions
+func TestFuncName(t *testing.T) {
+	anonymous := func() {}
+	var nilMiddleware r.Middleware
+
+	tests := []struct {
+		name     string
+		fn       any
+		expected string
+	}{
+		{name: "named function", fn: listUsers, expected: "simplerouter_test.listUsers"},
+		{name: "typed middleware", fn: r.Middleware(authMiddleware), expected: "simplerouter_test.authMiddleware"},
+		{name: "anonymous function", fn: anonymous, expected: "simplerouter_test.TestFuncName.func1"},
+		{name: "package function", fn: r.NewRoute, expected: "simplerouter.NewRoute"},
+		{name: "nil function", fn: nilMiddleware, expected: "nil"},
+		{name: "not a function", fn: 42, expected: "nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCorrect(t, r.FuncName(tt.fn), tt.expected)
+		})
+	}
+}
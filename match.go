@@ -0,0 +1,103 @@
+package simplerouter
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Matcher matches requests against a mounted route tree without serving them, see [Route.Matcher].
+// It is safe for concurrent use.
+type Matcher struct {
+	mux   *http.ServeMux
+	seeds []MatchSeed
+}
+
+// MatchSeed is a sample request of an endpoint of the tree, to seed fuzz tests with, see [Matcher.Seeds].
+type MatchSeed struct {
+	Method string
+	Path   string
+}
+
+// Matcher mounts the route tree with the given options and returns a [Matcher] for it, to find the
+// pattern matching requests without running their middlewares and handlers. As matching is fast and
+// free of side effects, it can be used in fuzz tests to look for panics and unexpected matches:
+//
+//	func FuzzRouter(f *testing.F) {
+//		matcher := newRouter().Matcher()
+//		for _, seed := range matcher.Seeds() {
+//			f.Add(seed.Method, seed.Path)
+//		}
+//		f.Fuzz(func(t *testing.T, method, path string) {
+//			if pattern, ok := matcher.MatchOnly(method, path); ok && strings.HasPrefix(pattern, "/admin") {
+//				t.Errorf("%s %s matches admin pattern %q", method, path, pattern)
+//			}
+//		})
+//	}
+//
+// Editing the tree afterwards does not affect the Matcher, as with [Route.Mount].
+func (r *Route) Matcher(opts ...MountOption) *Matcher {
+	m := &Matcher{mux: r.Mount(opts...)}
+	config := &mountConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	for _, endpoint := range r.Endpoints() {
+		method := endpoint.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		for _, path := range append([]string{endpoint.Path}, endpoint.Aliases...) {
+			m.seeds = append(m.seeds, MatchSeed{Method: method, Path: config.basePath + samplePath(path)})
+		}
+		for _, example := range endpoint.Metadata.Examples {
+			if example.Path != "" {
+				m.seeds = append(m.seeds, MatchSeed{Method: method, Path: config.basePath + example.Path})
+			}
+		}
+	}
+	return m
+}
+
+// MatchOnly returns the pattern of the endpoint serving requests with the given method and path,
+// like "GET /users/{id}", and whether any pattern matches them. Requests answered with a
+// 405 Method Not Allowed match no pattern, and requests redirected to their clean path, like
+// "/users/../users", report the pattern matching the clean path. The handler given to
+// [WithNotFound] matches the "/" pattern.
+func (m *Matcher) MatchOnly(method, path string) (pattern string, ok bool) {
+	req := &http.Request{Method: method, URL: &url.URL{Path: path}, Host: "localhost", Header: http.Header{}}
+	mux := m.mux
+	for {
+		handler, pattern := mux.Handler(req)
+		// Endpoints losing a priority conflict are served by a nested http.ServeMux, see [Route.Priority].
+		if nested, isMux := handler.(*http.ServeMux); isMux && pattern == "/" {
+			mux = nested
+			continue
+		}
+		pattern = strings.TrimSpace(pattern)
+		return pattern, pattern != ""
+	}
+}
+
+// Seeds returns a sample request for the path and aliases of each endpoint of the tree, with a sample
+// value for each wildcard, followed by the paths of the examples of the endpoint, see [Route.Examples].
+// Endpoints matching all methods get GET requests. Paths include the base path given to [WithBasePath].
+func (m *Matcher) Seeds() []MatchSeed {
+	return m.seeds
+}
+
+// samplePath replaces the wildcards of the path with sample values.
+func samplePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "{$}":
+			segments[i] = ""
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}"):
+			segments[i] = "a/b"
+		case strings.HasPrefix(segment, "{"):
+			segments[i] = "x"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,92 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// matchTree returns a tree with wildcards, aliases and examples to match requests against
+func matchTree() *r.Route {
+	return r.NewRoute("/api").Add(
+		r.NewRoute("/users").Add(r.Get(listUsers), r.Post(listUsers)),
+		r.NewRoute("/users/{id}").Alias("/people/{id}").Add(r.Get(listUsers).Examples(r.Example{Path: "/api/users/42", Status: 200})),
+		r.NewRoute("/files/{path...}").Add(r.All(listUsers)),
+		r.NewRoute("/{$}").Add(r.Get(listUsers)),
+	)
+}
+
+// TestMatchOnly tests the patterns matched by requests without serving them
+func TestMatchOnly(t *testing.T) {
+	tests := []struct {
+		name            string
+		route           *r.Route
+		method          string
+		path            string
+		expectedPattern string
+	}{
+		{name: "literal", route: matchTree(), method: http.MethodPost, path: "/api/users", expectedPattern: "POST /api/users"},
+		{name: "wildcard", route: matchTree(), method: http.MethodGet, path: "/api/users/42", expectedPattern: "GET /api/users/{id}"},
+		{name: "alias", route: matchTree(), method: http.MethodHead, path: "/api/people/42", expectedPattern: "GET /api/people/{id}"},
+		{name: "all methods", route: matchTree(), method: "PURGE", path: "/api/files/a/b", expectedPattern: "/api/files/{path...}"},
+		{name: "exact match", route: matchTree(), method: http.MethodGet, path: "/api/", expectedPattern: "GET /api/{$}"},
+		{name: "unclean path", route: matchTree(), method: http.MethodGet, path: "/api/users/../users", expectedPattern: "GET /api/users"},
+		{name: "method not allowed", route: matchTree(), method: http.MethodDelete, path: "/api/users", expectedPattern: ""},
+		{name: "not found", route: matchTree(), method: http.MethodGet, path: "/other", expectedPattern: ""},
+		{name: "priority winner", route: priorityTree(1, 0), method: http.MethodGet, path: "/users/me", expectedPattern: "/users/me"},
+		{name: "priority loser", route: priorityTree(1, 0), method: http.MethodGet, path: "/users/42", expectedPattern: "GET /users/{id}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, ok := tt.route.Matcher().MatchOnly(tt.method, tt.path)
+			assertCorrect(t, pattern, tt.expectedPattern)
+			assertCorrect(t, ok, tt.expectedPattern != "")
+		})
+	}
+}
+
+// TestMatcherSeeds tests that the seeds of a matcher match the endpoints they come from
+func TestMatcherSeeds(t *testing.T) {
+	matcher := matchTree().Matcher()
+
+	got := []string{}
+	for _, seed := range matcher.Seeds() {
+		pattern, _ := matcher.MatchOnly(seed.Method, seed.Path)
+		got = append(got, seed.Method+" "+seed.Path+" => "+pattern)
+	}
+	assertCorrect(t, strings.Join(got, "\n"), strings.Join([]string{
+		"GET /api/users => GET /api/users",
+		"POST /api/users => POST /api/users",
+		"GET /api/users/x => GET /api/users/{id}",
+		"GET /api/people/x => GET /api/people/{id}",
+		"GET /api/users/42 => GET /api/users/{id}",
+		"GET /api/files/a/b => /api/files/{path...}",
+		"GET /api/ => GET /api/{$}",
+	}, "\n"))
+}
+
+// FuzzMatchOnly tests that matching arbitrary requests does not panic and only matches the admin route
+// with its own path
+func FuzzMatchOnly(f *testing.F) {
+	matcher := r.NewRoute("").Add(matchTree(), r.NewRoute("/admin").Add(r.Get(listUsers))).Matcher()
+	for _, seed := range matcher.Seeds() {
+		f.Add(seed.Method, seed.Path)
+	}
+	f.Add("GET", "/api/../admin")
+	f.Add("", "")
+	f.Add("GET", "api//users/%2F")
+
+	f.Fuzz(func(t *testing.T, method, requestPath string) {
+		pattern, ok := matcher.MatchOnly(method, requestPath)
+		if ok != (pattern != "") {
+			t.Errorf("MatchOnly(%q, %q) = %q, %v", method, requestPath, pattern, ok)
+		}
+		if pattern == "GET /admin" && path.Clean("/"+requestPath) != "/admin" {
+			t.Errorf("MatchOnly(%q, %q) matches the admin subtree", method, requestPath)
+		}
+	})
+}
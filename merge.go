@@ -0,0 +1,89 @@
+package simplerouter
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Merge grafts the routes of the other tree under the route, so several packages can each contribute
+// a piece of the same API. Routes without handler, middlewares, aliases nor metadata are plain path
+// nodes, which are merged with the plain child route of the same path if there is one, recursively,
+// and dissolved into their parent if they have no path, like the root of the other tree usually is.
+// Any other route is added as a child of the route it is merged into, keeping its own middlewares
+// and metadata for the routes under it.
+//
+// It returns an error joining a [*RouteError] with [CodeConflict] for each endpoint of the other tree
+// whose pattern conflicts with an endpoint of the route, like two handlers for the same method and path,
+// or [ErrNilRoute], [ErrRouteCycle] or [ErrFrozenRoute] as [Route.TryAdd]. Nothing is merged if it fails.
+func (r *Route) Merge(other *Route) error {
+	if r.frozen {
+		return ErrFrozenRoute
+	}
+	if other == nil {
+		return ErrNilRoute
+	}
+	if other.contains(r) {
+		return ErrRouteCycle
+	}
+
+	// Merge a copy first to find the conflicts introduced by the other tree.
+	existing := map[string]bool{}
+	for _, err := range unwrapErrors(r.Validate()) {
+		existing[err.Error()] = true
+	}
+	merged := r.Clone()
+	merged.merge(other.Clone())
+	var conflicts []error
+	for _, err := range unwrapErrors(merged.Validate()) {
+		var routeErr *RouteError
+		if errors.As(err, &routeErr) && routeErr.Code == CodeConflict && !existing[err.Error()] {
+			conflicts = append(conflicts, err)
+		}
+	}
+	if len(conflicts) > 0 {
+		return errors.Join(conflicts...)
+	}
+
+	r.recordBuild("Merge", other.describe)
+	r.merge(other)
+	return nil
+}
+
+// merge grafts the route into r, merging plain path nodes as described by [Route.Merge].
+func (r *Route) merge(route *Route) {
+	if !route.plain() {
+		r.Routes = append(r.Routes, route)
+		return
+	}
+	if route.Path == "" {
+		for _, child := range route.Routes {
+			r.merge(child)
+		}
+		return
+	}
+	for _, child := range r.Routes {
+		if child.Path == route.Path && child.plain() {
+			for _, grandchild := range route.Routes {
+				child.merge(grandchild)
+			}
+			return
+		}
+	}
+	r.Routes = append(r.Routes, route)
+}
+
+// plain reports whether the route is a plain path node: no handler, middlewares, aliases nor metadata.
+func (r *Route) plain() bool {
+	return r.Handler == nil && len(r.Middlewares) == 0 && len(r.Aliases) == 0 && reflect.ValueOf(r.Metadata).IsZero()
+}
+
+// unwrapErrors returns the errors joined in err, or err itself if it does not join errors.
+func unwrapErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
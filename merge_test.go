@@ -0,0 +1,79 @@
+package simplerouter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestMerge tests the routes served after merging trees contributed by different packages
+func TestMerge(t *testing.T) {
+	tracker := []string{}
+	router := r.NewRoute("").Add(
+		r.NewRoute("/api").Add(
+			r.NewRoute("/users").Add(r.Get(handlerWriter("users"))),
+		),
+	)
+	billing := r.NewRoute("").Add(
+		r.NewRoute("/api").Add(
+			r.NewRoute("/invoices").Use(middlewareTracker("billing", &tracker)).Add(r.Get(handlerWriter("invoices"))),
+			r.NewRoute("/users").Add(r.Post(handlerWriter("created"))),
+		),
+	)
+
+	if err := router.Merge(billing); err != nil {
+		t.Fatalf("Merge() = %v, want nil", err)
+	}
+	assertCorrect(t, len(router.Routes), 1)
+	assertCorrect(t, len(router.Routes[0].Routes), 2)
+
+	mux := router.Mount()
+	tests := []struct {
+		method           string
+		path             string
+		expectedBody     string
+		expectedTracking []string
+	}{
+		{method: http.MethodGet, path: "/api/users", expectedBody: "users", expectedTracking: []string{}},
+		{method: http.MethodPost, path: "/api/users", expectedBody: "created", expectedTracking: []string{}},
+		{method: http.MethodGet, path: "/api/invoices", expectedBody: "invoices", expectedTracking: []string{"billing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			tracker = tracker[:0]
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			if !reflect.DeepEqual(tracker, tt.expectedTracking) {
+				t.Errorf("Middlewares executed = %v, want %v", tracker, tt.expectedTracking)
+			}
+		})
+	}
+}
+
+// TestMergeWithConflicts tests that conflicting trees are reported and not merged
+func TestMergeWithConflicts(t *testing.T) {
+	router := r.NewRoute("").Add(r.NewRoute("/users").Add(r.Get(handlerWriter("users"))))
+	other := r.NewRoute("").Add(r.NewRoute("/users").Add(r.Get(handlerWriter("other users"))))
+
+	err := router.Merge(other)
+
+	var routeErr *r.RouteError
+	if !errors.As(err, &routeErr) {
+		t.Fatalf("Merge() = %v, want a RouteError", err)
+	}
+	assertCorrect(t, routeErr.Code, r.CodeConflict)
+	if !strings.HasPrefix(err.Error(), `GET /users: conflict`) {
+		t.Errorf("Merge() = %q, want a conflict of GET /users", err)
+	}
+	assertCorrect(t, len(router.Routes[0].Routes), 1)
+	assertCorrect(t, router.Merge(nil), r.ErrNilRoute)
+	assertCorrect(t, router.Routes[0].Merge(router), r.ErrRouteCycle)
+}
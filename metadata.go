@@ -0,0 +1,232 @@
+package simplerouter
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Metadata holds descriptive information about a route that does not take part in the matching.
+// Child routes inherit the metadata of their parents when the route is mounted,
+// except for the name, summary, description, examples and locales that only describe the route they are set on.
+type Metadata struct {
+	// Name identifies the route to build its URL, see [Route.Name].
+	Name string
+	// Locales are the locales of the paths of the route, keyed by path, see [Route.Localize].
+	Locales map[string]string
+	// Summary is a short description of the route, see [Route.Summary].
+	Summary string
+	// Description is a detailed explanation of the route behavior, see [Route.Description].
+	Description string
+	// Tags group routes by topic, see [Route.Tags].
+	Tags []string
+	// Deprecated marks routes that should not be used anymore, see [Route.Deprecated].
+	Deprecated bool
+	// DeprecatedSince is the date the route was deprecated, see [Route.DeprecatedSince].
+	DeprecatedSince time.Time
+	// Examples are sample requests of the route, see [Route.Examples].
+	Examples []Example
+	// Responses are sample responses of the route, see [Route.Example].
+	Responses []ResponseExample
+	// Values stores arbitrary information about the route, see [Route.Meta].
+	Values map[string]any
+	// Assets lists the critical resources announced through Early Hints, see [Route.Preload].
+	Assets []string
+	// NoIndex asks search engines not to index the route, see [Route.NoIndex].
+	NoIndex bool
+	// MaintenanceExempt keeps the route served during maintenances, see [Route.MaintenanceExempt].
+	MaintenanceExempt bool
+	// Canonical is the name or path of the canonical version of the route, see [Route.Canonical].
+	Canonical string
+	// Preflight registers handlers for CORS preflight requests, see [Route.CORS].
+	Preflight bool
+	// InitError is the error of the failed dependency of an optional route, see [Route.Optional].
+	InitError error
+	// Fallback is the handler serving an optional route when its dependency failed, see [Route.Optional].
+	Fallback http.HandlerFunc
+	// MaxBodySize is the maximum size in bytes of the request bodies, see [Route.MaxBodySize].
+	MaxBodySize int64
+	// OnError handles the errors of the [HandlerE] handlers of the route, see [Route.OnError].
+	OnError ErrorHandler
+	// Validator validates the structs bound from the requests of the route, see [Route.Validator].
+	Validator func(any) error
+	// CookieSecret signs the cookies of the route, see [Route.CookieSecret].
+	CookieSecret []byte
+	// Timeout is the time budget of the requests of the route, see [Route.Timeout].
+	Timeout time.Duration
+	// Version is the API version the route belongs to, see [Version].
+	Version string
+	// Sunset is the date after which the route is expected to be removed, see [Route.Sunset].
+	Sunset time.Time
+	// Priority resolves the conflicts between the patterns of the route and other routes, see [Route.Priority].
+	Priority int
+}
+
+// inherit returns the metadata that results from applying m to the parent metadata.
+func (m Metadata) inherit(parent Metadata) Metadata {
+	values := maps.Clone(parent.Values)
+	if len(m.Values) > 0 {
+		if values == nil {
+			values = map[string]any{}
+		}
+		maps.Copy(values, m.Values)
+	}
+
+	tags := slices.Clone(parent.Tags)
+	for _, tag := range m.Tags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	canonical := parent.Canonical
+	if m.Canonical != "" {
+		canonical = m.Canonical
+	}
+
+	initError, fallback := parent.InitError, parent.Fallback
+	if m.InitError != nil {
+		initError, fallback = m.InitError, m.Fallback
+	}
+
+	maxBodySize := parent.MaxBodySize
+	if m.MaxBodySize != 0 {
+		maxBodySize = m.MaxBodySize
+	}
+
+	onError := parent.OnError
+	if m.OnError != nil {
+		onError = m.OnError
+	}
+
+	timeout := parent.Timeout
+	if m.Timeout != 0 {
+		timeout = m.Timeout
+	}
+
+	version := parent.Version
+	if m.Version != "" {
+		version = m.Version
+	}
+
+	deprecatedSince := parent.DeprecatedSince
+	if !m.DeprecatedSince.IsZero() {
+		deprecatedSince = m.DeprecatedSince
+	}
+
+	sunset := parent.Sunset
+	if !m.Sunset.IsZero() {
+		sunset = m.Sunset
+	}
+
+	priority := parent.Priority
+	if m.Priority != 0 {
+		priority = m.Priority
+	}
+
+	validator := parent.Validator
+	if m.Validator != nil {
+		validator = m.Validator
+	}
+
+	cookieSecret := parent.CookieSecret
+	if m.CookieSecret != nil {
+		cookieSecret = m.CookieSecret
+	}
+
+	return Metadata{
+		Name:              m.Name,
+		Locales:           m.Locales,
+		Summary:           m.Summary,
+		Description:       m.Description,
+		Tags:              tags,
+		Deprecated:        parent.Deprecated || m.Deprecated,
+		DeprecatedSince:   deprecatedSince,
+		Examples:          m.Examples,
+		Responses:         m.Responses,
+		Values:            values,
+		Assets:            append(append([]string{}, parent.Assets...), m.Assets...),
+		NoIndex:           parent.NoIndex || m.NoIndex,
+		MaintenanceExempt: parent.MaintenanceExempt || m.MaintenanceExempt,
+		Canonical:         canonical,
+		Preflight:         parent.Preflight || m.Preflight,
+		InitError:         initError,
+		Fallback:          fallback,
+		MaxBodySize:       maxBodySize,
+		OnError:           onError,
+		Validator:         validator,
+		CookieSecret:      cookieSecret,
+		Timeout:           timeout,
+		Version:           version,
+		Sunset:            sunset,
+		Priority:          priority,
+	}
+}
+
+// Meta stores a custom value under the given key in the route metadata.
+// Child routes inherit the value unless they set the same key.
+func (r *Route) Meta(key string, value any) *Route {
+	if r.Metadata.Values == nil {
+		r.Metadata.Values = map[string]any{}
+	}
+	r.Metadata.Values[key] = value
+	return r
+}
+
+// Summary sets a short description of the route, used by documentation generators.
+func (r *Route) Summary(summary string) *Route {
+	r.Metadata.Summary = summary
+	return r
+}
+
+// Description sets a detailed explanation of the route behavior, used by documentation generators.
+func (r *Route) Description(description string) *Route {
+	r.Metadata.Description = description
+	return r
+}
+
+// Describe sets the summary and the detailed description of the route at once,
+// see [Route.Summary] and [Route.Description].
+func (r *Route) Describe(summary, description string) *Route {
+	return r.Summary(summary).Description(description)
+}
+
+// Tags adds tags grouping the route and its child routes by topic.
+func (r *Route) Tags(tags ...string) *Route {
+	r.Metadata.Tags = append(r.Metadata.Tags, tags...)
+	return r
+}
+
+// Deprecated marks the route and its child routes as deprecated.
+// The deprecation is documented, like in the OpenAPI document, but the responses only carry a Deprecation
+// header (RFC 9745) once a date is set with [Route.DeprecatedSince], as the header requires one and any
+// other date, like the time the tree was mounted, would change on every restart.
+func (r *Route) Deprecated() *Route {
+	r.Metadata.Deprecated = true
+	return r
+}
+
+// DeprecatedSince marks the route and its child routes as deprecated since t, announced by their
+// Deprecation header like "@1688169599", see [Route.Deprecated]. The date can be in the future to
+// announce an upcoming deprecation. Child routes can set their own date, which replaces the one of their parents.
+func (r *Route) DeprecatedSince(t time.Time) *Route {
+	if t.IsZero() {
+		panic("t parameter cannot be the zero time")
+	}
+	r.Metadata.DeprecatedSince = t
+	return r.Deprecated()
+}
+
+// deprecation returns a Middleware that sets the Deprecation header to the date t, as a structured
+// field date, before calling the next handler.
+func deprecation(t time.Time) Middleware {
+	value := "@" + strconv.FormatInt(t.Unix(), 10)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", value)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestMetadataBuilders tests that the metadata builders store their values in the route
+func TestMetadataBuilders(t *testing.T) {
+	route := r.NewRoute("/users").
+		Summary("List users").
+		Description("Returns the registered users.").
+		Tags("users").
+		Tags("admin", "internal").
+		Meta("owner", "team-a").
+		Meta("scopes", []string{"users:read"}).
+		Deprecated()
+
+	assertCorrect(t, route.Metadata.Summary, "List users")
+	assertCorrect(t, route.Metadata.Description, "Returns the registered users.")
+	assertCorrect(t, route.Metadata.Deprecated, true)
+	if !reflect.DeepEqual(route.Metadata.Tags, []string{"users", "admin", "internal"}) {
+		t.Errorf("Tags = %v, want %v", route.Metadata.Tags, []string{"users", "admin", "internal"})
+	}
+	want := map[string]any{"owner": "team-a", "scopes": []string{"users:read"}}
+	if !reflect.DeepEqual(route.Metadata.Values, want) {
+		t.Errorf("Values = %v, want %v", route.Metadata.Values, want)
+	}
+}
+
+// TestMetadataInheritance tests the effective metadata of the endpoints of a tree
+func TestMetadataInheritance(t *testing.T) {
+	route := r.NewRoute("/api").Summary("API").Tags("api").Meta("owner", "team-a").Add(
+		r.NewRoute("/users").Tags("users").Deprecated().Add(
+			r.Get(handlerWriter("users")).Summary("List users").Tags("api"),
+		),
+		r.NewRoute("/posts").Meta("owner", "team-b").Add(
+			r.Get(handlerWriter("posts")),
+		),
+	)
+
+	tests := []struct {
+		name     string
+		endpoint r.Endpoint
+		expected r.Metadata
+	}{
+		{
+			name:     "deprecated endpoint",
+			endpoint: route.Endpoints()[0],
+			expected: r.Metadata{
+				Summary:    "List users",
+				Tags:       []string{"api", "users"},
+				Deprecated: true,
+				Values:     map[string]any{"owner": "team-a"},
+				Assets:     []string{},
+			},
+		},
+		{
+			name:     "overridden value",
+			endpoint: route.Endpoints()[1],
+			expected: r.Metadata{
+				Tags:   []string{"api"},
+				Values: map[string]any{"owner": "team-b"},
+				Assets: []string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.endpoint.Metadata, tt.expected) {
+				t.Errorf("Metadata = %+v, want %+v", tt.endpoint.Metadata, tt.expected)
+			}
+		})
+	}
+}
+
+// TestDeprecatedHeader tests the Deprecation header of mounted routes
+func TestDeprecatedHeader(t *testing.T) {
+	mux := r.NewRoute("/api").Add(
+		r.NewRoute("/v1").DeprecatedSince(time.Unix(1688169599, 0)).Add(
+			r.Get(handlerWriter("v1")),
+			r.NewRoute("/users").DeprecatedSince(time.Unix(1700000000, 0)).Add(r.Get(handlerWriter("v1 users"))),
+		),
+		r.NewRoute("/v2").Add(r.Get(handlerWriter("v2"))),
+	).Mount()
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/api/v1", expected: "@1688169599"},
+		{path: "/api/v1/users", expected: "@1700000000"},
+		{path: "/api/v2", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Header().Get("Deprecation"), tt.expected)
+		})
+	}
+}
+
+// TestDeprecatedHeaderWithoutDate tests that routes deprecated without a date send no Deprecation header
+func TestDeprecatedHeaderWithoutDate(t *testing.T) {
+	mux := r.NewRoute("/v1").Deprecated().Add(r.Get(handlerWriter("v1"))).Mount()
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1", nil))
+
+	assertCorrect(t, w.Body.String(), "v1")
+	_, found := w.Header()["Deprecation"]
+	assertCorrect(t, found, false)
+}
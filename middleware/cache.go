@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCachedBodySize is the maximum size in bytes of the response bodies cached by [Cache].
+const maxCachedBodySize = 1 << 20
+
+// cacheableStatuses are the status codes of the responses cached by [Cache], the ones RFC 9110 defines
+// as heuristically cacheable.
+var cacheableStatuses = []int{200, 203, 204, 300, 301, 308, 404, 405, 410, 414, 501}
+
+// cachedResponse is a response stored by [Cache].
+type cachedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	Stored time.Time
+}
+
+// Cache returns a middleware caching the responses of GET and HEAD requests in store for ttl, to attach
+// to read-heavy subtrees. Responses are cached under the key returned by keyFn, or by the method, host and
+// URL of the request if keyFn is nil, and by the values of the request headers listed in their Vary header,
+// so different representations of the same URL are cached apart. Use [NewLRUStore] to keep them in memory,
+// or any [Store] to share them between instances:
+//
+//	router.Add(simplerouter.NewRoute("/catalog").Use(middleware.Cache(middleware.NewLRUStore(1000), time.Minute, nil)))
+//
+// Cached responses carry an Age header and an X-Cache header, HIT or MISS. As a shared cache, it does not
+// cache responses setting cookies, marked as private, no-cache or no-store, varying on every header,
+// with a body over 1 MiB or with a status code that is not heuristically cacheable, nor responses to
+// requests with an Authorization header. Requests asking for no-cache or no-store skip the cached responses.
+// Requests are served without the cache if the store fails, logging its error.
+// It panics if store is nil or ttl is not greater than zero.
+func Cache(store Store, ttl time.Duration, keyFn func(r *http.Request) string) func(http.Handler) http.Handler {
+	if store == nil || ttl <= 0 {
+		panic("store parameter cannot be nil and ttl parameter must be greater than zero")
+	}
+	if keyFn == nil {
+		keyFn = func(r *http.Request) string { return r.Method + " " + r.Host + r.URL.RequestURI() }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if (r.Method != http.MethodGet && r.Method != http.MethodHead) || r.Header.Get("Authorization") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := "cache:" + keyFn(r)
+			if !hasDirective(r.Header, "no-cache", "no-store") {
+				cached, err := lookupResponse(r.Context(), store, key, r)
+				if err != nil {
+					logCacheError(r, "cache store failed", err)
+				} else if cached != nil {
+					writeCachedResponse(w, cached)
+					return
+				}
+			}
+
+			cw := &cacheWriter{responseWriter: newResponseWriter(w)}
+			w.Header().Set("X-Cache", "MISS")
+			next.ServeHTTP(cw, r)
+			if err := storeResponse(r.Context(), store, key, r, cw, ttl); err != nil {
+				logCacheError(r, "response not cached", err)
+			}
+		})
+	}
+}
+
+// lookupResponse returns the response cached under key for the request, or nil if there is none.
+func lookupResponse(ctx context.Context, store Store, key string, r *http.Request) (*cachedResponse, error) {
+	vary, ok, err := store.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, err
+	}
+	value, ok, err := store.Get(ctx, variantKey(key, strings.Split(string(vary), ","), r))
+	if err != nil || !ok {
+		return nil, err
+	}
+	cached := &cachedResponse{}
+	if err := gob.NewDecoder(bytes.NewReader(value)).Decode(cached); err != nil {
+		return nil, nil
+	}
+	return cached, nil
+}
+
+// storeResponse caches the response recorded by cw under key, if it is cacheable.
+// The headers the response varies on are stored under key, and the response under its variant key.
+func storeResponse(ctx context.Context, store Store, key string, r *http.Request, cw *cacheWriter, ttl time.Duration) error {
+	header := cw.Header().Clone()
+	header.Del("X-Cache")
+	if cw.overflow || !slices.Contains(cacheableStatuses, cw.Status()) || header.Get("Set-Cookie") != "" ||
+		hasDirective(header, "private", "no-cache", "no-store") {
+		return nil
+	}
+	vary := []string{}
+	for _, value := range header.Values("Vary") {
+		for name := range strings.SplitSeq(value, ",") {
+			if name = strings.TrimSpace(name); name == "*" {
+				return nil
+			} else if name != "" {
+				vary = append(vary, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	cached := cachedResponse{Status: cw.Status(), Header: header, Body: cw.body.Bytes(), Stored: time.Now()}
+	if err := gob.NewEncoder(&buf).Encode(cached); err != nil {
+		return err
+	}
+	if err := store.Set(ctx, key, []byte(strings.Join(vary, ",")), ttl); err != nil {
+		return err
+	}
+	return store.Set(ctx, variantKey(key, vary, r), buf.Bytes(), ttl)
+}
+
+// variantKey returns the key of the variant of the response cached under key for the values
+// of the request headers it varies on.
+func variantKey(key string, vary []string, r *http.Request) string {
+	var b strings.Builder
+	// The variant key always differs from key, which stores the headers the response varies on.
+	b.WriteString(key + "\n")
+	for _, name := range vary {
+		if name != "" {
+			b.WriteString(name + ": " + strings.Join(r.Header.Values(name), ", ") + "\n")
+		}
+	}
+	return b.String()
+}
+
+// writeCachedResponse writes the cached response with its age.
+func writeCachedResponse(w http.ResponseWriter, cached *cachedResponse) {
+	header := w.Header()
+	for name, values := range cached.Header {
+		header[name] = values
+	}
+	header.Set("Age", strconv.Itoa(int(time.Since(cached.Stored).Seconds())))
+	header.Set("X-Cache", "HIT")
+	w.WriteHeader(cached.Status)
+	w.Write(cached.Body)
+}
+
+// hasDirective reports whether the Cache-Control header contains any of the directives.
+func hasDirective(h http.Header, directives ...string) bool {
+	for _, value := range h.Values("Cache-Control") {
+		for directive := range strings.SplitSeq(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if slices.Contains(directives, strings.ToLower(name)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// logCacheError logs an error of the cache store for the request.
+func logCacheError(r *http.Request, msg string, err error) {
+	slog.ErrorContext(r.Context(), msg,
+		"error", err,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"request_id", RequestIDFrom(r.Context()),
+	)
+}
+
+// cacheWriter wraps a responseWriter keeping the body of the response to cache it.
+type cacheWriter struct {
+	*responseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+// Write keeps the body before writing it, until it is too large to be cached.
+func (w *cacheWriter) Write(b []byte) (int, error) {
+	if !w.overflow {
+		if w.body.Len()+len(b) > maxCachedBodySize {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.responseWriter.Write(b)
+}
@@ -0,0 +1,107 @@
+package middleware_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlos-el/simplerouter/middleware"
+	"github.com/carlos-el/simplerouter/middleware/storetest"
+)
+
+// countingHandler answers with the number of requests it served and the Accept-Language of the request,
+// with the headers set by the header query parameter
+func countingHandler(count *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*count++
+		if header, value, found := strings.Cut(r.URL.Query().Get("header"), ":"); found {
+			w.Header().Set(header, value)
+		}
+		if r.URL.Query().Has("vary") {
+			w.Header().Set("Vary", "Accept-Language")
+		}
+		if r.URL.Query().Has("missing") {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, "%d %s", *count, r.Header.Get("Accept-Language"))
+	})
+}
+
+// TestCache tests the responses served from the cache, and the ones that are not cached
+func TestCache(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		target        string
+		secondTarget  string
+		header        http.Header
+		expectedCache string
+		expectedBody  string
+	}{
+		{name: "cached", method: http.MethodGet, target: "/", expectedCache: "HIT", expectedBody: "1 en"},
+		{name: "other URL", method: http.MethodGet, target: "/", secondTarget: "/?page=2", expectedCache: "MISS", expectedBody: "2 fr"},
+		{name: "unsafe method", method: http.MethodPost, target: "/", expectedBody: "2 fr"},
+		{name: "credentials", method: http.MethodGet, target: "/", header: http.Header{"Authorization": {"Bearer token"}}, expectedBody: "2 fr"},
+		{name: "request no-cache", method: http.MethodGet, target: "/", header: http.Header{"Cache-Control": {"no-cache"}}, expectedCache: "MISS", expectedBody: "2 fr"},
+		{name: "response no-store", method: http.MethodGet, target: "/?header=Cache-Control:no-store", expectedCache: "MISS", expectedBody: "2 fr"},
+		{name: "response private", method: http.MethodGet, target: "/?header=Cache-Control:max-age=60,+private", expectedCache: "MISS", expectedBody: "2 fr"},
+		{name: "response cookie", method: http.MethodGet, target: "/?header=Set-Cookie:id=1", expectedCache: "MISS", expectedBody: "2 fr"},
+		{name: "vary all", method: http.MethodGet, target: "/?header=Vary:*", expectedCache: "MISS", expectedBody: "2 fr"},
+		{name: "uncacheable status", method: http.MethodGet, target: "/?missing", expectedCache: "MISS", expectedBody: "2 fr"},
+		{name: "same variant", method: http.MethodGet, target: "/?vary", header: http.Header{"Accept-Language": {"en"}}, expectedCache: "HIT", expectedBody: "1 en"},
+		{name: "other variant", method: http.MethodGet, target: "/?vary", expectedCache: "MISS", expectedBody: "2 fr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := 0
+			handler := middleware.Cache(middleware.NewLRUStore(10), time.Minute, nil)(countingHandler(&count))
+
+			first := httptest.NewRequest(tt.method, tt.target, nil)
+			first.Header.Set("Accept-Language", "en")
+			serve(handler, first)
+
+			target := tt.target
+			if tt.secondTarget != "" {
+				target = tt.secondTarget
+			}
+			req := httptest.NewRequest(tt.method, target, nil)
+			req.Header.Set("Accept-Language", "fr")
+			for name, values := range tt.header {
+				req.Header[name] = values
+			}
+			w := serve(handler, req)
+
+			assertCorrect(t, w.Header().Get("X-Cache"), tt.expectedCache)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+// TestCacheAge tests that the cached responses keep their status and headers, with their age
+func TestCacheAge(t *testing.T) {
+	count := 0
+	handler := middleware.Cache(middleware.NewLRUStore(10), time.Minute, nil)(countingHandler(&count))
+	serve(handler, httptest.NewRequest(http.MethodGet, "/?header=Content-Type:text/csv", nil))
+	w := serve(handler, httptest.NewRequest(http.MethodGet, "/?header=Content-Type:text/csv", nil))
+
+	assertCorrect(t, w.Code, http.StatusOK)
+	assertCorrect(t, w.Header().Get("Content-Type"), "text/csv")
+	assertCorrect(t, w.Header().Get("Age"), "0")
+	assertCorrect(t, count, 1)
+}
+
+// TestCacheWithFailingStore tests that requests are served without the cache when the store fails
+func TestCacheWithFailingStore(t *testing.T) {
+	count := 0
+	handler := middleware.Cache(storetest.FailingStore{Err: errors.New("store down")}, time.Minute, nil)(countingHandler(&count))
+	serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	w := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assertCorrect(t, w.Code, http.StatusOK)
+	assertCorrect(t, w.Body.String(), "2 ")
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// CanonicalOptions configures the [CanonicalRedirect] middleware. The zero value redirects nothing.
+type CanonicalOptions struct {
+	// HTTPS redirects the requests served over plain HTTP to HTTPS.
+	HTTPS bool
+	// Host is the canonical host of the site, like "www.example.com". Requests to other hosts, like
+	// "example.com", are redirected to it. If empty, requests keep their host.
+	Host string
+	// TrimTrailingSlash redirects the paths ending with a slash, other than the root, to the path without it.
+	TrimTrailingSlash bool
+	// LowercasePath redirects the paths with uppercase letters to their lowercase version.
+	LowercasePath bool
+	// TrustForwardedProto reads the scheme of the requests from the X-Forwarded-Proto header, so requests
+	// served over HTTPS by a reverse proxy are not redirected. Only enable it behind a proxy setting it.
+	TrustForwardedProto bool
+}
+
+// CanonicalRedirect returns a middleware that redirects the requests to the canonical form of their URL,
+// so search engines index a single URL for each page, keeping the query. GET and HEAD requests are
+// redirected with a 301 Moved Permanently, and the other ones with a 308 Permanent Redirect so clients
+// send them again with the same method and body. Requests already in canonical form are passed through.
+//
+// As paths that are not canonical may not match any route, it should wrap the mounted tree rather
+// than be added to its routes:
+//
+//	handler := middleware.CanonicalRedirect(middleware.CanonicalOptions{
+//		HTTPS:             true,
+//		Host:              "www.example.com",
+//		TrimTrailingSlash: true,
+//	})(router.Mount())
+func CanonicalRedirect(opts CanonicalOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			target, ok := canonicalURL(r, opts)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			status := http.StatusMovedPermanently
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				status = http.StatusPermanentRedirect
+			}
+			http.Redirect(w, r, target, status)
+		})
+	}
+}
+
+// canonicalURL returns the canonical URL of the request and whether it differs from the requested one.
+func canonicalURL(r *http.Request, opts CanonicalOptions) (string, bool) {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	} else if proto := r.Header.Get("X-Forwarded-Proto"); opts.TrustForwardedProto && proto != "" {
+		scheme = strings.ToLower(strings.TrimSpace(strings.Split(proto, ",")[0]))
+	}
+
+	host, canonicalHost := r.Host, r.Host
+	if opts.Host != "" {
+		canonicalHost = opts.Host
+	}
+	canonicalScheme := scheme
+	if opts.HTTPS && scheme != "https" {
+		canonicalScheme = "https"
+		// The port of plain HTTP, like the 80 of the request, does not serve HTTPS.
+		if h, _, err := net.SplitHostPort(canonicalHost); err == nil && opts.Host == "" {
+			canonicalHost = h
+		}
+	}
+
+	path := r.URL.EscapedPath()
+	canonicalPath := path
+	if opts.TrimTrailingSlash && len(canonicalPath) > 1 {
+		canonicalPath = strings.TrimRight(canonicalPath, "/")
+		if canonicalPath == "" {
+			canonicalPath = "/"
+		}
+	}
+	if opts.LowercasePath {
+		canonicalPath = strings.ToLower(canonicalPath)
+	}
+
+	if canonicalScheme == scheme && strings.EqualFold(canonicalHost, host) && canonicalPath == path {
+		return "", false
+	}
+	target := canonicalScheme + "://" + canonicalHost + canonicalPath
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	return target, true
+}
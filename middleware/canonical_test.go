@@ -0,0 +1,71 @@
+package middleware_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestCanonicalRedirect tests the redirects to the canonical URLs of the requests
+func TestCanonicalRedirect(t *testing.T) {
+	handler := middleware.CanonicalRedirect(middleware.CanonicalOptions{
+		HTTPS:               true,
+		Host:                "www.example.com",
+		TrimTrailingSlash:   true,
+		LowercasePath:       true,
+		TrustForwardedProto: true,
+	})(handlerWriter("served"))
+
+	tests := []struct {
+		name             string
+		method           string
+		target           string
+		tls              bool
+		headers          map[string]string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{name: "canonical", method: http.MethodGet, target: "https://www.example.com/about", tls: true, expectedStatus: http.StatusOK},
+		{name: "root", method: http.MethodGet, target: "https://www.example.com/", tls: true, expectedStatus: http.StatusOK},
+		{name: "http", method: http.MethodGet, target: "http://www.example.com/about?a=1", expectedStatus: http.StatusMovedPermanently, expectedLocation: "https://www.example.com/about?a=1"},
+		{name: "forwarded https", method: http.MethodGet, target: "http://www.example.com/about", headers: map[string]string{"X-Forwarded-Proto": "https"}, expectedStatus: http.StatusOK},
+		{name: "other host", method: http.MethodGet, target: "https://example.com/about", tls: true, expectedStatus: http.StatusMovedPermanently, expectedLocation: "https://www.example.com/about"},
+		{name: "trailing slash", method: http.MethodGet, target: "https://www.example.com/about/", tls: true, expectedStatus: http.StatusMovedPermanently, expectedLocation: "https://www.example.com/about"},
+		{name: "uppercase path", method: http.MethodHead, target: "https://www.example.com/About", tls: true, expectedStatus: http.StatusMovedPermanently, expectedLocation: "https://www.example.com/about"},
+		{name: "all at once", method: http.MethodGet, target: "http://example.com:8080/Docs/", expectedStatus: http.StatusMovedPermanently, expectedLocation: "https://www.example.com/docs"},
+		{name: "post", method: http.MethodPost, target: "http://www.example.com/form", expectedStatus: http.StatusPermanentRedirect, expectedLocation: "https://www.example.com/form"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if !tt.tls {
+				req.TLS = nil
+			} else if req.TLS == nil {
+				req.TLS = &tls.ConnectionState{}
+			}
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := serve(handler, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Header().Get("Location"), tt.expectedLocation)
+		})
+	}
+}
+
+// TestCanonicalRedirectKeepsHostPort tests that the port of the request is dropped only when switching to HTTPS
+func TestCanonicalRedirectKeepsHostPort(t *testing.T) {
+	handler := middleware.CanonicalRedirect(middleware.CanonicalOptions{HTTPS: true, TrimTrailingSlash: true})(handlerWriter("served"))
+
+	w := serve(handler, httptest.NewRequest(http.MethodGet, "http://localhost:8080/a", nil))
+	assertCorrect(t, w.Header().Get("Location"), "https://localhost/a")
+
+	req := httptest.NewRequest(http.MethodGet, "https://localhost:8443/a/", nil)
+	w = serve(handler, req)
+	assertCorrect(t, w.Header().Get("Location"), "https://localhost:8443/a")
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"slices"
+)
+
+// ClientCertOption configures the [ClientCert] middleware.
+type ClientCertOption func(*clientCertConfig)
+
+// clientCertConfig holds the checks of the [ClientCert] middleware.
+type clientCertConfig struct {
+	sans []string
+	ous  []string
+}
+
+// ClientCertSANs requires the certificates to have one of sans among their subject alternative names:
+// DNS names, email addresses, IP addresses or URIs, like the SPIFFE ID "spiffe://example.org/billing".
+func ClientCertSANs(sans ...string) ClientCertOption {
+	return func(c *clientCertConfig) { c.sans = append(c.sans, sans...) }
+}
+
+// ClientCertOUs requires the certificates to have one of ous among the organizational units of their subject.
+func ClientCertOUs(ous ...string) ClientCertOption {
+	return func(c *clientCertConfig) { c.ous = append(c.ous, ous...) }
+}
+
+// ClientIdentity is the identity of a client authenticated by the [ClientCert] middleware.
+type ClientIdentity struct {
+	// CommonName is the common name of the subject of the certificate.
+	CommonName string
+	// OrganizationalUnits are the organizational units of the subject of the certificate.
+	OrganizationalUnits []string
+	// SANs are the subject alternative names of the certificate: its DNS names, email addresses,
+	// IP addresses and URIs, in that order.
+	SANs []string
+	// Certificate is the certificate of the client.
+	Certificate *x509.Certificate
+}
+
+// clientIdentityKey is the context key storing the identity of the client.
+type clientIdentityKey struct{}
+
+// ClientCert returns a middleware that requires requests to be sent over TLS with a client certificate
+// verified by the server, and checks its subject alternative names and organizational units if configured
+// by opts, so internal services can only be called by the services they trust:
+//
+//	router.Add(simplerouter.NewRoute("/internal").Use(middleware.ClientCert(
+//		middleware.ClientCertSANs("spiffe://example.org/billing"),
+//	)).Add(...))
+//
+// The server verifies the certificates against its tls.Config.ClientCAs, so its tls.Config.ClientAuth
+// must be tls.VerifyClientCertIfGiven, or tls.RequireAndVerifyClientCert if every route needs them.
+// Certificates that were not verified, like the ones requested with tls.RequestClientCert, are not accepted.
+// The identity of the clients is available to the next handlers with [ClientIdentityFrom]. Requests
+// without a verified certificate are answered with a 401 Unauthorized, and the ones whose certificate
+// does not pass the checks with a 403 Forbidden.
+func ClientCert(opts ...ClientCertOption) func(http.Handler) http.Handler {
+	config := &clientCertConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			identity := newClientIdentity(r.TLS.VerifiedChains[0][0])
+			if !config.allows(identity) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIdentityKey{}, identity)))
+		})
+	}
+}
+
+// ClientIdentityFrom returns the identity of the client stored in ctx by [ClientCert], or nil if there is none.
+func ClientIdentityFrom(ctx context.Context) *ClientIdentity {
+	identity, _ := ctx.Value(clientIdentityKey{}).(*ClientIdentity)
+	return identity
+}
+
+// newClientIdentity returns the identity of the client with the certificate.
+func newClientIdentity(cert *x509.Certificate) *ClientIdentity {
+	sans := slices.Concat(cert.DNSNames, cert.EmailAddresses)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return &ClientIdentity{
+		CommonName:          cert.Subject.CommonName,
+		OrganizationalUnits: cert.Subject.OrganizationalUnit,
+		SANs:                sans,
+		Certificate:         cert,
+	}
+}
+
+// allows reports whether the identity passes the checks of the configuration.
+func (c *clientCertConfig) allows(identity *ClientIdentity) bool {
+	if len(c.sans) > 0 && !slices.ContainsFunc(identity.SANs, func(san string) bool { return slices.Contains(c.sans, san) }) {
+		return false
+	}
+	if len(c.ous) > 0 && !slices.ContainsFunc(identity.OrganizationalUnits, func(ou string) bool { return slices.Contains(c.ous, ou) }) {
+		return false
+	}
+	return true
+}
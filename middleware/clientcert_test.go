@@ -0,0 +1,96 @@
+package middleware_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestClientCert tests the client certificates accepted and rejected by the middleware
+func TestClientCert(t *testing.T) {
+	spiffeID, _ := url.Parse("spiffe://example.org/billing")
+	billing := &x509.Certificate{
+		Subject: pkix.Name{CommonName: "billing", OrganizationalUnit: []string{"payments"}},
+		URIs:    []*url.URL{spiffeID},
+	}
+	reports := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "reports", OrganizationalUnit: []string{"analytics"}},
+		DNSNames:    []string{"reports.internal"},
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.7")},
+	}
+
+	tests := []struct {
+		name           string
+		opts           []middleware.ClientCertOption
+		state          *tls.ConnectionState
+		expectedStatus int
+	}{
+		{name: "no tls", expectedStatus: http.StatusUnauthorized},
+		{name: "no certificate", state: &tls.ConnectionState{}, expectedStatus: http.StatusUnauthorized},
+		{name: "unverified certificate", state: &tls.ConnectionState{PeerCertificates: []*x509.Certificate{billing}}, expectedStatus: http.StatusUnauthorized},
+		{name: "any verified certificate", state: verifiedState(reports), expectedStatus: http.StatusOK},
+		{name: "uri san", opts: []middleware.ClientCertOption{middleware.ClientCertSANs("spiffe://example.org/billing")}, state: verifiedState(billing), expectedStatus: http.StatusOK},
+		{name: "dns san", opts: []middleware.ClientCertOption{middleware.ClientCertSANs("billing.internal", "reports.internal")}, state: verifiedState(reports), expectedStatus: http.StatusOK},
+		{name: "ip san", opts: []middleware.ClientCertOption{middleware.ClientCertSANs("10.0.0.7")}, state: verifiedState(reports), expectedStatus: http.StatusOK},
+		{name: "wrong san", opts: []middleware.ClientCertOption{middleware.ClientCertSANs("spiffe://example.org/billing")}, state: verifiedState(reports), expectedStatus: http.StatusForbidden},
+		{name: "ou", opts: []middleware.ClientCertOption{middleware.ClientCertOUs("payments")}, state: verifiedState(billing), expectedStatus: http.StatusOK},
+		{name: "wrong ou", opts: []middleware.ClientCertOption{middleware.ClientCertOUs("payments")}, state: verifiedState(reports), expectedStatus: http.StatusForbidden},
+		{name: "san and wrong ou", opts: []middleware.ClientCertOption{middleware.ClientCertSANs("reports.internal"), middleware.ClientCertOUs("payments")}, state: verifiedState(reports), expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middleware.ClientCert(tt.opts...)(handlerWriter("served"))
+			req := httptest.NewRequest(http.MethodGet, "/internal", nil)
+			req.TLS = tt.state
+			w := serve(handler, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+		})
+	}
+}
+
+// TestClientIdentityFrom tests the identity of the client stored in the context by the middleware
+func TestClientIdentityFrom(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "reports", OrganizationalUnit: []string{"analytics"}},
+		DNSNames:       []string{"reports.internal"},
+		EmailAddresses: []string{"reports@example.org"},
+		IPAddresses:    []net.IP{net.ParseIP("10.0.0.7")},
+	}
+	var identity *middleware.ClientIdentity
+	handler := middleware.ClientCert()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity = middleware.ClientIdentityFrom(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/internal", nil)
+	req.TLS = verifiedState(cert)
+	serve(handler, req)
+
+	if identity == nil {
+		t.Fatal("identity not found in the context")
+	}
+	assertCorrect(t, identity.CommonName, "reports")
+	assertCorrect(t, strings.Join(identity.OrganizationalUnits, ","), "analytics")
+	assertCorrect(t, strings.Join(identity.SANs, ","), "reports.internal,reports@example.org,10.0.0.7")
+	assertCorrect(t, identity.Certificate, cert)
+
+	if middleware.ClientIdentityFrom(httptest.NewRequest(http.MethodGet, "/", nil).Context()) != nil {
+		t.Error("identity found in a context without it")
+	}
+}
+
+// verifiedState returns the state of a TLS connection whose client sent the verified certificate.
+func verifiedState(cert *x509.Certificate) *tls.ConnectionState {
+	return &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+		VerifiedChains:   [][]*x509.Certificate{{cert}},
+	}
+}
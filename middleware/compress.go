@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// incompressibleTypes lists the content types that are already compressed,
+// skipped by [Compress] when no content types are given. Types ending in "/" match any subtype.
+var incompressibleTypes = []string{
+	"image/", "video/", "audio/", "font/woff", "font/woff2",
+	"application/zip", "application/gzip", "application/x-gzip", "application/zstd",
+	"application/x-7z-compressed", "application/x-rar-compressed", "application/x-bzip2",
+	"application/octet-stream", "application/pdf",
+}
+
+// compressibleImages lists the image types that are text and worth compressing.
+var compressibleImages = []string{"image/svg+xml"}
+
+// Compress returns a middleware that compresses responses with gzip or deflate, as negotiated with the
+// Accept-Encoding request header, at the given compression level (like gzip.DefaultCompression).
+// Only the content types given are compressed, where "text/*" matches any text type. If none is given,
+// every response is compressed except the ones with already compressed content types (images, video,
+// archives...). Responses with a Content-Encoding set by the next handlers, 204, 206 and 304 responses
+// are never compressed. Flushing and hijacking the connection are passed through to the wrapped writer.
+// Brotli is not negotiated, as the standard library has no encoder for it and the middleware package
+// has no dependencies: compress the responses with it in a reverse proxy or CDN if needed.
+// It panics if the level is not valid.
+func Compress(level int, types ...string) func(http.Handler) http.Handler {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		panic("level parameter must be a valid compression level, got " + strconv.Itoa(level))
+	}
+
+	pools := map[string]*sync.Pool{
+		"gzip": {New: func() any {
+			w, _ := gzip.NewWriterLevel(io.Discard, level)
+			return w
+		}},
+		"deflate": {New: func() any {
+			w, _ := flate.NewWriter(io.Discard, level)
+			return w
+		}},
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, types: types, pool: pools[encoding]}
+			panicked := true
+			defer func() { cw.close(panicked) }()
+			next.ServeHTTP(cw, r)
+			panicked = false
+		})
+	}
+}
+
+// negotiateEncoding returns the preferred encoding accepted by the client, gzip or deflate,
+// or an empty string if neither is accepted.
+func negotiateEncoding(acceptEncoding string) string {
+	best, bestQ := "", 0.0
+	for part := range strings.SplitSeq(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		switch name = strings.ToLower(strings.TrimSpace(name)); name {
+		case "*":
+			name = "gzip"
+		case "gzip", "deflate":
+		default:
+			continue
+		}
+		// gzip wins ties, as it is the most widely supported.
+		if q > 0 && (q > bestQ || (q == bestQ && name == "gzip")) {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressor is the common interface of the gzip and flate writers.
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressWriter wraps an http.ResponseWriter compressing the body if the response is compressible.
+// The decision is delayed until the first write, so the content type can be sniffed if not set.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	types    []string
+	pool     *sync.Pool
+
+	status     int
+	decided    bool
+	compressor compressor
+}
+
+// WriteHeader records the status code until the first write.
+// Informational status codes are written right away, as they do not start the final response.
+func (w *compressWriter) WriteHeader(code int) {
+	if code >= 100 && code <= 199 {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status == 0 {
+		w.status = code
+	}
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write compresses the data if the response is compressible.
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.decide(b)
+	}
+	if w.compressor != nil {
+		return w.compressor.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush flushes the compressed data and sends any buffered data to the client.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(nil)
+	}
+	if w.compressor != nil {
+		w.compressor.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack lets the next handlers take over the connection if the wrapped http.ResponseWriter supports it.
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("middleware: the wrapped ResponseWriter does not implement http.Hijacker")
+	}
+	w.decided = true
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide chooses whether to compress the response, sniffing its content type from the first data
+// written if not set, and writes the status code.
+func (w *compressWriter) decide(first []byte) {
+	w.decided = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(first) > 0 {
+		header.Set("Content-Type", http.DetectContentType(first))
+	}
+
+	if w.compressible() {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		w.compressor = w.pool.Get().(compressor)
+		w.compressor.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// compressible reports whether the response should be compressed.
+func (w *compressWriter) compressible() bool {
+	switch w.status {
+	case http.StatusNoContent, http.StatusPartialContent, http.StatusNotModified:
+		return false
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if err != nil {
+		return len(w.types) == 0
+	}
+	if len(w.types) > 0 {
+		return matchType(w.types, mediaType)
+	}
+	return matchType(compressibleImages, mediaType) || !matchType(incompressibleTypes, mediaType)
+}
+
+// close writes the status code if nothing was written, without compressing the empty body,
+// and finishes the compressed stream. If the next handlers panicked, nothing is written, so the
+// middlewares recovering the panic can still answer with an error.
+func (w *compressWriter) close(panicked bool) {
+	if !w.decided && !panicked {
+		w.decided = true
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if w.compressor != nil {
+		if !panicked {
+			w.compressor.Close()
+		}
+		w.compressor.Reset(io.Discard)
+		w.pool.Put(w.compressor)
+		w.compressor = nil
+	}
+}
+
+// matchType reports whether the media type matches one of the types,
+// where types ending in "/" or "/*" match any subtype.
+func matchType(types []string, mediaType string) bool {
+	for _, t := range types {
+		t = strings.TrimSuffix(t, "*")
+		if mediaType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t)) {
+			return true
+		}
+	}
+	return false
+}
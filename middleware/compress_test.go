@@ -0,0 +1,158 @@
+package middleware_test
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// typedWriter creates a handler that writes a response with a specific content type
+func typedWriter(contentType, response string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.Write([]byte(response))
+	})
+}
+
+// TestCompress tests the negotiation and the compression of responses
+func TestCompress(t *testing.T) {
+	body := strings.Repeat("hello world ", 100)
+
+	tests := []struct {
+		name             string
+		acceptEncoding   string
+		types            []string
+		handler          http.Handler
+		expectedEncoding string
+	}{
+		{name: "gzip accepted", acceptEncoding: "gzip, deflate", handler: typedWriter("text/plain", body), expectedEncoding: "gzip"},
+		{name: "deflate preferred", acceptEncoding: "gzip;q=0.5, deflate", handler: typedWriter("text/plain", body), expectedEncoding: "deflate"},
+		{name: "any encoding", acceptEncoding: "*", handler: typedWriter("text/plain", body), expectedEncoding: "gzip"},
+		{name: "no accepted encoding", acceptEncoding: "br, gzip;q=0", handler: typedWriter("text/plain", body), expectedEncoding: ""},
+		{name: "sniffed content type", acceptEncoding: "gzip", handler: typedWriter("", body), expectedEncoding: "gzip"},
+		{name: "compressed content type", acceptEncoding: "gzip", handler: typedWriter("image/png", body), expectedEncoding: ""},
+		{name: "svg image", acceptEncoding: "gzip", handler: typedWriter("image/svg+xml", body), expectedEncoding: "gzip"},
+		{name: "listed content type", acceptEncoding: "gzip", types: []string{"text/*"}, handler: typedWriter("text/html; charset=utf-8", body), expectedEncoding: "gzip"},
+		{name: "unlisted content type", acceptEncoding: "gzip", types: []string{"text/*"}, handler: typedWriter("application/json", body), expectedEncoding: ""},
+		{
+			name:           "already encoded response",
+			acceptEncoding: "gzip",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "identity")
+				w.Write([]byte(body))
+			}),
+			expectedEncoding: "identity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+
+			w := serve(middleware.Compress(gzip.DefaultCompression, tt.types...)(tt.handler), req)
+
+			assertCorrect(t, w.Header().Get("Content-Encoding"), tt.expectedEncoding)
+			assertCorrect(t, w.Header().Get("Vary"), "Accept-Encoding")
+
+			var reader io.Reader = w.Body
+			switch tt.expectedEncoding {
+			case "gzip":
+				gz, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				reader = gz
+			case "deflate":
+				reader = flate.NewReader(w.Body)
+			}
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertCorrect(t, string(got), body)
+		})
+	}
+}
+
+// TestCompressWithEmptyResponse tests that responses without body are not compressed
+func TestCompressWithEmptyResponse(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	w := serve(middleware.Compress(gzip.DefaultCompression)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})), req)
+
+	assertCorrect(t, w.Code, http.StatusNoContent)
+	assertCorrect(t, w.Header().Get("Content-Encoding"), "")
+	assertCorrect(t, w.Body.Len(), 0)
+}
+
+// TestCompressWithPanic tests that the recoverer can still answer with an error when the handler panics
+func TestCompressWithPanic(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	handler := middleware.Compress(gzip.DefaultCompression)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	w := serve(middleware.Recover()(handler), req)
+
+	assertCorrect(t, w.Code, http.StatusInternalServerError)
+	assertCorrect(t, w.Header().Get("Content-Encoding"), "")
+	assertCorrect(t, w.Body.String(), "Internal Server Error\n")
+}
+
+// TestCompressWithFlush tests that flushed data can be decompressed before the response ends
+func TestCompressWithFlush(t *testing.T) {
+	flushed := make(chan struct{})
+	server := httptest.NewServer(middleware.Compress(gzip.BestSpeed)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("first"))
+		http.NewResponseController(w).Flush()
+		<-flushed
+		w.Write([]byte("second"))
+	})))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := make([]byte, 5)
+	if _, err := io.ReadFull(gz, first); err != nil {
+		t.Fatal(err)
+	}
+	close(flushed)
+	rest, _ := io.ReadAll(gz)
+
+	assertCorrect(t, string(first)+string(rest), "firstsecond")
+}
+
+// TestCompressWithInvalidLevel tests that invalid compression levels cause a panic
+func TestCompressWithInvalidLevel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Compress(42) to panic, but it didn't")
+		}
+	}()
+
+	middleware.Compress(42)
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions configures the [CORS] middleware.
+type CORSOptions struct {
+	// AllowedOrigins lists the origins allowed to make cross origin requests, like "https://example.com".
+	// An origin can contain a "*" wildcard, like "https://*.example.com", and "*" alone allows any origin.
+	AllowedOrigins []string
+	// AllowOriginFunc decides whether an origin is allowed, used instead of AllowedOrigins if not nil.
+	AllowOriginFunc func(origin string) bool
+	// AllowedMethods lists the methods allowed in cross origin requests, GET, HEAD and POST if empty.
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers allowed in cross origin requests.
+	// If empty, the headers requested by the preflight request are allowed.
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers clients are allowed to read.
+	ExposedHeaders []string
+	// AllowCredentials allows requests with credentials like cookies or authorization headers.
+	AllowCredentials bool
+	// MaxAge is how long the result of a preflight request can be cached by clients.
+	// If zero, the header is not sent and the client default is used.
+	MaxAge time.Duration
+}
+
+// CORS returns a middleware implementing Cross-Origin Resource Sharing as configured by opts.
+// Preflight requests from allowed origins are answered with a 204 No Content response without
+// calling the next handler, and other requests from allowed origins get the CORS response headers.
+// Requests from not allowed origins are served without CORS headers, so browsers block them.
+//
+// Preflight requests use the OPTIONS method, so they only reach the middleware if the path has a handler
+// for it: attach it with [github.com/carlos-el/simplerouter.Route.CORS] to register them automatically.
+func CORS(opts CORSOptions) func(http.Handler) http.Handler {
+	if len(opts.AllowedMethods) == 0 {
+		opts.AllowedMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	}
+	allowedMethods := strings.Join(opts.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(opts.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(opts.ExposedHeaders, ", ")
+	allowAnyOrigin := slices.Contains(opts.AllowedOrigins, "*")
+
+	allowOrigin := opts.AllowOriginFunc
+	if allowOrigin == nil {
+		allowOrigin = func(origin string) bool {
+			for _, allowed := range opts.AllowedOrigins {
+				if matchOrigin(allowed, origin) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			if origin == "" || !allowOrigin(origin) {
+				if preflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowAnyOrigin && !opts.AllowCredentials && opts.AllowOriginFunc == nil {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if opts.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if exposedHeaders != "" {
+					h.Set("Access-Control-Expose-Headers", exposedHeaders)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.Set("Access-Control-Allow-Methods", allowedMethods)
+			if allowedHeaders != "" {
+				h.Set("Access-Control-Allow-Headers", allowedHeaders)
+			} else if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				h.Set("Access-Control-Allow-Headers", requested)
+			}
+			if opts.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// matchOrigin reports whether origin matches the allowed origin, which can contain a "*" wildcard.
+func matchOrigin(allowed, origin string) bool {
+	if allowed == "*" || strings.EqualFold(allowed, origin) {
+		return true
+	}
+
+	prefix, suffix, found := strings.Cut(strings.ToLower(allowed), "*")
+	origin = strings.ToLower(origin)
+	return found &&
+		len(origin) >= len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) &&
+		strings.HasSuffix(origin, suffix)
+}
@@ -0,0 +1,148 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestCORS tests the CORS headers of preflight and actual requests
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            middleware.CORSOptions
+		method          string
+		headers         map[string]string
+		expectedStatus  int
+		expectedBody    string
+		expectedHeaders map[string]string
+	}{
+		{
+			name:            "no origin",
+			opts:            middleware.CORSOptions{AllowedOrigins: []string{"https://example.com"}},
+			method:          http.MethodGet,
+			headers:         map[string]string{},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "ok",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:            "allowed origin",
+			opts:            middleware.CORSOptions{AllowedOrigins: []string{"https://example.com"}, ExposedHeaders: []string{"X-Total"}},
+			method:          http.MethodGet,
+			headers:         map[string]string{"Origin": "https://example.com"},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "ok",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "https://example.com", "Access-Control-Expose-Headers": "X-Total", "Vary": "Origin"},
+		},
+		{
+			name:            "not allowed origin",
+			opts:            middleware.CORSOptions{AllowedOrigins: []string{"https://example.com"}},
+			method:          http.MethodGet,
+			headers:         map[string]string{"Origin": "https://evil.com"},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "ok",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:            "wildcard subdomain origin",
+			opts:            middleware.CORSOptions{AllowedOrigins: []string{"https://*.example.com"}},
+			method:          http.MethodGet,
+			headers:         map[string]string{"Origin": "https://app.example.com"},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "ok",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "https://app.example.com"},
+		},
+		{
+			name:            "any origin",
+			opts:            middleware.CORSOptions{AllowedOrigins: []string{"*"}},
+			method:          http.MethodGet,
+			headers:         map[string]string{"Origin": "https://example.com"},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "ok",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "*"},
+		},
+		{
+			name:            "any origin with credentials",
+			opts:            middleware.CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			method:          http.MethodGet,
+			headers:         map[string]string{"Origin": "https://example.com"},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "ok",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "https://example.com", "Access-Control-Allow-Credentials": "true"},
+		},
+		{
+			name:            "origin func",
+			opts:            middleware.CORSOptions{AllowOriginFunc: func(origin string) bool { return origin == "https://func.com" }},
+			method:          http.MethodGet,
+			headers:         map[string]string{"Origin": "https://func.com"},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "ok",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "https://func.com"},
+		},
+		{
+			name: "preflight",
+			opts: middleware.CORSOptions{
+				AllowedOrigins: []string{"https://example.com"},
+				AllowedMethods: []string{"GET", "PUT"},
+				MaxAge:         10 * time.Minute,
+			},
+			method:         http.MethodOptions,
+			headers:        map[string]string{"Origin": "https://example.com", "Access-Control-Request-Method": "PUT", "Access-Control-Request-Headers": "Content-Type"},
+			expectedStatus: http.StatusNoContent,
+			expectedBody:   "",
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://example.com",
+				"Access-Control-Allow-Methods": "GET, PUT",
+				"Access-Control-Allow-Headers": "Content-Type",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:            "preflight with allowed headers",
+			opts:            middleware.CORSOptions{AllowedOrigins: []string{"https://example.com"}, AllowedHeaders: []string{"Authorization"}},
+			method:          http.MethodOptions,
+			headers:         map[string]string{"Origin": "https://example.com", "Access-Control-Request-Method": "POST", "Access-Control-Request-Headers": "Content-Type"},
+			expectedStatus:  http.StatusNoContent,
+			expectedBody:    "",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Methods": "GET, HEAD, POST", "Access-Control-Allow-Headers": "Authorization"},
+		},
+		{
+			name:            "preflight from not allowed origin",
+			opts:            middleware.CORSOptions{AllowedOrigins: []string{"https://example.com"}},
+			method:          http.MethodOptions,
+			headers:         map[string]string{"Origin": "https://evil.com", "Access-Control-Request-Method": "POST"},
+			expectedStatus:  http.StatusNoContent,
+			expectedBody:    "",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Methods": ""},
+		},
+		{
+			name:            "plain options request",
+			opts:            middleware.CORSOptions{AllowedOrigins: []string{"https://example.com"}},
+			method:          http.MethodOptions,
+			headers:         map[string]string{"Origin": "https://example.com"},
+			expectedStatus:  http.StatusOK,
+			expectedBody:    "ok",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "https://example.com", "Access-Control-Allow-Methods": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := serve(middleware.CORS(tt.opts)(handlerWriter("ok")), req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			for key, value := range tt.expectedHeaders {
+				assertCorrect(t, w.Header().Get(key), value)
+			}
+		})
+	}
+}
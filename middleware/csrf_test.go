@@ -0,0 +1,92 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// tokenWriter is a handler writing the CSRF token of the request
+func tokenWriter(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(middleware.CSRFToken(r)))
+}
+
+// TestCSRF tests the requests accepted and rejected by the CSRF middleware
+func TestCSRF(t *testing.T) {
+	handler := middleware.CSRF(middleware.CSRFOptions{ExemptPaths: []string{"/api/"}})(http.HandlerFunc(tokenWriter))
+	token := "token123"
+	form := url.Values{middleware.CSRFField: {token}}.Encode()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		cookie         string
+		header         string
+		form           string
+		expectedStatus int
+	}{
+		{name: "safe method without token", method: http.MethodGet, path: "/", expectedStatus: http.StatusOK},
+		{name: "unsafe method without cookie", method: http.MethodPost, path: "/", header: token, expectedStatus: http.StatusForbidden},
+		{name: "unsafe method without token", method: http.MethodPost, path: "/", cookie: token, expectedStatus: http.StatusForbidden},
+		{name: "unsafe method with wrong token", method: http.MethodDelete, path: "/", cookie: token, header: "other", expectedStatus: http.StatusForbidden},
+		{name: "token in header", method: http.MethodPost, path: "/", cookie: token, header: token, expectedStatus: http.StatusOK},
+		{name: "token in form", method: http.MethodPost, path: "/", cookie: token, form: form, expectedStatus: http.StatusOK},
+		{name: "exempt path", method: http.MethodPost, path: "/api/users", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.form))
+			if tt.form != "" {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: middleware.CSRFCookie, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				req.Header.Set(middleware.CSRFHeader, tt.header)
+			}
+
+			w := serve(handler, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			if tt.expectedStatus == http.StatusOK && tt.cookie != "" {
+				assertCorrect(t, w.Body.String(), token)
+			}
+		})
+	}
+}
+
+// TestCSRFTokenGeneration tests the token given to clients without one
+func TestCSRFTokenGeneration(t *testing.T) {
+	handler := middleware.CSRF(middleware.CSRFOptions{})(http.HandlerFunc(tokenWriter))
+
+	w := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Got %d cookies, want 1", len(cookies))
+	}
+	assertCorrect(t, cookies[0].Name, middleware.CSRFCookie)
+	assertCorrect(t, cookies[0].Value, w.Body.String())
+	assertCorrect(t, len(cookies[0].Value), 43)
+}
+
+// TestCSRFTemplateField tests the hidden input rendered for forms
+func TestCSRFTemplateField(t *testing.T) {
+	var field string
+	handler := middleware.CSRF(middleware.CSRFOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		field = string(middleware.CSRFTemplateField(r))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: middleware.CSRFCookie, Value: "abc"})
+	serve(handler, req)
+
+	assertCorrect(t, field, `<input type="hidden" name="csrf_token" value="abc">`)
+}
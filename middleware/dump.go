@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/carlos-el/simplerouter/har"
+)
+
+// DumpOptions configures the [Dump] middleware.
+type DumpOptions struct {
+	// MaxBodySize is the maximum number of bytes of each request and response body dumped, 64 KiB if zero.
+	// Longer bodies are truncated in the dump, but not in the request or response.
+	MaxBodySize int
+	// RedactHeaders are the names of the headers whose values are hidden from the dump, besides
+	// the credentials of [har.DefaultRedactedHeaders].
+	RedactHeaders []string
+	// Enabled reports whether the requests are dumped, so dumping can be toggled at runtime.
+	// If nil, every request is dumped.
+	Enabled func(r *http.Request) bool
+}
+
+// Dump returns a middleware that writes every request and its response to out, with their headers and
+// bodies, to debug integration issues during development. Requests are written once served, each one at once,
+// so concurrent requests are not interleaved. Credentials are redacted, but bodies are dumped as they are,
+// so it should not be used in production. Use it on the subtrees being debugged only:
+//
+//	router.Add(simplerouter.NewRoute("/webhooks").Use(middleware.Dump(os.Stderr, middleware.DumpOptions{})))
+func Dump(out io.Writer, opts DumpOptions) func(http.Handler) http.Handler {
+	if opts.MaxBodySize == 0 {
+		opts.MaxBodySize = 64 << 10
+	}
+	redaction := har.Redaction{Headers: opts.RedactHeaders}
+	var mu sync.Mutex
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if opts.Enabled != nil && !opts.Enabled(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The start of the body is read for the dump and put back for the next handlers.
+			var reqBody []byte
+			if r.Body != nil && r.Body != http.NoBody {
+				reqBody, _ = io.ReadAll(io.LimitReader(r.Body, int64(opts.MaxBodySize)+1))
+				r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(reqBody), r.Body), Closer: r.Body}
+			}
+
+			start := time.Now()
+			dw := &dumpWriter{responseWriter: newResponseWriter(w), maxSize: opts.MaxBodySize}
+			next.ServeHTTP(dw, r)
+
+			var b bytes.Buffer
+			fmt.Fprintf(&b, "--> %s %s %s\r\nHost: %s\r\n", r.Method, r.URL.RequestURI(), r.Proto, r.Host)
+			redaction.Header(r.Header).Write(&b)
+			writeDumpBody(&b, reqBody, opts.MaxBodySize, len(reqBody) > opts.MaxBodySize)
+
+			fmt.Fprintf(&b, "<-- %d %s (%s)\r\n", dw.Status(), http.StatusText(dw.Status()), time.Since(start))
+			redaction.Header(w.Header()).Write(&b)
+			writeDumpBody(&b, dw.body.Bytes(), opts.MaxBodySize, dw.written > int64(opts.MaxBodySize))
+
+			mu.Lock()
+			defer mu.Unlock()
+			out.Write(b.Bytes())
+		})
+	}
+}
+
+// readCloser is an io.ReadCloser reading from Reader and closed by Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// writeDumpBody writes the body to the dump, up to maxSize bytes, followed by a blank line.
+func writeDumpBody(b *bytes.Buffer, body []byte, maxSize int, truncated bool) {
+	b.WriteString("\r\n")
+	if len(body) == 0 {
+		return
+	}
+	b.Write(body[:min(len(body), maxSize)])
+	if truncated {
+		b.WriteString("\r\n[truncated]")
+	}
+	b.WriteString("\r\n\r\n")
+}
+
+// dumpWriter wraps a responseWriter keeping the start of the response body for the dump.
+type dumpWriter struct {
+	*responseWriter
+	body    bytes.Buffer
+	maxSize int
+}
+
+// Write keeps the start of the body before writing it.
+func (w *dumpWriter) Write(b []byte) (int, error) {
+	if room := w.maxSize - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(len(b), room)])
+	}
+	return w.responseWriter.Write(b)
+}
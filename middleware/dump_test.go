@@ -0,0 +1,68 @@
+package middleware_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// echoHandler answers with the request body, setting a cookie
+func echoHandler(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+	w.WriteHeader(http.StatusCreated)
+	w.Write(body)
+}
+
+// TestDump tests the requests and responses dumped, with redacted headers and truncated bodies
+func TestDump(t *testing.T) {
+	var out bytes.Buffer
+	handler := middleware.Dump(&out, middleware.DumpOptions{MaxBodySize: 8, RedactHeaders: []string{"X-Api-Key"}})(http.HandlerFunc(echoHandler))
+
+	req := httptest.NewRequest(http.MethodPost, "/users?page=2", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("X-Api-Key", "key")
+	req.Header.Set("Content-Type", "application/json")
+	w := serve(handler, req)
+
+	// The request and response are not affected by the dump.
+	assertCorrect(t, w.Code, http.StatusCreated)
+	assertCorrect(t, w.Body.String(), `{"name":"gopher"}`)
+
+	dump := strings.ReplaceAll(out.String(), "\r\n", "\n")
+	for _, want := range []string{
+		"--> POST /users?page=2 HTTP/1.1\nHost: example.com\n",
+		"Authorization: [REDACTED]\n",
+		"X-Api-Key: [REDACTED]\n\n{\"name\":\n[truncated]\n\n",
+		"<-- 201 Created (",
+		"Set-Cookie: [REDACTED]\n\n{\"name\":\n[truncated]\n\n",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump = %q, want it to contain %q", dump, want)
+		}
+	}
+	if strings.Contains(dump, "secret") || strings.Contains(dump, "token") {
+		t.Errorf("dump = %q, want the credentials redacted", dump)
+	}
+}
+
+// TestDumpWithEnabled tests that the requests are not dumped while dumping is disabled
+func TestDumpWithEnabled(t *testing.T) {
+	var out bytes.Buffer
+	enabled := false
+	handler := middleware.Dump(&out, middleware.DumpOptions{Enabled: func(r *http.Request) bool { return enabled }})(http.HandlerFunc(echoHandler))
+
+	serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	assertCorrect(t, out.Len(), 0)
+
+	enabled = true
+	serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.HasPrefix(out.String(), "--> GET / HTTP/1.1") {
+		t.Errorf("dump = %q, want the request dumped", out.String())
+	}
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ETagOptions configures the [ETag] middleware.
+type ETagOptions struct {
+	// MaxSize is the maximum size in bytes of the responses buffered to compute their ETag, 1 MiB if zero.
+	// Bigger responses are streamed without ETag.
+	MaxSize int
+	// Weak computes weak ETags (W/"..."), for responses that are equivalent but not byte for byte equal.
+	Weak bool
+	// ContentTypes lists the content types of the responses getting an ETag, where "text/*" matches
+	// any text type. If empty, responses of any content type get one.
+	ContentTypes []string
+}
+
+// ETag returns a middleware that buffers the successful responses to GET requests, computes their ETag
+// from a hash of the body and answers the requests whose If-None-Match header matches it with a
+// 304 Not Modified without body, so clients revalidating cached responses do not download them again.
+// ETags set by the next handlers are kept and used in the same way.
+// Responses bigger than the size cap, flushed responses, and responses with other status codes or
+// content types are streamed as they are.
+func ETag(opts ETagOptions) func(http.Handler) http.Handler {
+	if opts.MaxSize == 0 {
+		opts.MaxSize = 1 << 20
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ew := &etagWriter{ResponseWriter: w, maxSize: opts.MaxSize}
+			next.ServeHTTP(ew, r)
+			if ew.streaming {
+				return
+			}
+
+			h := w.Header()
+			if ew.status == 0 {
+				ew.status = http.StatusOK
+			}
+			if ew.status != http.StatusOK || !etagAllowed(opts.ContentTypes, h.Get("Content-Type")) {
+				ew.stream()
+				return
+			}
+
+			etag := h.Get("ETag")
+			if etag == "" {
+				sum := sha256.Sum256(ew.buf.Bytes())
+				etag = `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+				if opts.Weak {
+					etag = "W/" + etag
+				}
+				h.Set("ETag", etag)
+			}
+
+			if etagMatches(r.Header.Get("If-None-Match"), etag) {
+				h.Del("Content-Length")
+				h.Del("Content-Type")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			ew.stream()
+		})
+	}
+}
+
+// etagWriter wraps an http.ResponseWriter buffering the response until its size exceeds maxSize
+// or it is flushed, when it starts streaming it.
+type etagWriter struct {
+	http.ResponseWriter
+	maxSize   int
+	status    int
+	buf       bytes.Buffer
+	streaming bool
+}
+
+// WriteHeader records the status code until the response is streamed.
+// Informational status codes are written right away, as they do not start the final response.
+func (w *etagWriter) WriteHeader(code int) {
+	if code >= 100 && code <= 199 {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.streaming {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+// Write buffers the data, streaming the response if it gets bigger than the size cap.
+func (w *etagWriter) Write(b []byte) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.buf.Len()+len(b) > w.maxSize {
+		if err := w.stream(); err != nil {
+			return 0, err
+		}
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buf.Write(b)
+}
+
+// Flush streams the response and sends any buffered data to the client.
+func (w *etagWriter) Flush() {
+	if !w.streaming {
+		w.stream()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for http.ResponseController.
+func (w *etagWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// stream writes the status code and the buffered data, writing the rest of the response directly.
+func (w *etagWriter) stream() error {
+	w.streaming = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf = bytes.Buffer{}
+	return err
+}
+
+// etagAllowed reports whether responses with the content type get an ETag.
+func etagAllowed(types []string, contentType string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && matchType(types, mediaType)
+}
+
+// etagMatches reports whether the If-None-Match header matches the ETag, with a weak comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestETag tests the ETags computed for responses and the conditional requests answered
+func TestETag(t *testing.T) {
+	body := `{"id":1}`
+	etag := etagOf(t, middleware.ETagOptions{}, typedWriter("application/json", body))
+
+	tests := []struct {
+		name           string
+		opts           middleware.ETagOptions
+		handler        http.Handler
+		method         string
+		ifNoneMatch    string
+		expectedStatus int
+		expectedETag   string
+		expectedBody   string
+	}{
+		{name: "new request", handler: typedWriter("application/json", body), method: http.MethodGet, expectedStatus: http.StatusOK, expectedETag: etag, expectedBody: body},
+		{name: "matching request", handler: typedWriter("application/json", body), method: http.MethodGet, ifNoneMatch: etag, expectedStatus: http.StatusNotModified, expectedETag: etag, expectedBody: ""},
+		{name: "one of several etags", handler: typedWriter("application/json", body), method: http.MethodGet, ifNoneMatch: `"other", W/` + etag, expectedStatus: http.StatusNotModified, expectedETag: etag, expectedBody: ""},
+		{name: "any etag", handler: typedWriter("application/json", body), method: http.MethodGet, ifNoneMatch: "*", expectedStatus: http.StatusNotModified, expectedETag: etag, expectedBody: ""},
+		{name: "stale etag", handler: typedWriter("application/json", body), method: http.MethodGet, ifNoneMatch: `"other"`, expectedStatus: http.StatusOK, expectedETag: etag, expectedBody: body},
+		{name: "weak etag", opts: middleware.ETagOptions{Weak: true}, handler: typedWriter("application/json", body), method: http.MethodGet, expectedStatus: http.StatusOK, expectedETag: "W/" + etag, expectedBody: body},
+		{name: "unsafe method", handler: typedWriter("application/json", body), method: http.MethodPost, ifNoneMatch: etag, expectedStatus: http.StatusOK, expectedETag: "", expectedBody: body},
+		{name: "response over the size cap", opts: middleware.ETagOptions{MaxSize: 4}, handler: typedWriter("application/json", body), method: http.MethodGet, expectedStatus: http.StatusOK, expectedETag: "", expectedBody: body},
+		{name: "content type not allowed", opts: middleware.ETagOptions{ContentTypes: []string{"text/*"}}, handler: typedWriter("application/json", body), method: http.MethodGet, expectedStatus: http.StatusOK, expectedETag: "", expectedBody: body},
+		{name: "content type allowed", opts: middleware.ETagOptions{ContentTypes: []string{"application/json"}}, handler: typedWriter("application/json; charset=utf-8", body), method: http.MethodGet, expectedStatus: http.StatusOK, expectedETag: etag, expectedBody: body},
+		{
+			name: "unsuccessful response",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "missing", http.StatusNotFound)
+			}),
+			method:         http.MethodGet,
+			expectedStatus: http.StatusNotFound,
+			expectedETag:   "",
+			expectedBody:   "missing\n",
+		},
+		{
+			name: "etag set by the handler",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"v1"`)
+				w.Write([]byte(body))
+			}),
+			method:         http.MethodGet,
+			ifNoneMatch:    `"v1"`,
+			expectedStatus: http.StatusNotModified,
+			expectedETag:   `"v1"`,
+			expectedBody:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			w := serve(middleware.ETag(tt.opts)(tt.handler), req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Header().Get("ETag"), tt.expectedETag)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+// TestETagWithFlush tests that flushed responses are streamed without ETag
+func TestETagWithFlush(t *testing.T) {
+	handler := middleware.ETag(middleware.ETagOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("first "))
+		http.NewResponseController(w).Flush()
+		w.Write([]byte("second"))
+	}))
+
+	w := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assertCorrect(t, w.Header().Get("ETag"), "")
+	assertCorrect(t, w.Body.String(), "first second")
+	assertCorrect(t, w.Flushed, true)
+}
+
+// etagOf returns the ETag computed for the response of the handler
+func etagOf(t *testing.T, opts middleware.ETagOptions, handler http.Handler) string {
+	t.Helper()
+	etag := serve(middleware.ETag(opts)(handler), httptest.NewRequest(http.MethodGet, "/", nil)).Header().Get("ETag")
+	if !strings.HasPrefix(etag, `"`) {
+		t.Fatalf("ETag = %q, want a strong ETag", etag)
+	}
+	return etag
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// IPFilterOptions configures the [IPFilterWith] middleware.
+type IPFilterOptions struct {
+	// Allow lists the prefixes of the allowed clients. If empty, clients outside Deny are allowed.
+	Allow []netip.Prefix
+	// Deny lists the prefixes of the blocked clients, blocked even if they are in Allow.
+	Deny []netip.Prefix
+	// TrustedProxies lists the prefixes of the reverse proxies in front of the service, like the load
+	// balancers of the platform. Requests sent by them are identified by the X-Forwarded-For header,
+	// walked from the right-most address and skipping the trusted proxies, as the addresses on their left
+	// are set by the clients, or by the X-Real-IP header without it. If empty, the proxy headers are ignored.
+	TrustedProxies []netip.Prefix
+	// TrueClientIP identifies the requests sent by the trusted proxies by their True-Client-IP header first,
+	// only for CDNs like Akamai or Cloudflare overwriting it, as it is otherwise set by the clients.
+	TrueClientIP bool
+}
+
+// IPFilter returns a middleware that answers the requests of blocked clients with a 403 Forbidden,
+// to restrict subtrees like admin routes to known networks:
+//
+//	office := []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24"), netip.MustParsePrefix("2001:db8::/32")}
+//	router.Add(simplerouter.NewRoute("/admin").Use(middleware.IPFilter(office, nil, false)).Add(...))
+//
+// Clients in any of the deny prefixes are blocked. If allow is not empty, clients outside all of its
+// prefixes are blocked too. Clients are identified by the address of the connection, unless
+// trustProxyHeaders is true: then they are identified by the last address of the X-Forwarded-For header,
+// the one added by the reverse proxy in front of the service, as the previous ones are set by the clients,
+// or by the X-Real-IP header without it. The True-Client-IP header is ignored. Only trust them behind a
+// proxy setting them, otherwise clients can spoof their address; use [IPFilterWith] behind several proxies.
+// Requests whose client address cannot be parsed are blocked.
+func IPFilter(allow, deny []netip.Prefix, trustProxyHeaders bool) func(http.Handler) http.Handler {
+	return ipFilter(IPFilterOptions{Allow: allow, Deny: deny}, trustProxyHeaders)
+}
+
+// IPFilterWith returns a middleware blocking clients as [IPFilter], configured by opts, identifying the
+// clients behind the trusted proxies of opts by their proxy headers.
+func IPFilterWith(opts IPFilterOptions) func(http.Handler) http.Handler {
+	return ipFilter(opts, false)
+}
+
+// ipFilter returns the middleware of [IPFilterWith], trusting the proxy connected to the server if trustPeer is true.
+func ipFilter(opts IPFilterOptions, trustPeer bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addr, ok := opts.clientAddr(r, trustPeer)
+			if !ok || !allowedAddr(addr, opts.Allow, opts.Deny) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientAddr returns the address of the client of the request: the address of the connection, or the
+// first address of the proxy headers that is not a trusted proxy if the request is sent by one.
+// The server is connected to a trusted proxy if trustPeer is true.
+func (o IPFilterOptions) clientAddr(r *http.Request, trustPeer bool) (netip.Addr, bool) {
+	addr, ok := parseClientAddr(clientIP(r))
+	if !ok || !(trustPeer || o.trusts(addr)) {
+		return addr, ok
+	}
+
+	if o.TrueClientIP {
+		if trueClient, ok := parseClientAddr(r.Header.Get("True-Client-IP")); ok {
+			return trueClient, true
+		}
+	}
+	var hops []string
+	for _, forwarded := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(forwarded, ",")...)
+	}
+	if len(hops) == 0 {
+		if realIP := r.Header.Get("X-Real-IP"); realIP != "" {
+			return parseClientAddr(realIP)
+		}
+		return addr, true
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok = parseClientAddr(hops[i])
+		if !ok || !o.trusts(addr) {
+			return addr, ok
+		}
+	}
+	// Every address is a trusted proxy, the left-most one is the closest to the client.
+	return addr, true
+}
+
+// trusts reports whether addr is one of the trusted proxies.
+func (o IPFilterOptions) trusts(addr netip.Addr) bool {
+	for _, prefix := range o.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClientAddr parses the address of a client, reporting whether it is valid.
+func parseClientAddr(s string) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(s))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	// IPv4 clients of dual-stack listeners are reported as IPv4-mapped IPv6 addresses.
+	return addr.Unmap().WithZone(""), true
+}
+
+// allowedAddr reports whether addr is in none of the deny prefixes and, if allow is not empty, in one of its prefixes.
+func allowedAddr(addr netip.Addr, allow, deny []netip.Prefix) bool {
+	for _, prefix := range deny {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	if len(allow) == 0 {
+		return true
+	}
+	for _, prefix := range allow {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestIPFilter tests the clients allowed and blocked by the IP filters
+func TestIPFilter(t *testing.T) {
+	allow := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")}
+	deny := []netip.Prefix{netip.MustParsePrefix("10.0.66.0/24")}
+
+	tests := []struct {
+		name              string
+		allow             []netip.Prefix
+		trustProxyHeaders bool
+		remoteAddr        string
+		headers           map[string][]string
+		expectedStatus    int
+	}{
+		{name: "allowed", allow: allow, remoteAddr: "10.1.2.3:4567", expectedStatus: http.StatusOK},
+		{name: "allowed ipv6", allow: allow, remoteAddr: "[2001:db8::1]:4567", expectedStatus: http.StatusOK},
+		{name: "ipv4-mapped ipv6", allow: allow, remoteAddr: "[::ffff:10.1.2.3]:4567", expectedStatus: http.StatusOK},
+		{name: "not allowed", allow: allow, remoteAddr: "192.0.2.1:4567", expectedStatus: http.StatusForbidden},
+		{name: "denied", allow: allow, remoteAddr: "10.0.66.7:4567", expectedStatus: http.StatusForbidden},
+		{name: "denied without allowlist", remoteAddr: "10.0.66.7:4567", expectedStatus: http.StatusForbidden},
+		{name: "not denied without allowlist", remoteAddr: "192.0.2.1:4567", expectedStatus: http.StatusOK},
+		{name: "untrusted proxy headers", allow: allow, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"X-Forwarded-For": {"10.1.2.3"}}, expectedStatus: http.StatusForbidden},
+		{name: "forwarded client", allow: allow, trustProxyHeaders: true, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"X-Forwarded-For": {"10.1.2.3"}}, expectedStatus: http.StatusOK},
+		{name: "spoofed forwarded client", allow: allow, trustProxyHeaders: true, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"X-Forwarded-For": {"10.1.2.3, 198.51.100.7"}}, expectedStatus: http.StatusForbidden},
+		{name: "last forwarded header", allow: allow, trustProxyHeaders: true, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"X-Forwarded-For": {"198.51.100.7", "10.1.2.3"}}, expectedStatus: http.StatusOK},
+		{name: "real ip", allow: allow, trustProxyHeaders: true, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"X-Real-Ip": {"10.1.2.3"}}, expectedStatus: http.StatusOK},
+		{name: "real ip ignored with forwarded client", allow: allow, trustProxyHeaders: true, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"X-Real-Ip": {"10.1.2.3"}, "X-Forwarded-For": {"198.51.100.7"}}, expectedStatus: http.StatusForbidden},
+		{name: "spoofed true client ip", allow: allow, trustProxyHeaders: true, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"True-Client-Ip": {"10.1.2.3"}, "X-Forwarded-For": {"198.51.100.7"}}, expectedStatus: http.StatusForbidden},
+		{name: "invalid forwarded client", trustProxyHeaders: true, remoteAddr: "10.0.66.7:4567", headers: map[string][]string{"X-Forwarded-For": {"unknown"}}, expectedStatus: http.StatusForbidden},
+		{name: "invalid client", remoteAddr: "unknown", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middleware.IPFilter(tt.allow, deny, tt.trustProxyHeaders)(handlerWriter("served"))
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for name, values := range tt.headers {
+				req.Header[name] = values
+			}
+			w := serve(handler, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+		})
+	}
+}
+
+// TestIPFilterWith tests the clients identified behind trusted proxies
+func TestIPFilterWith(t *testing.T) {
+	opts := middleware.IPFilterOptions{
+		Allow:          []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24"), netip.MustParsePrefix("198.51.100.0/24")},
+	}
+	cdn := opts
+	cdn.TrueClientIP = true
+
+	tests := []struct {
+		name           string
+		opts           middleware.IPFilterOptions
+		remoteAddr     string
+		headers        map[string][]string
+		expectedStatus int
+	}{
+		{name: "client behind proxies", opts: opts, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"X-Forwarded-For": {"10.1.2.3, 198.51.100.7"}}, expectedStatus: http.StatusOK},
+		{name: "spoofed client behind proxies", opts: opts, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"X-Forwarded-For": {"10.1.2.3, 203.0.113.9, 198.51.100.7"}}, expectedStatus: http.StatusForbidden},
+		{name: "untrusted proxy", opts: opts, remoteAddr: "203.0.113.9:4567", headers: map[string][]string{"X-Forwarded-For": {"10.1.2.3"}}, expectedStatus: http.StatusForbidden},
+		{name: "direct client", opts: opts, remoteAddr: "10.1.2.3:4567", headers: map[string][]string{"X-Forwarded-For": {"203.0.113.9"}}, expectedStatus: http.StatusOK},
+		{name: "only proxies", opts: opts, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"X-Forwarded-For": {"198.51.100.7"}}, expectedStatus: http.StatusForbidden},
+		{name: "ignored true client ip", opts: opts, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"True-Client-Ip": {"10.1.2.3"}}, expectedStatus: http.StatusForbidden},
+		{name: "true client ip", opts: cdn, remoteAddr: "192.0.2.1:4567", headers: map[string][]string{"True-Client-Ip": {"10.1.2.3"}, "X-Forwarded-For": {"203.0.113.9"}}, expectedStatus: http.StatusOK},
+		{name: "true client ip from untrusted proxy", opts: cdn, remoteAddr: "203.0.113.9:4567", headers: map[string][]string{"True-Client-Ip": {"10.1.2.3"}}, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := middleware.IPFilterWith(tt.opts)(handlerWriter("served"))
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for name, values := range tt.headers {
+				req.Header[name] = values
+			}
+			w := serve(handler, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+		})
+	}
+}
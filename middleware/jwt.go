@@ -0,0 +1,310 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JWTKeyFunc returns the key verifying the signature of a token, given the alg and kid of its header.
+// Keys are []byte for HMAC algorithms (HS256, HS384, HS512), *rsa.PublicKey for RSA algorithms
+// (RS256, RS384, RS512, PS256, PS384, PS512), *ecdsa.PublicKey for ECDSA algorithms (ES256, ES384, ES512)
+// and ed25519.PublicKey for EdDSA. Returning an error rejects the token.
+type JWTKeyFunc func(alg, kid string) (any, error)
+
+// JWTOption configures the [JWT] middleware.
+type JWTOption func(*jwtConfig)
+
+// jwtConfig holds the checks of the [JWT] middleware.
+type jwtConfig struct {
+	audience string
+	issuer   string
+	leeway   time.Duration
+}
+
+// JWTAudience requires the tokens to include audience in their aud claim.
+func JWTAudience(audience string) JWTOption {
+	return func(c *jwtConfig) { c.audience = audience }
+}
+
+// JWTIssuer requires the tokens to have issuer as their iss claim.
+func JWTIssuer(issuer string) JWTOption {
+	return func(c *jwtConfig) { c.issuer = issuer }
+}
+
+// JWTLeeway tolerates the given clock difference when checking the exp and nbf claims.
+func JWTLeeway(leeway time.Duration) JWTOption {
+	return func(c *jwtConfig) { c.leeway = leeway }
+}
+
+// Claims are the claims of a JSON Web Token validated by the [JWT] middleware.
+type Claims struct {
+	// Issuer is the iss claim.
+	Issuer string
+	// Subject is the sub claim, usually identifying the user.
+	Subject string
+	// Audience is the aud claim.
+	Audience []string
+	// ExpiresAt is the exp claim, zero if not set.
+	ExpiresAt time.Time
+	// NotBefore is the nbf claim, zero if not set.
+	NotBefore time.Time
+	// IssuedAt is the iat claim, zero if not set.
+	IssuedAt time.Time
+	// ID is the jti claim.
+	ID string
+	// Raw holds every claim of the token, including the registered ones, as decoded from JSON.
+	Raw map[string]any
+}
+
+// claimsKey is the context key storing the claims of the token.
+type claimsKey struct{}
+
+// JWT returns a middleware that requires requests to carry a JSON Web Token in their
+// "Authorization: Bearer" header, signed with the key returned by keyFunc and valid at the time
+// of the request (exp and nbf claims), and checks its audience and issuer if configured by opts.
+// The claims of valid tokens are available to the next handlers with [ClaimsFrom]. Other requests
+// are answered with a 401 Unauthorized and a WWW-Authenticate header, as defined by RFC 6750.
+// Attaching it to a subtree, like /api, requires authentication in all of its routes.
+func JWT(keyFunc JWTKeyFunc, opts ...JWTOption) func(http.Handler) http.Handler {
+	if keyFunc == nil {
+		panic("keyFunc parameter cannot be nil")
+	}
+	config := &jwtConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := parseJWT(token, keyFunc, config, time.Now())
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf("Bearer error=\"invalid_token\", error_description=%q", err.Error()))
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+		})
+	}
+}
+
+// ClaimsFrom returns the claims of the token stored in ctx by [JWT], or nil if there are none.
+func ClaimsFrom(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*Claims)
+	return claims
+}
+
+// bearerToken returns the token of the Authorization header of the request.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// parseJWT verifies the signature and the claims of the token at the given time.
+func parseJWT(token string, keyFunc JWTKeyFunc, config *jwtConfig, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errors.New("malformed header")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed signature")
+	}
+
+	key, err := keyFunc(header.Alg, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	raw := map[string]any{}
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, errors.New("malformed claims")
+	}
+	claims, err := newClaims(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !claims.ExpiresAt.IsZero() && !now.Before(claims.ExpiresAt.Add(config.leeway)):
+		return nil, errors.New("token is expired")
+	case !claims.NotBefore.IsZero() && now.Before(claims.NotBefore.Add(-config.leeway)):
+		return nil, errors.New("token is not valid yet")
+	case config.issuer != "" && claims.Issuer != config.issuer:
+		return nil, errors.New("invalid issuer")
+	case config.audience != "" && !slices.Contains(claims.Audience, config.audience):
+		return nil, errors.New("invalid audience")
+	}
+	return claims, nil
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a token into v.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// verifySignature verifies the signature of the signed part of a token with the key for the algorithm.
+func verifySignature(alg string, key any, signed string, signature []byte) error {
+	invalid := errors.New("invalid signature")
+
+	var hash crypto.Hash
+	switch alg[len(alg)-min(len(alg), 3):] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	}
+	var digest []byte
+	if hash != 0 {
+		h := hash.New()
+		h.Write([]byte(signed))
+		digest = h.Sum(nil)
+	}
+
+	switch k := key.(type) {
+	case []byte:
+		if !strings.HasPrefix(alg, "HS") || hash == 0 {
+			break
+		}
+		mac := hmac.New(hash.New, k)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return invalid
+		}
+		return nil
+	case *rsa.PublicKey:
+		var err error
+		switch {
+		case strings.HasPrefix(alg, "RS") && hash != 0:
+			err = rsa.VerifyPKCS1v15(k, hash, digest, signature)
+		case strings.HasPrefix(alg, "PS") && hash != 0:
+			err = rsa.VerifyPSS(k, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		default:
+			return fmt.Errorf("unexpected algorithm %q for an RSA key", alg)
+		}
+		if err != nil {
+			return invalid
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") || hash == 0 {
+			break
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return invalid
+		}
+		rInt := new(big.Int).SetBytes(signature[:size])
+		sInt := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, rInt, sInt) {
+			return invalid
+		}
+		return nil
+	case ed25519.PublicKey:
+		if alg != "EdDSA" {
+			break
+		}
+		if !ed25519.Verify(k, []byte(signed), signature) {
+			return invalid
+		}
+		return nil
+	}
+	return fmt.Errorf("unexpected algorithm %q for a %T key", alg, key)
+}
+
+// newClaims returns the claims decoded from the raw claims of a token.
+func newClaims(raw map[string]any) (*Claims, error) {
+	claims := &Claims{Raw: raw}
+	var ok bool
+	for name, value := range raw {
+		switch name {
+		case "iss":
+			claims.Issuer, ok = value.(string)
+		case "sub":
+			claims.Subject, ok = value.(string)
+		case "jti":
+			claims.ID, ok = value.(string)
+		case "aud":
+			claims.Audience, ok = audience(value)
+		case "exp":
+			claims.ExpiresAt, ok = numericDate(value)
+		case "nbf":
+			claims.NotBefore, ok = numericDate(value)
+		case "iat":
+			claims.IssuedAt, ok = numericDate(value)
+		default:
+			ok = true
+		}
+		if !ok {
+			return nil, fmt.Errorf("malformed %s claim", name)
+		}
+	}
+	return claims, nil
+}
+
+// audience returns the aud claim, which can be a string or an array of strings.
+func audience(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, true
+	case []any:
+		audience := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			audience[i] = s
+		}
+		return audience, true
+	}
+	return nil, false
+}
+
+// numericDate returns the time of a claim with the number of seconds since the Unix epoch.
+func numericDate(value any) (time.Time, bool) {
+	seconds, ok := value.(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(seconds * 1000)), true
+}
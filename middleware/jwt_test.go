@@ -0,0 +1,135 @@
+package middleware_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+var jwtSecret = []byte("secret")
+
+// signHS256 creates a token with the given claims signed with HS256 and jwtSecret
+func signHS256(claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// hmacKey is a key function returning jwtSecret
+func hmacKey(alg, kid string) (any, error) {
+	return jwtSecret, nil
+}
+
+// subjectWriter is a handler writing the subject of the claims of the request
+func subjectWriter(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(middleware.ClaimsFrom(r.Context()).Subject))
+}
+
+// TestJWT tests the validation of the tokens of requests
+func TestJWT(t *testing.T) {
+	now := time.Now().Unix()
+	handler := middleware.JWT(hmacKey,
+		middleware.JWTIssuer("auth.example.com"),
+		middleware.JWTAudience("api"),
+	)(http.HandlerFunc(subjectWriter))
+
+	valid := map[string]any{"sub": "42", "iss": "auth.example.com", "aud": []string{"api", "web"}, "exp": now + 60}
+	with := func(key string, value any) map[string]any {
+		claims := map[string]any{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		claims[key] = value
+		return claims
+	}
+
+	tests := []struct {
+		name                    string
+		authorization           string
+		expectedStatus          int
+		expectedBody            string
+		expectedWWWAuthenticate string
+	}{
+		{name: "valid token", authorization: "Bearer " + signHS256(valid), expectedStatus: http.StatusOK, expectedBody: "42"},
+		{name: "single audience", authorization: "bearer " + signHS256(with("aud", "api")), expectedStatus: http.StatusOK, expectedBody: "42"},
+		{name: "missing token", authorization: "", expectedStatus: http.StatusUnauthorized, expectedWWWAuthenticate: "Bearer"},
+		{name: "other scheme", authorization: "Basic dXNlcjpwYXNz", expectedStatus: http.StatusUnauthorized, expectedWWWAuthenticate: "Bearer"},
+		{name: "malformed token", authorization: "Bearer abc", expectedStatus: http.StatusUnauthorized, expectedWWWAuthenticate: `Bearer error="invalid_token", error_description="malformed token"`},
+		{name: "tampered token", authorization: "Bearer " + signHS256(valid) + "x", expectedStatus: http.StatusUnauthorized, expectedWWWAuthenticate: `Bearer error="invalid_token", error_description="invalid signature"`},
+		{name: "expired token", authorization: "Bearer " + signHS256(with("exp", now-60)), expectedStatus: http.StatusUnauthorized, expectedWWWAuthenticate: `Bearer error="invalid_token", error_description="token is expired"`},
+		{name: "token not valid yet", authorization: "Bearer " + signHS256(with("nbf", now+60)), expectedStatus: http.StatusUnauthorized, expectedWWWAuthenticate: `Bearer error="invalid_token", error_description="token is not valid yet"`},
+		{name: "wrong issuer", authorization: "Bearer " + signHS256(with("iss", "other")), expectedStatus: http.StatusUnauthorized, expectedWWWAuthenticate: `Bearer error="invalid_token", error_description="invalid issuer"`},
+		{name: "wrong audience", authorization: "Bearer " + signHS256(with("aud", "web")), expectedStatus: http.StatusUnauthorized, expectedWWWAuthenticate: `Bearer error="invalid_token", error_description="invalid audience"`},
+		{name: "malformed claim", authorization: "Bearer " + signHS256(with("exp", "tomorrow")), expectedStatus: http.StatusUnauthorized, expectedWWWAuthenticate: `Bearer error="invalid_token", error_description="malformed exp claim"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := serve(handler, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Header().Get("WWW-Authenticate"), tt.expectedWWWAuthenticate)
+			if tt.expectedStatus == http.StatusOK {
+				assertCorrect(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+// TestJWTWithECDSA tests tokens signed with ECDSA keys and algorithms not matching the key
+func TestJWTWithECDSA(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	handler := middleware.JWT(func(alg, kid string) (any, error) {
+		return &key.PublicKey, nil
+	})(http.HandlerFunc(subjectWriter))
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256","kid":"1"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"7"}`))
+	digest := sha256.Sum256([]byte(header + "." + payload))
+	r, s, _ := ecdsa.Sign(rand.Reader, key, digest[:])
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	token := header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := serve(handler, req)
+	assertCorrect(t, w.Code, http.StatusOK)
+	assertCorrect(t, w.Body.String(), "7")
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signHS256(map[string]any{"sub": "7"}))
+	w = serve(handler, req)
+	assertCorrect(t, w.Code, http.StatusUnauthorized)
+	if !strings.Contains(w.Header().Get("WWW-Authenticate"), "unexpected algorithm") {
+		t.Errorf("WWW-Authenticate = %q, want an unexpected algorithm error", w.Header().Get("WWW-Authenticate"))
+	}
+}
+
+// TestClaimsFromWithoutClaims tests the claims of contexts without them
+func TestClaimsFromWithoutClaims(t *testing.T) {
+	if claims := middleware.ClaimsFrom(httptest.NewRequest(http.MethodGet, "/", nil).Context()); claims != nil {
+		t.Errorf("ClaimsFrom() = %v, want nil", claims)
+	}
+}
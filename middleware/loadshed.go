@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LoadShedOptions configures the [LoadShed] middleware. At least one of MaxLatency and Signal must be set.
+type LoadShedOptions struct {
+	// MaxLatency is the 99th percentile of the latency of the requests over which requests are shed.
+	// The latency is not watched if zero.
+	MaxLatency time.Duration
+	// Window is the period over which the latency percentile is computed, 10 seconds if zero.
+	// Only the last 4096 requests of the window are taken into account.
+	Window time.Duration
+	// MinSamples is the number of requests served in the window before the latency is taken into account, 100 if zero.
+	MinSamples int
+	// Signal returns a measure of the load of the service, like its CPU usage or the depth of a queue,
+	// over which requests are shed. It is called at most 10 times per second.
+	Signal func() float64
+	// MaxSignal is the value of Signal over which requests are shed.
+	MaxSignal float64
+	// MaxShedRatio is the highest fraction of requests shed, 0.9 if zero, so the requests still served
+	// tell when the overload is over.
+	MaxShedRatio float64
+	// RetryAfter is the delay sent to the clients of the shed requests in the Retry-After header, 1 second if zero.
+	RetryAfter time.Duration
+}
+
+const (
+	// loadShedEvalInterval is the minimum time between two evaluations of the load by [LoadShed].
+	loadShedEvalInterval = 100 * time.Millisecond
+	// maxLatencySamples is the maximum number of latencies kept by [LoadShed].
+	maxLatencySamples = 4096
+)
+
+// LoadShed returns a middleware that rejects a fraction of the requests with a 503 Service Unavailable
+// and a Retry-After header while the service is overloaded, so the requests it accepts are still served
+// in time instead of all of them timing out. The service is overloaded when the 99th percentile of the
+// latency of its requests exceeds MaxLatency, or when Signal exceeds MaxSignal. The fraction of requests
+// shed grows with the overload: with a percentile twice MaxLatency, half the requests are shed.
+// Use it at the root of the tree, and wrap it with [Skip] to keep serving health checks:
+//
+//	router.Use(middleware.Skip(
+//		middleware.LoadShed(middleware.LoadShedOptions{MaxLatency: 500 * time.Millisecond}),
+//		func(r *http.Request) bool { return r.URL.Path == "/healthz" },
+//	))
+//
+// It panics if neither MaxLatency nor Signal is set.
+func LoadShed(opts LoadShedOptions) func(http.Handler) http.Handler {
+	if opts.MaxLatency <= 0 && opts.Signal == nil {
+		panic("opts parameter must set MaxLatency or Signal")
+	}
+	if opts.Window == 0 {
+		opts.Window = 10 * time.Second
+	}
+	if opts.MinSamples == 0 {
+		opts.MinSamples = 100
+	}
+	if opts.MaxShedRatio == 0 {
+		opts.MaxShedRatio = 0.9
+	}
+	if opts.RetryAfter == 0 {
+		opts.RetryAfter = time.Second
+	}
+	shedder := &loadShedder{opts: opts}
+	retryAfter := strconv.Itoa(int(math.Ceil(opts.RetryAfter.Seconds())))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ratio := shedder.shedRatio(); ratio > 0 && rand.Float64() < ratio {
+				w.Header().Set("Retry-After", retryAfter)
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+
+			start := time.Now()
+			next.ServeHTTP(w, r)
+			shedder.record(start, time.Since(start))
+		})
+	}
+}
+
+// latencySample is the latency of a request served by [LoadShed], finished at end.
+type latencySample struct {
+	end     time.Time
+	latency time.Duration
+}
+
+// loadShedder keeps the latencies of the last requests and the fraction of requests to shed.
+type loadShedder struct {
+	opts LoadShedOptions
+	// ratio holds the float64 bits of the fraction of requests to shed.
+	ratio atomic.Uint64
+	// evaluated holds the time of the last evaluation of the load, in Unix nanoseconds.
+	evaluated atomic.Int64
+
+	mu      sync.Mutex
+	samples []latencySample
+}
+
+// record adds the latency of a request served, dropping the ones out of the window.
+func (s *loadShedder) record(start time.Time, latency time.Duration) {
+	if s.opts.MaxLatency <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	end := start.Add(latency)
+	s.samples = append(s.samples, latencySample{end: end, latency: latency})
+	// Samples are recorded in about the order they end, so the expired ones are at the start.
+	expired := max(len(s.samples)-maxLatencySamples, 0)
+	for expired < len(s.samples) && end.Sub(s.samples[expired].end) > s.opts.Window {
+		expired++
+	}
+	s.samples = s.samples[expired:]
+}
+
+// shedRatio returns the fraction of requests to shed, evaluating the load again if it is due.
+func (s *loadShedder) shedRatio() float64 {
+	now := time.Now().UnixNano()
+	last := s.evaluated.Load()
+	if now-last >= int64(loadShedEvalInterval) && s.evaluated.CompareAndSwap(last, now) {
+		s.ratio.Store(math.Float64bits(s.evaluate()))
+	}
+	return math.Float64frombits(s.ratio.Load())
+}
+
+// evaluate computes the fraction of requests to shed from the latency percentile and the signal.
+func (s *loadShedder) evaluate() float64 {
+	ratio := 0.0
+	if p99 := s.p99(); s.opts.MaxLatency > 0 && p99 > s.opts.MaxLatency {
+		ratio = 1 - float64(s.opts.MaxLatency)/float64(p99)
+	}
+	if s.opts.Signal != nil {
+		if signal := s.opts.Signal(); signal > s.opts.MaxSignal && signal > 0 {
+			ratio = max(ratio, 1-s.opts.MaxSignal/signal)
+		}
+	}
+	return min(ratio, s.opts.MaxShedRatio)
+}
+
+// p99 returns the 99th percentile of the latencies in the window, or zero if there are too few of them.
+func (s *loadShedder) p99() time.Duration {
+	s.mu.Lock()
+	cutoff := time.Now().Add(-s.opts.Window)
+	latencies := make([]time.Duration, 0, len(s.samples))
+	for _, sample := range s.samples {
+		if sample.end.After(cutoff) {
+			latencies = append(latencies, sample.latency)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(latencies) < s.opts.MinSamples {
+		return 0
+	}
+	slices.Sort(latencies)
+	return latencies[(len(latencies)*99-1)/100]
+}
@@ -0,0 +1,90 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// shedCount serves n requests with handler, returning how many of them were shed
+func shedCount(t *testing.T, handler http.Handler, n int) int {
+	t.Helper()
+	shed := 0
+	for range n {
+		w := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code == http.StatusServiceUnavailable {
+			shed++
+			assertCorrect(t, w.Header().Get("Retry-After"), "2")
+		}
+	}
+	return shed
+}
+
+// TestLoadShedWithSignal tests that the fraction of requests shed follows the load signal
+func TestLoadShedWithSignal(t *testing.T) {
+	var load atomic.Int64
+	handler := middleware.LoadShed(middleware.LoadShedOptions{
+		Signal:     func() float64 { return float64(load.Load()) },
+		MaxSignal:  10,
+		RetryAfter: 1500 * time.Millisecond,
+	})(handlerWriter("served"))
+
+	tests := []struct {
+		name    string
+		load    int64
+		minShed int
+		maxShed int
+	}{
+		{name: "under the threshold", load: 5, minShed: 0, maxShed: 0},
+		{name: "twice the threshold", load: 20, minShed: 400, maxShed: 600},
+		{name: "capped", load: 1000, minShed: 850, maxShed: 950},
+		{name: "recovered", load: 10, minShed: 0, maxShed: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			load.Store(tt.load)
+			time.Sleep(150 * time.Millisecond)
+			if shed := shedCount(t, handler, 1000); shed < tt.minShed || shed > tt.maxShed {
+				t.Errorf("got %d shed requests out of 1000, want between %d and %d", shed, tt.minShed, tt.maxShed)
+			}
+		})
+	}
+}
+
+// TestLoadShedWithLatency tests that requests are shed once the latency percentile exceeds the threshold
+func TestLoadShedWithLatency(t *testing.T) {
+	var delay atomic.Int64
+	handler := middleware.LoadShed(middleware.LoadShedOptions{
+		MaxLatency: 2 * time.Millisecond,
+		MinSamples: 20,
+		RetryAfter: 2 * time.Second,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Duration(delay.Load()))
+	}))
+
+	// Fast requests are not shed.
+	assertCorrect(t, shedCount(t, handler, 50), 0)
+
+	delay.Store(int64(8 * time.Millisecond))
+	shedCount(t, handler, 20)
+	delay.Store(0)
+	time.Sleep(150 * time.Millisecond)
+	if shed := shedCount(t, handler, 1000); shed < 100 {
+		t.Errorf("got %d shed requests out of 1000 with slow requests, want some", shed)
+	}
+}
+
+// TestLoadShedWithoutThresholds tests that the middleware needs a latency or signal threshold
+func TestLoadShedWithoutThresholds(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("LoadShed() did not panic")
+		}
+	}()
+	middleware.LoadShed(middleware.LoadShedOptions{})
+}
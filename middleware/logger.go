@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Logger returns a middleware that logs each request once it is served, with its method, path,
+// status code, response size, duration and request ID. If logger is nil, slog.Default is used.
+func Logger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := newResponseWriter(w)
+
+			next.ServeHTTP(rw, r)
+
+			l := logger
+			if l == nil {
+				l = slog.Default()
+			}
+			l.InfoContext(r.Context(), "request served",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rw.Status(),
+				"bytes", rw.written,
+				"duration", time.Since(start),
+				"remote_addr", r.RemoteAddr,
+				"request_id", RequestIDFrom(r.Context()),
+			)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package middleware_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestLogger tests the log line written for served requests
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	handler := middleware.Logger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	}))
+
+	serve(handler, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	for _, want := range []string{"method=POST", "path=/users", "status=201", "bytes=7"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Log = %q, want it to contain %q", buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// MaxBytes returns a middleware that limits the size of request bodies to n bytes.
+// Requests declaring a larger Content-Length are answered right away with a 413 Request Entity Too Large
+// JSON error. Otherwise, the body is wrapped with http.MaxBytesReader, and if the next handlers read past
+// the limit, the response they write is replaced by the same 413 error, unless they had already started it.
+func MaxBytes(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				writeTooLarge(w)
+				return
+			}
+			if r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body := &maxBytesBody{ReadCloser: http.MaxBytesReader(w, r.Body, n)}
+			r.Body = body
+			mw := &maxBytesWriter{ResponseWriter: w, body: body}
+			next.ServeHTTP(mw, r)
+
+			if body.exceeded && !mw.started && !mw.replaced {
+				mw.replace()
+			}
+		})
+	}
+}
+
+// maxBytesBody wraps a request body limited by http.MaxBytesReader recording whether the limit was exceeded.
+type maxBytesBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+// Read records whether the limit was exceeded before returning the result of the read.
+func (b *maxBytesBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// maxBytesWriter wraps an http.ResponseWriter replacing the response with a 413 error
+// if the limit of the request body was exceeded before the response started.
+type maxBytesWriter struct {
+	http.ResponseWriter
+	body     *maxBytesBody
+	started  bool
+	replaced bool
+}
+
+// WriteHeader writes the status code, or the 413 error if the limit was exceeded.
+// Informational status codes are written as they are, as they do not start the final response.
+func (w *maxBytesWriter) WriteHeader(code int) {
+	if w.replaced {
+		return
+	}
+	if code >= 100 && code <= 199 {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if !w.started && w.body.exceeded {
+		w.replace()
+		return
+	}
+	w.started = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes the data, or discards it if the response was replaced by the 413 error.
+func (w *maxBytesWriter) Write(b []byte) (int, error) {
+	if !w.started && !w.replaced {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.replaced {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends any buffered data to the client if the wrapped http.ResponseWriter supports it.
+func (w *maxBytesWriter) Flush() {
+	if !w.started && !w.replaced {
+		w.WriteHeader(http.StatusOK)
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for http.ResponseController.
+func (w *maxBytesWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// replace writes the 413 error instead of the response of the next handlers.
+func (w *maxBytesWriter) replace() {
+	w.replaced = true
+	// Headers set by the next handlers for the discarded body would not match the error.
+	w.Header().Del("Content-Length")
+	w.Header().Del("Content-Encoding")
+	writeTooLarge(w.ResponseWriter)
+}
+
+// writeTooLarge writes a 413 Request Entity Too Large JSON error.
+func writeTooLarge(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	json.NewEncoder(w).Encode(map[string]string{"error": http.StatusText(http.StatusRequestEntityTooLarge)})
+}
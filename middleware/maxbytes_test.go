@@ -0,0 +1,93 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// readBody reads the whole request body, answering with a 400 status code if it fails
+func readBody(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write(body)
+}
+
+// TestMaxBytes tests the responses to request bodies of different sizes
+func TestMaxBytes(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		unknownLength  bool
+		handler        http.HandlerFunc
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "body within the limit",
+			body:           "hello",
+			handler:        readBody,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "hello",
+		},
+		{
+			name:           "declared length over the limit",
+			body:           "hello world",
+			handler:        readBody,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"Request Entity Too Large"}` + "\n",
+		},
+		{
+			name:           "unknown length over the limit",
+			body:           "hello world",
+			unknownLength:  true,
+			handler:        readBody,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"Request Entity Too Large"}` + "\n",
+		},
+		{
+			name:          "handler writing nothing after the limit",
+			body:          "hello world",
+			unknownLength: true,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				io.ReadAll(r.Body)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"Request Entity Too Large"}` + "\n",
+		},
+		{
+			name:          "response started before the limit",
+			body:          "hello world",
+			unknownLength: true,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				io.ReadAll(r.Body)
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.unknownLength {
+				req.ContentLength = -1
+			}
+
+			w := serve(middleware.MaxBytes(8)(tt.handler), req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"expvar"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// metricsMu serializes the lookups and publications of the metrics maps.
+var metricsMu sync.Mutex
+
+// Metrics returns a middleware that records request metrics published with the expvar package
+// under the "http" map: the total number of requests, the number of requests by status code,
+// the number of requests in flight and the accumulated serving time in microseconds.
+// It is [MetricsMap] with the "http" name.
+func Metrics() func(http.Handler) http.Handler {
+	return MetricsMap("http")
+}
+
+// MetricsMap returns a middleware that records the request metrics of [Metrics] under the expvar map
+// with the given name, so they do not collide with the variables published by other packages, or so
+// subtrees are measured separately. The map is published on the first call with the name, and shared by
+// the middlewares returned by the next ones. It panics if name is empty, or if another package already
+// published a variable that is not a map under the name.
+func MetricsMap(name string) func(http.Handler) http.Handler {
+	if name == "" {
+		panic("name parameter cannot be empty")
+	}
+	metrics := metricsMap(name)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := newResponseWriter(w)
+			metrics.Add("requests_in_flight", 1)
+
+			defer func() {
+				metrics.Add("requests_in_flight", -1)
+				metrics.Add("requests_total", 1)
+				metrics.Add("requests_status_"+strconv.Itoa(rw.Status()), 1)
+				metrics.Add("duration_microseconds_total", time.Since(start).Microseconds())
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// metricsMap returns the expvar map published under name, publishing it if there is none.
+func metricsMap(name string) *expvar.Map {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	v := expvar.Get(name)
+	if v == nil {
+		return expvar.NewMap(name)
+	}
+	m, ok := v.(*expvar.Map)
+	if !ok {
+		panic("expvar variable " + strconv.Quote(name) + " is already published and is not a map")
+	}
+	return m
+}
@@ -0,0 +1,49 @@
+package middleware_test
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// metricValue returns the value of a metric of the published map with the given name
+func metricValue(mapName, name string) int64 {
+	value, ok := expvar.Get(mapName).(*expvar.Map).Get(name).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+	return value.Value()
+}
+
+// TestMetrics tests the request counters published with expvar
+func TestMetrics(t *testing.T) {
+	handler := middleware.Metrics()(http.NotFoundHandler())
+	total := metricValue("http", "requests_total")
+	notFound := metricValue("http", "requests_status_404")
+
+	serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	serve(middleware.Metrics()(http.NotFoundHandler()), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assertCorrect(t, metricValue("http", "requests_total"), total+2)
+	assertCorrect(t, metricValue("http", "requests_status_404"), notFound+2)
+	assertCorrect(t, metricValue("http", "requests_in_flight"), int64(0))
+}
+
+// TestMetricsMap tests the metrics recorded under maps already published or with another type
+func TestMetricsMap(t *testing.T) {
+	published := expvar.NewMap("metrics_test_published")
+	published.Add("requests_total", 5)
+	serve(middleware.MetricsMap("metrics_test_published")(http.NotFoundHandler()), httptest.NewRequest(http.MethodGet, "/", nil))
+	assertCorrect(t, metricValue("metrics_test_published", "requests_total"), int64(6))
+
+	expvar.NewString("metrics_test_string")
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MetricsMap() with a published string did not panic")
+		}
+	}()
+	middleware.MetricsMap("metrics_test_string")
+}
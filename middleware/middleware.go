@@ -0,0 +1,60 @@
+// Package middleware provides net/http compatible middlewares commonly needed by services
+// built with simplerouter. Every middleware is a func(http.Handler) http.Handler,
+// so it can be passed directly to [github.com/carlos-el/simplerouter.Route.Use] or used with any other router.
+package middleware
+
+import (
+	"net/http"
+)
+
+// responseWriter wraps an http.ResponseWriter recording the status code and the number of bytes written.
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+// newResponseWriter returns a responseWriter wrapping w.
+func newResponseWriter(w http.ResponseWriter) *responseWriter {
+	return &responseWriter{ResponseWriter: w}
+}
+
+// WriteHeader records the status code before writing it.
+// Informational status codes are not recorded, as they are not the final response status.
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 && (code < 100 || code > 199) {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write records the number of bytes written, setting the status to 200 if none was written.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.written += int64(n)
+	return n, err
+}
+
+// Flush sends any buffered data to the client if the wrapped http.ResponseWriter supports it.
+func (w *responseWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status returns the status code of the response, 200 if the handler wrote nothing.
+func (w *responseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
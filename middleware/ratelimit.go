@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitStore keeps the token buckets of the [RateLimitWith] middleware,
+// so they can be shared by several instances of a service (with Redis, for example).
+type RateLimitStore interface {
+	// Take consumes a token from the bucket of key, holding up to limit tokens refilled over window.
+	// It reports whether a token was available and, if not, how long until the next one is.
+	Take(ctx context.Context, key string, limit int, window time.Duration) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// RateLimit returns a middleware that limits the requests of each client to limit per window,
+// with a token bucket kept in memory, so bursts of up to limit requests are allowed.
+// Clients are identified by keyFn, or by their IP if keyFn is nil (use [RealIP] first behind a proxy).
+// Requests over the limit are answered with a 429 Too Many Requests and a Retry-After header.
+// Buckets are only kept by the returned middleware, so each route or subtree using its own
+// RateLimit call has independent limits.
+func RateLimit(limit int, window time.Duration, keyFn func(r *http.Request) string) func(http.Handler) http.Handler {
+	return RateLimitWith(NewMemoryRateLimitStore(), limit, window, keyFn)
+}
+
+// RateLimitWith does the same as [RateLimit], but the buckets are kept by store.
+// Requests are let through if the store fails, logging its error, so an outage of the store does not
+// take the service down.
+func RateLimitWith(store RateLimitStore, limit int, window time.Duration, keyFn func(r *http.Request) string) func(http.Handler) http.Handler {
+	if limit <= 0 || window <= 0 {
+		panic("limit and window parameters must be greater than zero")
+	}
+	if keyFn == nil {
+		keyFn = clientIP
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, retryAfter, err := store.Take(r.Context(), keyFn(r), limit, window)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "rate limit store failed",
+					"error", err,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"request_id", RequestIDFrom(r.Context()),
+				)
+			} else if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the IP of the client of the request.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// bucket is a token bucket of a [MemoryRateLimitStore].
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryRateLimitStore is a [RateLimitStore] keeping the buckets in memory.
+// Full buckets are removed periodically. It is safe for concurrent use.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimitStore returns an empty [MemoryRateLimitStore].
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: map[string]*bucket{}}
+}
+
+// Take consumes a token from the bucket of key, refilling it for the time elapsed since the last take.
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	rate := float64(limit) / window.Seconds()
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) > window {
+		for k, b := range s.buckets {
+			if b.tokens+now.Sub(b.last).Seconds()*rate >= float64(limit) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / rate * float64(time.Second)), nil
+	}
+	b.tokens--
+	return true, 0, nil
+}
+
+// storeRateLimit is a [RateLimitStore] keeping the buckets in a [Store].
+type storeRateLimit struct {
+	mu    sync.Mutex
+	store Store
+}
+
+// RateLimitStoreFrom returns a [RateLimitStore] keeping the buckets in store, to share the limits of
+// several instances of a service with any [Store] backend. Buckets are read and written in separate
+// operations, so concurrent requests of a client served by different instances may take the same token.
+// Backends supporting atomic operations can implement [RateLimitStore] directly for exact limits.
+func RateLimitStoreFrom(store Store) RateLimitStore {
+	return &storeRateLimit{store: store}
+}
+
+// Take consumes a token from the bucket of key stored as its tokens and last take time.
+func (s *storeRateLimit) Take(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	rate := float64(limit) / window.Seconds()
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := bucket{tokens: float64(limit), last: now}
+	value, ok, err := s.store.Get(ctx, "ratelimit:"+key)
+	if err != nil {
+		return false, 0, err
+	}
+	if ok {
+		tokens, last, found := strings.Cut(string(value), " ")
+		t, err1 := strconv.ParseFloat(tokens, 64)
+		l, err2 := strconv.ParseInt(last, 10, 64)
+		if found && err1 == nil && err2 == nil {
+			b = bucket{tokens: t, last: time.Unix(0, l)}
+		}
+	}
+	b.tokens = math.Min(float64(limit), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	allowed, retryAfter := b.tokens >= 1, time.Duration(0)
+	if allowed {
+		b.tokens--
+	} else {
+		retryAfter = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+
+	// The bucket is full again after window, when it is the same as a missing one.
+	value = []byte(strconv.FormatFloat(b.tokens, 'f', -1, 64) + " " + strconv.FormatInt(now.UnixNano(), 10))
+	if err := s.store.Set(ctx, "ratelimit:"+key, value, window); err != nil {
+		return false, 0, err
+	}
+	return allowed, retryAfter, nil
+}
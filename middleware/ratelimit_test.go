@@ -0,0 +1,109 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// failingStore is a rate limit store that always fails
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	return false, 0, errors.New("store unavailable")
+}
+
+// TestRateLimit tests the responses of requests over the limit of their client
+func TestRateLimit(t *testing.T) {
+	handler := middleware.RateLimit(2, time.Hour, nil)(handlerWriter("ok"))
+
+	tests := []struct {
+		name               string
+		remoteAddr         string
+		expectedStatus     int
+		expectedRetryAfter string
+	}{
+		{name: "first request", remoteAddr: "10.0.0.1:1234", expectedStatus: http.StatusOK},
+		{name: "second request from another port", remoteAddr: "10.0.0.1:5678", expectedStatus: http.StatusOK},
+		{name: "request over the limit", remoteAddr: "10.0.0.1:1234", expectedStatus: http.StatusTooManyRequests, expectedRetryAfter: "1800"},
+		{name: "request from another client", remoteAddr: "10.0.0.2:1234", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+
+			w := serve(handler, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Header().Get("Retry-After"), tt.expectedRetryAfter)
+		})
+	}
+}
+
+// TestRateLimitWithKeyFunc tests that clients are identified by the key function
+func TestRateLimitWithKeyFunc(t *testing.T) {
+	handler := middleware.RateLimit(1, time.Hour, func(r *http.Request) string {
+		return r.Header.Get("X-API-Key")
+	})(handlerWriter("ok"))
+
+	for _, key := range []string{"a", "b"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-API-Key", key)
+		assertCorrect(t, serve(handler, req).Code, http.StatusOK)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", "a")
+	assertCorrect(t, serve(handler, req).Code, http.StatusTooManyRequests)
+}
+
+// TestRateLimitIndependentBuckets tests that each middleware has its own buckets
+func TestRateLimitIndependentBuckets(t *testing.T) {
+	first := middleware.RateLimit(1, time.Hour, nil)(handlerWriter("ok"))
+	second := middleware.RateLimit(1, time.Hour, nil)(handlerWriter("ok"))
+
+	assertCorrect(t, serve(first, httptest.NewRequest(http.MethodGet, "/", nil)).Code, http.StatusOK)
+	assertCorrect(t, serve(second, httptest.NewRequest(http.MethodGet, "/", nil)).Code, http.StatusOK)
+	assertCorrect(t, serve(first, httptest.NewRequest(http.MethodGet, "/", nil)).Code, http.StatusTooManyRequests)
+}
+
+// TestRateLimitWithFailingStore tests that requests are let through when the store fails
+func TestRateLimitWithFailingStore(t *testing.T) {
+	handler := middleware.RateLimitWith(failingStore{}, 1, time.Hour, nil)(handlerWriter("ok"))
+
+	assertCorrect(t, serve(handler, httptest.NewRequest(http.MethodGet, "/", nil)).Code, http.StatusOK)
+}
+
+// TestMemoryRateLimitStoreRefill tests that tokens are refilled over the window
+func TestMemoryRateLimitStoreRefill(t *testing.T) {
+	store := middleware.NewMemoryRateLimitStore()
+	ctx := context.Background()
+
+	allowed, _, _ := store.Take(ctx, "key", 1, 20*time.Millisecond)
+	assertCorrect(t, allowed, true)
+	allowed, retryAfter, _ := store.Take(ctx, "key", 1, 20*time.Millisecond)
+	assertCorrect(t, allowed, false)
+
+	time.Sleep(retryAfter)
+	allowed, _, _ = store.Take(ctx, "key", 1, 20*time.Millisecond)
+	assertCorrect(t, allowed, true)
+}
+
+// TestRateLimitStoreFrom tests rate limits kept by a store shared by several middlewares
+func TestRateLimitStoreFrom(t *testing.T) {
+	store := middleware.NewMemoryStore()
+	first := middleware.RateLimitWith(middleware.RateLimitStoreFrom(store), 2, time.Hour, nil)(handlerWriter("ok"))
+	second := middleware.RateLimitWith(middleware.RateLimitStoreFrom(store), 2, time.Hour, nil)(handlerWriter("ok"))
+
+	assertCorrect(t, serve(first, httptest.NewRequest(http.MethodGet, "/", nil)).Code, http.StatusOK)
+	assertCorrect(t, serve(second, httptest.NewRequest(http.MethodGet, "/", nil)).Code, http.StatusOK)
+	w := serve(first, httptest.NewRequest(http.MethodGet, "/", nil))
+	assertCorrect(t, w.Code, http.StatusTooManyRequests)
+	assertCorrect(t, w.Header().Get("Retry-After"), "1800")
+}
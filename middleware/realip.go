@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RealIP returns a middleware that sets the request RemoteAddr to the client IP reported by
+// the True-Client-IP, X-Real-IP or X-Forwarded-For headers, in that order of preference.
+// Only use it behind a reverse proxy that sets these headers, otherwise clients can spoof their IP.
+func RealIP() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := realIP(r); ip != "" {
+				r.RemoteAddr = ip
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// realIP returns the client IP reported by the proxy headers of the request, if any is valid.
+func realIP(r *http.Request) string {
+	candidates := []string{
+		r.Header.Get("True-Client-IP"),
+		r.Header.Get("X-Real-IP"),
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		// The left-most address is the one of the original client.
+		candidates = append(candidates, strings.TrimSpace(strings.Split(forwarded, ",")[0]))
+	}
+
+	for _, candidate := range candidates {
+		if net.ParseIP(candidate) != nil {
+			return candidate
+		}
+	}
+	return ""
+}
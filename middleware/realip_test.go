@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestRealIP tests the remote address set from the proxy headers
+func TestRealIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{name: "no headers", headers: map[string]string{}, expected: "192.0.2.1:1234"},
+		{name: "x-real-ip", headers: map[string]string{"X-Real-IP": "203.0.113.5"}, expected: "203.0.113.5"},
+		{name: "x-forwarded-for", headers: map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.1"}, expected: "203.0.113.7"},
+		{name: "true-client-ip preferred", headers: map[string]string{"True-Client-IP": "2001:db8::1", "X-Real-IP": "203.0.113.5"}, expected: "2001:db8::1"},
+		{name: "invalid ip ignored", headers: map[string]string{"X-Real-IP": "not-an-ip"}, expected: "192.0.2.1:1234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := middleware.RealIP()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			serve(handler, req)
+
+			assertCorrect(t, got, tt.expected)
+		})
+	}
+}
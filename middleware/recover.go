@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// Recover returns a middleware that recovers from panics in the next handlers,
+// logs them with their stack trace and answers with a 500 Internal Server Error.
+// Panics with http.ErrAbortHandler are propagated, as they are used to abort the response on purpose.
+func Recover() func(http.Handler) http.Handler {
+	return RecoverWith(nil)
+}
+
+// RecoverWith does the same as [Recover], but the response is written by onPanic,
+// which receives the recovered value. If onPanic is nil, a plain text 500 response is written.
+func RecoverWith(onPanic func(w http.ResponseWriter, r *http.Request, v any)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
+				slog.ErrorContext(r.Context(), "panic recovered",
+					"error", err,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"request_id", RequestIDFrom(r.Context()),
+					"stack", string(debug.Stack()),
+				)
+				if onPanic != nil {
+					onPanic(w, r, err)
+					return
+				}
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+func assertCorrect(t testing.TB, got, want any) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+// handlerWriter creates a handler that writes a specific response
+func handlerWriter(response string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(response))
+	})
+}
+
+// serve serves a GET request to path with the handler and returns the recorded response
+func serve(handler http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+// TestRecover tests that panics are turned into 500 responses
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        http.Handler
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "no panic",
+			handler:        handlerWriter("ok"),
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		{
+			name: "panic",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic("boom")
+			}),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   "Internal Server Error\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(middleware.Recover()(tt.handler), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+// TestRecoverWithAbortHandler tests that http.ErrAbortHandler panics are propagated
+func TestRecoverWithAbortHandler(t *testing.T) {
+	handler := middleware.Recover()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if recover() != http.ErrAbortHandler {
+			t.Error("Expected http.ErrAbortHandler panic to be propagated, but it wasn't")
+		}
+	}()
+
+	serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+// TestRecoverWith tests that the response of recovered panics can be customized
+func TestRecoverWith(t *testing.T) {
+	var recovered any
+	onPanic := func(w http.ResponseWriter, r *http.Request, v any) {
+		recovered = v
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	handler := middleware.RecoverWith(onPanic)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	w := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assertCorrect(t, w.Code, http.StatusServiceUnavailable)
+	assertCorrect(t, recovered, "boom")
+}
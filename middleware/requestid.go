@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader is the header used to read the request ID from requests and to echo it in responses.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength is the maximum length of the request IDs accepted from clients.
+const maxRequestIDLength = 128
+
+// requestIDKey is the context key storing the request ID.
+type requestIDKey struct{}
+
+// RequestID returns a middleware that identifies each request with the ID of its X-Request-ID header,
+// set by clients or proxies to correlate requests across services, or with a newly generated unique ID
+// if the header is missing or invalid. The ID is stored in the request context and echoed in the
+// X-Request-ID response header, and it can be read by the next handlers with [RequestIDFrom].
+// IDs longer than 128 characters or with characters other than letters, digits, '-', '_', '.' and ':'
+// are considered invalid, so they can be safely logged.
+func RequestID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+		})
+	}
+}
+
+// RequestIDFrom returns the request ID stored in ctx by [RequestID], or an empty string if there is none.
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newRequestID returns a random 128 bits request ID encoded in hexadecimal.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID reports whether a request ID received from a client can be used.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
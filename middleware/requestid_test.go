@@ -0,0 +1,65 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestRequestID tests that request IDs are read or generated, stored in the context and echoed
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		expectedID  string
+		generatedID bool
+	}{
+		{name: "missing header", header: "", generatedID: true},
+		{name: "valid header", header: "abc-123_x.y:z", expectedID: "abc-123_x.y:z"},
+		{name: "invalid characters", header: "abc\n123", generatedID: true},
+		{name: "too long", header: strings.Repeat("a", 129), generatedID: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ctxID string
+			handler := middleware.RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = middleware.RequestIDFrom(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.header)
+			}
+			w := serve(handler, req)
+			gotID := w.Header().Get(middleware.RequestIDHeader)
+
+			assertCorrect(t, ctxID, gotID)
+			if tt.generatedID {
+				assertCorrect(t, len(gotID), 32)
+			} else {
+				assertCorrect(t, gotID, tt.expectedID)
+			}
+		})
+	}
+}
+
+// TestRequestIDUniqueness tests that a different ID is generated for each request
+func TestRequestIDUniqueness(t *testing.T) {
+	handler := middleware.RequestID()(handlerWriter("ok"))
+
+	first := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	second := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if first.Header().Get(middleware.RequestIDHeader) == second.Header().Get(middleware.RequestIDHeader) {
+		t.Error("Expected a different request ID for each request")
+	}
+}
+
+// TestRequestIDFromWithoutID tests the request ID of contexts without one
+func TestRequestIDFromWithoutID(t *testing.T) {
+	assertCorrect(t, middleware.RequestIDFrom(httptest.NewRequest(http.MethodGet, "/", nil).Context()), "")
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// DefaultContentSecurityPolicy is the policy set by [ContentSecurityPolicy] when none is given: resources
+// are only loaded from the origin of the page, and inline scripts and styles need the nonce of the request.
+const DefaultContentSecurityPolicy = "default-src 'self'; script-src 'self' {nonce}; style-src 'self' {nonce}; " +
+	"object-src 'none'; base-uri 'self'; frame-ancestors 'none'"
+
+// cspNonceKey is the context key storing the CSP nonce of the request.
+type cspNonceKey struct{}
+
+// SecureHeaders returns a middleware that sets response headers hardening browsers against
+// common attacks: content type sniffing, clickjacking, referrer leaks and cross origin window access.
+// Strict-Transport-Security is also set for requests served over TLS.
+func SecureHeaders() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("X-Frame-Options", "DENY")
+			h.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			h.Set("Cross-Origin-Opener-Policy", "same-origin")
+			if r.TLS != nil {
+				h.Set("Strict-Transport-Security", "max-age=63072000; includeSubDomains")
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ContentSecurityPolicy returns a middleware that generates a random nonce for each request and sets the
+// Content-Security-Policy header to policy, replacing its "{nonce}" placeholders with the nonce source
+// of the request, like 'nonce-Z3N1cmZhY2U...'. If policy is empty, [DefaultContentSecurityPolicy] is used.
+// Pages rendered by the handlers allow their inline scripts and styles by setting the nonce returned by
+// [CSPNonceFrom] in their nonce attribute, while scripts injected by attackers, without it, are blocked:
+//
+//	<script nonce="{{.Nonce}}">...</script>
+//
+// Use it with [SecureHeaders] on the routes serving pages.
+func ContentSecurityPolicy(policy string) func(http.Handler) http.Handler {
+	if policy == "" {
+		policy = DefaultContentSecurityPolicy
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce := newCSPNonce()
+			w.Header().Set("Content-Security-Policy", strings.ReplaceAll(policy, "{nonce}", "'nonce-"+nonce+"'"))
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), cspNonceKey{}, nonce)))
+		})
+	}
+}
+
+// CSPNonceFrom returns the CSP nonce of the request stored in ctx by [ContentSecurityPolicy],
+// or an empty string if there is none.
+func CSPNonceFrom(ctx context.Context) string {
+	nonce, _ := ctx.Value(cspNonceKey{}).(string)
+	return nonce
+}
+
+// newCSPNonce returns a random CSP nonce.
+func newCSPNonce() string {
+	b := make([]byte, 18)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
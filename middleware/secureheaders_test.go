@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestSecureHeaders tests the hardening headers set on responses
+func TestSecureHeaders(t *testing.T) {
+	tests := []struct {
+		name         string
+		tls          bool
+		expectedHSTS string
+	}{
+		{name: "plain http", tls: false, expectedHSTS: ""},
+		{name: "tls", tls: true, expectedHSTS: "max-age=63072000; includeSubDomains"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			w := serve(middleware.SecureHeaders()(handlerWriter("ok")), req)
+
+			assertCorrect(t, w.Header().Get("X-Content-Type-Options"), "nosniff")
+			assertCorrect(t, w.Header().Get("X-Frame-Options"), "DENY")
+			assertCorrect(t, w.Header().Get("Referrer-Policy"), "strict-origin-when-cross-origin")
+			assertCorrect(t, w.Header().Get("Cross-Origin-Opener-Policy"), "same-origin")
+			assertCorrect(t, w.Header().Get("Strict-Transport-Security"), tt.expectedHSTS)
+		})
+	}
+}
+
+// TestContentSecurityPolicy tests the policy header and the nonce of each request
+func TestContentSecurityPolicy(t *testing.T) {
+	var nonces []string
+	handler := middleware.ContentSecurityPolicy("script-src 'self' {nonce}")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, middleware.CSPNonceFrom(r.Context()))
+	}))
+
+	first := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	second := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assertCorrect(t, len(nonces[0]), 24)
+	assertCorrect(t, nonces[0] != nonces[1], true)
+	assertCorrect(t, first.Header().Get("Content-Security-Policy"), "script-src 'self' 'nonce-"+nonces[0]+"'")
+	assertCorrect(t, second.Header().Get("Content-Security-Policy"), "script-src 'self' 'nonce-"+nonces[1]+"'")
+	assertCorrect(t, middleware.CSPNonceFrom(httptest.NewRequest(http.MethodGet, "/", nil).Context()), "")
+
+	w := serve(middleware.ContentSecurityPolicy("")(handlerWriter("ok")), httptest.NewRequest(http.MethodGet, "/", nil))
+	assertCorrect(t, strings.HasPrefix(w.Header().Get("Content-Security-Policy"), "default-src 'self'; script-src 'self' 'nonce-"), true)
+}
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// SessionCookie is the name of the cookie storing the session ID.
+const SessionCookie = "session_id"
+
+// sessionKey is the context key storing the session.
+type sessionKey struct{}
+
+// Session stores the values of a client across requests. It is safe for concurrent use.
+type Session struct {
+	mu      sync.Mutex
+	values  map[string]any
+	expires time.Time
+	// keep is called by the first Set of a new session, to keep it and send its cookie.
+	keep func()
+	// dirty reports whether the values changed since the session was loaded, see [SessionsWith].
+	dirty bool
+}
+
+// Get returns the value stored under key, or nil if there is none.
+func (s *Session) Get(key string) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key]
+}
+
+// Set stores value under key. The first value of a new session must be set before the response is
+// written, as it sends the cookie of the session.
+func (s *Session) Set(key string, value any) {
+	s.mu.Lock()
+	s.values[key] = value
+	s.dirty = true
+	keep := s.keep
+	s.keep = nil
+	s.mu.Unlock()
+
+	if keep != nil {
+		keep()
+	}
+}
+
+// Delete removes the value stored under key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.dirty = true
+	}
+}
+
+// Sessions returns a middleware that keeps server side sessions in memory, identified by a cookie.
+// Sessions expire after being idle for ttl and are available to the next handlers with [SessionFrom].
+// The requests without a session get an empty one, only kept, and sent with a cookie, once a value is
+// set, so clients not using sessions, like bots and health probes, do not fill the memory.
+// Sessions are only kept by the returned middleware, so it should be attached once, at the root of the tree.
+func Sessions(ttl time.Duration) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	var lastSweep time.Time
+	sessions := map[string]*Session{}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+
+			mu.Lock()
+			if now.Sub(lastSweep) > time.Minute {
+				for id, session := range sessions {
+					if now.After(session.expires) {
+						delete(sessions, id)
+					}
+				}
+				lastSweep = now
+			}
+			var session *Session
+			if cookie, err := r.Cookie(SessionCookie); err == nil && sessions[cookie.Value] != nil && now.Before(sessions[cookie.Value].expires) {
+				session = sessions[cookie.Value]
+				session.expires = now.Add(ttl)
+				setSessionCookie(w, r, cookie.Value, session.expires)
+			} else {
+				session = &Session{values: map[string]any{}, expires: now.Add(ttl)}
+				session.keep = func() {
+					id := newRequestID()
+					mu.Lock()
+					sessions[id] = session
+					mu.Unlock()
+					setSessionCookie(w, r, id, session.expires)
+				}
+			}
+			mu.Unlock()
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionKey{}, session)))
+		})
+	}
+}
+
+// storedSession is a session encoded in the store of [SessionsWith].
+type storedSession struct {
+	Values  map[string]any
+	Expires time.Time
+}
+
+// SessionsWith does the same as [Sessions], but the sessions are kept by store, so they can be shared by
+// several instances of a service. Session values are encoded with encoding/gob, so values of types other
+// than the predeclared ones must be registered with gob.Register. Sessions are saved after the next
+// handlers return if their values changed, and requests are answered with a 500 Internal Server Error if
+// they cannot be loaded. New sessions are only saved once a value is set, and unchanged ones once less than
+// half of ttl is left before they expire, to extend it, so most requests do not write to the store.
+func SessionsWith(store Store, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+
+			var id string
+			session := &Session{values: map[string]any{}, expires: now.Add(ttl)}
+			if cookie, err := r.Cookie(SessionCookie); err == nil {
+				value, ok, err := store.Get(r.Context(), "session:"+cookie.Value)
+				if err != nil {
+					logSessionError(r, "session store failed", err)
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+				var stored storedSession
+				if ok && gob.NewDecoder(bytes.NewReader(value)).Decode(&stored) == nil && stored.Values != nil {
+					id, session.values, session.expires = cookie.Value, stored.Values, stored.Expires
+				}
+			}
+
+			refresh := false
+			if id == "" {
+				session.keep = func() {
+					id = newRequestID()
+					setSessionCookie(w, r, id, session.expires)
+				}
+			} else if session.expires.Sub(now) < ttl/2 {
+				refresh, session.expires = true, now.Add(ttl)
+				setSessionCookie(w, r, id, session.expires)
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionKey{}, session)))
+
+			session.mu.Lock()
+			defer session.mu.Unlock()
+			if id == "" || !session.dirty && !refresh {
+				return
+			}
+			var buf bytes.Buffer
+			err := gob.NewEncoder(&buf).Encode(storedSession{Values: session.values, Expires: session.expires})
+			if err == nil {
+				err = store.Set(r.Context(), "session:"+id, buf.Bytes(), time.Until(session.expires))
+			}
+			if err != nil {
+				logSessionError(r, "session not saved", err)
+			}
+		})
+	}
+}
+
+// setSessionCookie sets the cookie storing the session ID in the response.
+func setSessionCookie(w http.ResponseWriter, r *http.Request, id string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    id,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// logSessionError logs an error of the session store for the request.
+func logSessionError(r *http.Request, msg string, err error) {
+	slog.ErrorContext(r.Context(), msg,
+		"error", err,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"request_id", RequestIDFrom(r.Context()),
+	)
+}
+
+// SessionFrom returns the session stored in ctx by [Sessions] or [SessionsWith], or nil if there is none.
+func SessionFrom(ctx context.Context) *Session {
+	session, _ := ctx.Value(sessionKey{}).(*Session)
+	return session
+}
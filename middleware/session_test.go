@@ -0,0 +1,143 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/carlos-el/simplerouter/middleware"
+	"github.com/carlos-el/simplerouter/middleware/storetest"
+)
+
+// counterHandler increments a counter stored in the session and writes its value
+func counterHandler(w http.ResponseWriter, r *http.Request) {
+	session := middleware.SessionFrom(r.Context())
+	count, _ := session.Get("count").(int)
+	session.Set("count", count+1)
+	w.Write([]byte{byte('0' + count + 1)})
+}
+
+// TestSessions tests that session values are kept across requests of the same client
+func TestSessions(t *testing.T) {
+	handler := middleware.Sessions(time.Hour)(http.HandlerFunc(counterHandler))
+
+	first := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	assertCorrect(t, first.Body.String(), "1")
+	cookie := first.Result().Cookies()[0]
+	assertCorrect(t, cookie.Name, middleware.SessionCookie)
+	assertCorrect(t, cookie.HttpOnly, true)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+	second := serve(handler, req)
+	assertCorrect(t, second.Body.String(), "2")
+
+	other := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	assertCorrect(t, other.Body.String(), "1")
+}
+
+// TestSessionsWithoutValues tests that sessions without values are not kept nor sent to the clients
+func TestSessionsWithoutValues(t *testing.T) {
+	handler := middleware.Sessions(time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if middleware.SessionFrom(r.Context()).Get("count") != nil {
+			t.Errorf("new session has values")
+		}
+	}))
+
+	w := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	assertCorrect(t, len(w.Result().Cookies()), 0)
+}
+
+// TestSessionsExpiration tests that idle sessions expire
+func TestSessionsExpiration(t *testing.T) {
+	handler := middleware.Sessions(time.Millisecond)(http.HandlerFunc(counterHandler))
+
+	first := serve(handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	time.Sleep(5 * time.Millisecond)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(first.Result().Cookies()[0])
+	second := serve(handler, req)
+	assertCorrect(t, second.Body.String(), "1")
+}
+
+// TestSessionsWith tests that session values are kept across requests served with a shared store
+func TestSessionsWith(t *testing.T) {
+	store := middleware.NewMemoryStore()
+	first := middleware.SessionsWith(store, time.Hour)(http.HandlerFunc(counterHandler))
+	second := middleware.SessionsWith(store, time.Hour)(http.HandlerFunc(counterHandler))
+
+	w := serve(first, httptest.NewRequest(http.MethodGet, "/", nil))
+	assertCorrect(t, w.Body.String(), "1")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+	assertCorrect(t, serve(second, req).Body.String(), "2")
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: middleware.SessionCookie, Value: "unknown"})
+	assertCorrect(t, serve(second, req).Body.String(), "1")
+}
+
+// countingStore is a memory store counting the values set
+type countingStore struct {
+	*middleware.MemoryStore
+	sets int
+}
+
+// Set counts the value before storing it
+func (s *countingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.sets++
+	return s.MemoryStore.Set(ctx, key, value, ttl)
+}
+
+// TestSessionsWithWrites tests that only the new sessions with values, the changed sessions and the
+// sessions about to expire are saved
+func TestSessionsWithWrites(t *testing.T) {
+	store := &countingStore{MemoryStore: middleware.NewMemoryStore()}
+	ttl := 200 * time.Millisecond
+	counter := middleware.SessionsWith(store, ttl)(http.HandlerFunc(counterHandler))
+	reader := middleware.SessionsWith(store, ttl)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count, _ := middleware.SessionFrom(r.Context()).Get("count").(int)
+		w.Write([]byte{byte('0' + count)})
+	}))
+	withCookie := func(cookie *http.Cookie) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookie)
+		return req
+	}
+
+	w := serve(reader, httptest.NewRequest(http.MethodGet, "/", nil))
+	assertCorrect(t, len(w.Result().Cookies()), 0)
+	assertCorrect(t, store.sets, 0)
+
+	w = serve(counter, httptest.NewRequest(http.MethodGet, "/", nil))
+	cookie := w.Result().Cookies()[0]
+	assertCorrect(t, store.sets, 1)
+
+	w = serve(reader, withCookie(cookie))
+	assertCorrect(t, w.Body.String(), "1")
+	assertCorrect(t, len(w.Result().Cookies()), 0)
+	assertCorrect(t, store.sets, 1)
+
+	time.Sleep(ttl * 3 / 4)
+	w = serve(reader, withCookie(cookie))
+	assertCorrect(t, len(w.Result().Cookies()), 1)
+	assertCorrect(t, store.sets, 2)
+
+	time.Sleep(ttl * 3 / 4)
+	assertCorrect(t, serve(counter, withCookie(cookie)).Body.String(), "2")
+	assertCorrect(t, store.sets, 3)
+}
+
+// TestSessionsWithFailingStore tests that requests are not served without their session
+func TestSessionsWithFailingStore(t *testing.T) {
+	handler := middleware.SessionsWith(storetest.FailingStore{Err: errors.New("store unavailable")}, time.Hour)(http.HandlerFunc(counterHandler))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: middleware.SessionCookie, Value: "id"})
+	assertCorrect(t, serve(handler, req).Code, http.StatusInternalServerError)
+}
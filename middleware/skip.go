@@ -0,0 +1,25 @@
+package middleware
+
+import "net/http"
+
+// Skip returns a middleware that applies mw to every request except the ones for which skip returns true,
+// which go straight to the next handler, like health checks that should not be logged:
+//
+//	middleware.Skip(middleware.Logger(nil), func(r *http.Request) bool { return r.URL.Path == "/healthz" })
+//
+// It panics if mw or skip is nil.
+func Skip(mw func(http.Handler) http.Handler, skip func(r *http.Request) bool) func(http.Handler) http.Handler {
+	if mw == nil || skip == nil {
+		panic("mw and skip parameters cannot be nil")
+	}
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
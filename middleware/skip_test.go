@@ -0,0 +1,22 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestSkip tests that skipped requests bypass the middleware
+func TestSkip(t *testing.T) {
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "denied", http.StatusForbidden)
+		})
+	}
+	handler := middleware.Skip(deny, func(r *http.Request) bool { return r.URL.Path == "/healthz" })(handlerWriter("ok"))
+
+	assertCorrect(t, serve(handler, httptest.NewRequest(http.MethodGet, "/healthz", nil)).Code, http.StatusOK)
+	assertCorrect(t, serve(handler, httptest.NewRequest(http.MethodGet, "/users", nil)).Code, http.StatusForbidden)
+}
@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps the state of the stateful middlewares, like rate limit buckets and sessions, so it can be
+// kept out of the process and shared by several instances of a service. Implementing it once for a
+// backend (Redis or Memcached, for example) makes the backend available to every middleware using it,
+// see [RateLimitStoreFrom], [SessionsWith] and [Cache]. Implementations must be safe for concurrent use.
+// The storetest package checks that implementations behave as expected.
+type Store interface {
+	// Get returns the value stored under key, and whether there is one that has not expired.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set stores value under key, replacing the current one, until ttl elapses. Values without ttl do not expire.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the value stored under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// memoryEntry is a value of a [MemoryStore].
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// MemoryStore is a [Store] keeping the values in memory. Expired values are removed periodically.
+// It is safe for concurrent use.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// NewMemoryStore returns an empty [MemoryStore].
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]memoryEntry{}}
+}
+
+// Get returns a copy of the value stored under key, and whether there is one that has not expired.
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || entry.expired(time.Now()) {
+		return nil, false, nil
+	}
+	return append([]byte{}, entry.value...), true, nil
+}
+
+// Set stores a copy of value under key until ttl elapses.
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	now := time.Now()
+	entry := memoryEntry{value: append([]byte{}, value...)}
+	if ttl > 0 {
+		entry.expires = now.Add(ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, e := range s.entries {
+			if e.expired(now) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.entries[key] = entry
+	return nil
+}
+
+// Delete removes the value stored under key.
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// expired reports whether the entry has expired at now.
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// LRUStore is a [Store] keeping up to a maximum number of values in memory, evicting the least recently
+// used one to make room for new values, so it suits caches. It is safe for concurrent use.
+type LRUStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	// order holds the keys of the entries, from the most recently used to the least.
+	order *list.List
+}
+
+// lruEntry is a value of an [LRUStore].
+type lruEntry struct {
+	key string
+	memoryEntry
+}
+
+// NewLRUStore returns an empty [LRUStore] keeping up to maxEntries values.
+// It panics if maxEntries is not greater than zero.
+func NewLRUStore(maxEntries int) *LRUStore {
+	if maxEntries <= 0 {
+		panic("maxEntries parameter must be greater than zero")
+	}
+	return &LRUStore{maxEntries: maxEntries, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+// Get returns a copy of the value stored under key, and whether there is one that has not expired,
+// marking it as the most recently used.
+func (s *LRUStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*lruEntry)
+	if entry.expired(time.Now()) {
+		s.remove(element)
+		return nil, false, nil
+	}
+	s.order.MoveToFront(element)
+	return append([]byte{}, entry.value...), true, nil
+}
+
+// Set stores a copy of value under key until ttl elapses, evicting the least recently used value if full.
+func (s *LRUStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := &lruEntry{key: key, memoryEntry: memoryEntry{value: append([]byte{}, value...)}}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		element.Value = entry
+		s.order.MoveToFront(element)
+		return nil
+	}
+	s.entries[key] = s.order.PushFront(entry)
+	if s.order.Len() > s.maxEntries {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+// Delete removes the value stored under key.
+func (s *LRUStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[key]; ok {
+		s.remove(element)
+	}
+	return nil
+}
+
+// Len returns the number of values stored, including the expired ones not evicted yet.
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// remove removes the element from the store.
+func (s *LRUStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*lruEntry).key)
+}
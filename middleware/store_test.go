@@ -0,0 +1,36 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/carlos-el/simplerouter/middleware"
+	"github.com/carlos-el/simplerouter/middleware/storetest"
+)
+
+// TestMemoryStore tests the memory store with the store conformance checks
+func TestMemoryStore(t *testing.T) {
+	storetest.Run(t, func() middleware.Store { return middleware.NewMemoryStore() })
+}
+
+// TestLRUStore tests the LRU store with the store conformance checks, and its evictions
+func TestLRUStore(t *testing.T) {
+	storetest.Run(t, func() middleware.Store { return middleware.NewLRUStore(10) })
+
+	ctx := context.Background()
+	store := middleware.NewLRUStore(2)
+	store.Set(ctx, "a", []byte("a"), 0)
+	store.Set(ctx, "b", []byte("b"), 0)
+	store.Get(ctx, "a")
+	store.Set(ctx, "c", []byte("c"), 0)
+
+	for key, expected := range map[string]bool{"a": true, "b": false, "c": true} {
+		_, ok, _ := store.Get(ctx, key)
+		if ok != expected {
+			t.Errorf("Get(%q) found = %v, want %v", key, ok, expected)
+		}
+	}
+	if store.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", store.Len())
+	}
+}
@@ -0,0 +1,114 @@
+// Package storetest checks implementations of the [middleware.Store] interface, and provides stores
+// to test how the stateful middlewares behave when their store fails.
+package storetest
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// Run checks that the stores returned by newStore behave as expected by the middlewares using them:
+// values are returned as stored, replaced, deleted and expired after their ttl, and stored values are
+// not changed by later changes of the slices passed to or returned by the store. newStore is called
+// for every check, and must return an empty store.
+func Run(t *testing.T, newStore func() middleware.Store) {
+	ctx := context.Background()
+
+	t.Run("missing key", func(t *testing.T) {
+		_, ok, err := newStore().Get(ctx, "missing")
+		check(t, err)
+		if ok {
+			t.Errorf("Get() of a missing key found a value")
+		}
+	})
+
+	t.Run("set and get", func(t *testing.T) {
+		store := newStore()
+		check(t, store.Set(ctx, "key", []byte("value"), 0))
+		assertValue(t, store, "key", "value")
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		store := newStore()
+		check(t, store.Set(ctx, "key", []byte("old"), 0))
+		check(t, store.Set(ctx, "key", []byte("new"), time.Hour))
+		assertValue(t, store, "key", "new")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		store := newStore()
+		check(t, store.Set(ctx, "key", []byte("value"), 0))
+		check(t, store.Delete(ctx, "key"))
+		check(t, store.Delete(ctx, "missing"))
+		if _, ok, err := store.Get(ctx, "key"); err != nil || ok {
+			t.Errorf("Get() of a deleted key = %v, %v, want not found", ok, err)
+		}
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		store := newStore()
+		check(t, store.Set(ctx, "short", []byte("value"), 50*time.Millisecond))
+		check(t, store.Set(ctx, "long", []byte("value"), time.Hour))
+		time.Sleep(100 * time.Millisecond)
+		if _, ok, err := store.Get(ctx, "short"); err != nil || ok {
+			t.Errorf("Get() of an expired key = %v, %v, want not found", ok, err)
+		}
+		assertValue(t, store, "long", "value")
+	})
+
+	t.Run("copies", func(t *testing.T) {
+		store := newStore()
+		value := []byte("value")
+		check(t, store.Set(ctx, "key", value, 0))
+		value[0] = 'X'
+		got, _, err := store.Get(ctx, "key")
+		check(t, err)
+		if len(got) > 0 {
+			got[0] = 'Y'
+		}
+		assertValue(t, store, "key", "value")
+	})
+}
+
+// check fails the test if err is not nil.
+func check(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected store error: %v", err)
+	}
+}
+
+// assertValue fails the test if the value of key in store is not want.
+func assertValue(t *testing.T, store middleware.Store, key, want string) {
+	t.Helper()
+	got, ok, err := store.Get(context.Background(), key)
+	check(t, err)
+	if !ok || !bytes.Equal(got, []byte(want)) {
+		t.Errorf("Get(%q) = %q, %v, want %q, true", key, got, ok, want)
+	}
+}
+
+// FailingStore is a [middleware.Store] whose operations always return Err, to test how middlewares
+// behave when their store is unavailable.
+type FailingStore struct {
+	Err error
+}
+
+// Get returns the error of the store.
+func (s FailingStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, s.Err
+}
+
+// Set returns the error of the store.
+func (s FailingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.Err
+}
+
+// Delete returns the error of the store.
+func (s FailingStore) Delete(ctx context.Context, key string) error {
+	return s.Err
+}
@@ -0,0 +1,67 @@
+package simplerouter
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// muxPathWildcard is the name of the wildcard matching the paths served by a mounted http.ServeMux.
+const muxPathWildcard = "muxpath"
+
+// wrappedPathWildcard is the name of the wildcard matching the paths served by a wrapped handler.
+const wrappedPathWildcard = "wrappedpath"
+
+// MountMux adds a child route serving the requests under prefix with mux, an http.ServeMux built
+// elsewhere, so existing muxes can be moved into the tree gradually. The prefix is stripped from the
+// requests before mux matches them, so its patterns do not change, and the middlewares and metadata
+// of the route and its parents apply to them as to any other child route. Once mounted, requests to
+// the prefix itself are redirected to the prefix followed by a slash.
+func (r *Route) MountMux(prefix string, mux *http.ServeMux) *Route {
+	if mux == nil {
+		panic("mux parameter cannot be nil")
+	}
+	return r.Add(NewRoute(prefix + "/{" + muxPathWildcard + "...}").Add(All(wildcardHandler(mux, muxPathWildcard))))
+}
+
+// Wrap returns a Route serving every request under the path of its parent with h, like a chi or
+// gorilla/mux router built elsewhere, so existing routers can be moved into the tree gradually:
+//
+//	router.Add(simplerouter.NewRoute("/legacy").Use(auth).Add(simplerouter.Wrap(chiRouter)))
+//
+// The path of the parent is stripped from the requests before h matches them, as chi sub-routers and
+// http.StripPrefix expect, and the middlewares and metadata of the parent routes apply to them as to any
+// other route. Requests that h does not match get its own 404 Not Found answer. Once mounted, requests to
+// the parent path itself are redirected to the path followed by a slash.
+// Wrapped at the root of the tree, h serves every request not matched by the other routes, which is
+// handy to migrate a router route by route, leaving the rest to fall through to it.
+// It panics if h is nil, use [WrapKeepPath] for routers matching the full paths.
+func Wrap(h http.Handler) *Route {
+	if h == nil {
+		panic("h parameter cannot be nil")
+	}
+	return NewRoute("/{" + wrappedPathWildcard + "...}").Add(All(wildcardHandler(h, wrappedPathWildcard)))
+}
+
+// WrapKeepPath does the same as [Wrap], without stripping the path of the parent from the requests,
+// for routers matching the full paths, like gorilla/mux sub-routers created with PathPrefix.
+// It panics if h is nil.
+func WrapKeepPath(h http.Handler) *Route {
+	if h == nil {
+		panic("h parameter cannot be nil")
+	}
+	return NewRoute("/{" + wrappedPathWildcard + "...}").Add(All(h.ServeHTTP))
+}
+
+// wildcardHandler returns a handler serving the requests with h, with their path replaced
+// by the part matched by the given multi-segment wildcard.
+func wildcardHandler(h http.Handler, wildcard string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path = "/" + r.PathValue(wildcard)
+		r2.URL.RawPath = ""
+		h.ServeHTTP(w, r2)
+	}
+}
@@ -0,0 +1,98 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestMountMux tests the requests served by a mounted http.ServeMux
+func TestMountMux(t *testing.T) {
+	legacy := http.NewServeMux()
+	legacy.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("user " + req.PathValue("id") + " at " + req.URL.Path))
+	})
+	legacy.HandleFunc("GET /{$}", handlerWriter("legacy index"))
+
+	tracker := []string{}
+	mux := r.NewRoute("/api").Use(middlewareTracker("m1", &tracker)).Add(
+		r.NewRoute("/users").Add(r.Get(handlerWriter("users"))),
+	).MountMux("/legacy", legacy).Mount()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "route of the tree", method: http.MethodGet, path: "/api/users", expectedStatus: http.StatusOK, expectedBody: "users"},
+		{name: "pattern of the mux", method: http.MethodGet, path: "/api/legacy/users/42", expectedStatus: http.StatusOK, expectedBody: "user 42 at /users/42"},
+		{name: "root of the mux", method: http.MethodGet, path: "/api/legacy/", expectedStatus: http.StatusOK, expectedBody: "legacy index"},
+		{name: "prefix without slash", method: http.MethodGet, path: "/api/legacy", expectedStatus: http.StatusTemporaryRedirect, expectedBody: ""},
+		{name: "method not allowed by the mux", method: http.MethodPost, path: "/api/legacy/users/42", expectedStatus: http.StatusMethodNotAllowed, expectedBody: "Method Not Allowed\n"},
+		{name: "path unknown to the mux", method: http.MethodGet, path: "/api/legacy/orders", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker = tracker[:0]
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			if tt.expectedStatus != http.StatusTemporaryRedirect {
+				assertCorrect(t, w.Body.String(), tt.expectedBody)
+				assertCorrect(t, len(tracker), 1)
+			}
+		})
+	}
+}
+
+// TestWrap tests the paths of the requests served by wrapped handlers, and the requests falling through to them
+func TestWrap(t *testing.T) {
+	echoPath := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("legacy " + req.URL.Path))
+	})
+
+	tracker := []string{}
+	mux := r.NewRoute("").Add(
+		r.NewRoute("/api").Use(middlewareTracker("m1", &tracker)).Add(
+			r.NewRoute("/users").Add(r.Get(handlerWriter("users"))),
+			r.NewRoute("/stripped").Add(r.Wrap(echoPath)),
+			r.NewRoute("/kept").Add(r.WrapKeepPath(echoPath)),
+		),
+		r.Wrap(echoPath),
+	).Mount(r.WithValidation())
+
+	tests := []struct {
+		name            string
+		path            string
+		expectedStatus  int
+		expectedBody    string
+		expectedTracker int
+	}{
+		{name: "route of the tree", path: "/api/users", expectedStatus: http.StatusOK, expectedBody: "users", expectedTracker: 1},
+		{name: "stripped path", path: "/api/stripped/users/42", expectedStatus: http.StatusOK, expectedBody: "legacy /users/42", expectedTracker: 1},
+		{name: "stripped root", path: "/api/stripped/", expectedStatus: http.StatusOK, expectedBody: "legacy /", expectedTracker: 1},
+		{name: "kept path", path: "/api/kept/users/42", expectedStatus: http.StatusOK, expectedBody: "legacy /api/kept/users/42", expectedTracker: 1},
+		{name: "prefix without slash", path: "/api/kept", expectedStatus: http.StatusTemporaryRedirect, expectedBody: ""},
+		{name: "fall through", path: "/api/orders", expectedStatus: http.StatusOK, expectedBody: "legacy /api/orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker = []string{}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, len(tracker), tt.expectedTracker)
+			if tt.expectedBody != "" {
+				assertCorrect(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
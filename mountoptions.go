@@ -0,0 +1,80 @@
+package simplerouter
+
+import (
+	"net/http"
+	"strings"
+)
+
+// MountOption configures how a route tree is mounted by [Route.Mount].
+type MountOption func(*mountConfig)
+
+// mountConfig holds the options of a mount.
+type mountConfig struct {
+	walkFn   WalkFn
+	validate bool
+	notFound http.Handler
+	freeze   bool
+	basePath string
+}
+
+// WithWalk calls walkFn for each route and subroute as they are mounted,
+// allowing for custom debugging or logging of the routes.
+// Routes are walked depth first: each route before its child routes, and child routes in the order
+// they were added. Use [Route.SortByPath] first to get the same order regardless of the Add calls.
+func WithWalk(walkFn WalkFn) MountOption {
+	if walkFn == nil {
+		panic("walkFn parameter cannot be nil")
+	}
+	return func(c *mountConfig) { c.walkFn = walkFn }
+}
+
+// WithValidation validates the tree with [Route.Validate] before mounting it, panicking with all
+// of its problems at once instead of the first pattern rejected by http.ServeMux.
+func WithValidation() MountOption {
+	return func(c *mountConfig) { c.validate = true }
+}
+
+// WithNotFound answers the requests not matching any route of the tree with handler, registered on the
+// "/" catch-all pattern, instead of the 404 Not Found of http.ServeMux. Requests to paths registered
+// for other methods are answered by handler too. Mounting panics if the tree registers the "/" pattern.
+func WithNotFound(handler http.Handler) MountOption {
+	if handler == nil {
+		panic("handler parameter cannot be nil")
+	}
+	return func(c *mountConfig) { c.notFound = handler }
+}
+
+// WithBasePath prefixes the patterns of the tree with basePath, like "/myapp", so a tree written for the
+// root of a host is served as is behind a reverse proxy forwarding a path prefix without stripping it.
+// The names of the routes, their canonical versions and the patterns reported by [PatternFrom] include
+// the base path, and handlers build URLs including it with [URLFrom]. The handler given to [WithNotFound]
+// still answers every unmatched request. It panics if basePath does not start with a slash or ends with one.
+func WithBasePath(basePath string) MountOption {
+	if !strings.HasPrefix(basePath, "/") || strings.HasSuffix(basePath, "/") {
+		panic("basePath parameter must start with a slash and cannot end with one")
+	}
+	return func(c *mountConfig) { c.basePath = basePath }
+}
+
+// WithFreeze makes the route tree immutable once mounted: adding middlewares or routes to any of its
+// routes, or removing and replacing them, panics, or returns [ErrFrozenRoute] in the error-based API,
+// as changes made after mounting the tree do not affect the mounted http.ServeMux and would be silently
+// ignored otherwise. Copies made with [Route.Clone] are not frozen.
+func WithFreeze() MountOption {
+	return func(c *mountConfig) { c.freeze = true }
+}
+
+// freeze marks the route and its child routes as frozen.
+func (r *Route) freeze() {
+	r.frozen = true
+	for _, route := range r.Routes {
+		route.freeze()
+	}
+}
+
+// checkFrozen panics if the route is frozen, see [WithFreeze].
+func (r *Route) checkFrozen() {
+	if r.frozen {
+		panic(ErrFrozenRoute.Error())
+	}
+}
@@ -0,0 +1,167 @@
+package simplerouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestMountOptions tests combined mount options
+func TestMountOptions(t *testing.T) {
+	walked := []string{}
+	mux := r.NewRoute("/users").Add(r.Get(listUsers)).Mount(
+		r.WithWalk(func(route *r.Route, path string, middlewares []r.Middleware) {
+			walked = append(walked, path+route.Path)
+		}),
+		r.WithValidation(),
+		r.WithNotFound(handlerWriter("custom not found")),
+	)
+
+	assertCorrect(t, strings.Join(walked, ","), "/users,/users")
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "registered route", method: http.MethodGet, path: "/users", expectedStatus: http.StatusOK},
+		{name: "unknown path", method: http.MethodGet, path: "/orders", expectedStatus: http.StatusOK, expectedBody: "custom not found"},
+		{name: "other method", method: http.MethodPost, path: "/users", expectedStatus: http.StatusOK, expectedBody: "custom not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			if tt.expectedBody != "" {
+				assertCorrect(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+// TestMountWithValidation tests that mounting invalid trees with validation panics with all their problems
+func TestMountWithValidation(t *testing.T) {
+	defer func() {
+		v := recover()
+		want := "GET /api/{bad: invalid_pattern"
+		if !strings.Contains(fmt.Sprint(v), want) || !strings.Contains(fmt.Sprint(v), "undefined_name") {
+			t.Errorf("Mount() panicked with %v, want it to contain %q and undefined_name", v, want)
+		}
+	}()
+
+	r.NewRoute("/api").Add(
+		r.NewRoute("/{bad").Add(r.Get(listUsers)),
+		r.NewRoute("/docs").Canonical("missing").Add(r.Get(listUsers)),
+	).Mount(r.WithValidation())
+}
+
+// TestWithFreeze tests that frozen trees cannot be modified after mounting them
+func TestWithFreeze(t *testing.T) {
+	users := r.NewRoute("/users").Add(r.Get(listUsers))
+	route := r.NewRoute("/api").Add(users)
+	route.Mount(r.WithFreeze())
+
+	assertCorrect(t, route.TryAdd(r.NewRoute("/orders")), r.ErrFrozenRoute)
+	assertCorrect(t, users.TryUse(authMiddleware), r.ErrFrozenRoute)
+	assertCorrect(t, route.Merge(r.NewRoute("/orders")), r.ErrFrozenRoute)
+	assertCorrect(t, len(users.Middlewares), 0)
+
+	for name, modify := range map[string]func(){
+		"use":     func() { users.Use(authMiddleware) },
+		"add":     func() { route.Add(r.NewRoute("/orders")) },
+		"use pre": func() { users.UsePre(authMiddleware) },
+		"remove":  func() { route.Remove("/api/users", http.MethodGet) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Modifying a frozen route did not panic")
+				}
+			}()
+			modify()
+		})
+	}
+
+	clone := route.Clone()
+	assertCorrect(t, clone.TryAdd(r.NewRoute("/orders")), nil)
+}
+
+// TestWithBasePath tests the patterns, preflights and URLs of trees mounted under a base path
+func TestWithBasePath(t *testing.T) {
+	writeURL := func(w http.ResponseWriter, req *http.Request) {
+		url, err := r.URLFrom(req.Context(), "user", "id", "42")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(r.PatternFrom(req.Context()) + " " + url))
+	}
+	tree := r.NewRoute("/api").Add(
+		r.NewRoute("/users/{id}").Name("user").CORS(middleware.CORSOptions{AllowedOrigins: []string{"*"}}).Add(r.Get(writeURL)),
+		r.NewRoute("/legacy").Canonical("/api/users/1").Add(r.Get(handlerWriter("legacy"))),
+	)
+	mux := tree.Mount(r.WithBasePath("/myapp"))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+		expectedHeader string
+	}{
+		{name: "prefixed", method: http.MethodGet, path: "/myapp/api/users/7", expectedStatus: http.StatusOK, expectedBody: "/myapp/api/users/{id} /myapp/api/users/42"},
+		{name: "not prefixed", method: http.MethodGet, path: "/api/users/7", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+		{name: "preflight", method: http.MethodOptions, path: "/myapp/api/users/7", expectedStatus: http.StatusNoContent},
+		{name: "canonical", method: http.MethodGet, path: "/myapp/api/legacy", expectedStatus: http.StatusOK, expectedBody: "legacy", expectedHeader: `</myapp/api/users/1>; rel="canonical"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Origin", "https://example.com")
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, w.Header().Get("Link"), tt.expectedHeader)
+		})
+	}
+
+	// The same tree keeps working at the root.
+	w := httptest.NewRecorder()
+	tree.Mount().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/users/7", nil))
+	assertCorrect(t, w.Body.String(), "/api/users/{id} /api/users/42")
+
+	seeds := tree.Matcher(r.WithBasePath("/myapp")).Seeds()
+	assertCorrect(t, seeds[0].Path, "/myapp/api/users/x")
+
+	_, err := r.URLFrom(httptest.NewRequest(http.MethodGet, "/", nil).Context(), "user")
+	assertCorrect(t, err != nil, true)
+}
+
+// TestWithBasePathInvalid tests that base paths must start with a slash and not end with one
+func TestWithBasePathInvalid(t *testing.T) {
+	for _, basePath := range []string{"", "/", "myapp", "/myapp/"} {
+		t.Run(basePath, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WithBasePath(%q) did not panic", basePath)
+				}
+			}()
+			r.WithBasePath(basePath)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package simplerouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Name sets the name identifying the route, so its URL can be built with [Route.URL]
+// without hardcoding paths. Names must be unique in a route tree.
+func (r *Route) Name(name string) *Route {
+	if name == "" {
+		panic("name parameter cannot be empty")
+	}
+	r.Metadata.Name = name
+	return r
+}
+
+// URL builds the path of the route named name in the route tree, replacing its wildcards
+// with the values provided in params as key-value pairs:
+//
+//	router.URL("user", "id", "42") // "/api/users/42"
+//
+// It returns an error if the name is not defined or if a wildcard value is missing.
+func (r *Route) URL(name string, params ...string) (string, error) {
+	return namedURL(r.namedPaths(""), name, params)
+}
+
+// URLFrom builds the path of the route named name in the tree serving the request as [Route.URL] does,
+// including the base path the tree was mounted with, see [WithBasePath], so handlers link to the routes
+// of the tree wherever it is served:
+//
+//	url, err := simplerouter.URLFrom(r.Context(), "user", "id", "42") // "/myapp/api/users/42"
+//
+// It returns an error if the request is not served by a mounted route tree.
+func URLFrom(ctx context.Context, name string, params ...string) (string, error) {
+	info := RouteInfoFrom(ctx)
+	if info == nil {
+		return "", errors.New("request is not served by a mounted route tree")
+	}
+	return namedURL(info.paths, name, params)
+}
+
+// namedURL builds the path of the route named name in paths, replacing its wildcards with the values of params.
+func namedURL(paths map[string]string, name string, params []string) (string, error) {
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("params must be key-value pairs, got %d values", len(params))
+	}
+
+	path, ok := paths[name]
+	if !ok {
+		return "", fmt.Errorf("route name %q is not defined", name)
+	}
+
+	values := map[string]string{}
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+
+	return buildPath(path, func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	})
+}
+
+// namedPaths recursively collects the full paths of the named routes of the tree.
+func (r *Route) namedPaths(path string) map[string]string {
+	chainedPath := path + r.Path
+
+	paths := map[string]string{}
+	if r.Metadata.Name != "" {
+		paths[r.Metadata.Name] = chainedPath
+	}
+
+	for _, route := range r.Routes {
+		for name, p := range route.namedPaths(chainedPath) {
+			paths[name] = p
+		}
+	}
+
+	return paths
+}
+
+// pathNames adds the names of the routes without handlers to names, keyed by their full paths.
+// The first route of the tree wins for routes sharing a path.
+func (r *Route) pathNames(path string, names map[string]string) {
+	chainedPath := path + r.Path
+	if _, found := names[chainedPath]; !found && r.Handler == nil && r.Metadata.Name != "" {
+		names[chainedPath] = r.Metadata.Name
+	}
+	for _, route := range r.Routes {
+		route.pathNames(chainedPath, names)
+	}
+}
+
+// buildPath replaces the wildcards of an http.ServeMux path with the values returned by lookup.
+// Values are escaped, keeping the slashes of the values of multi-segment wildcards.
+func buildPath(path string, lookup func(key string) (string, bool)) (string, error) {
+	path = strings.TrimSuffix(path, "{$}")
+
+	var err error
+	built := wildcardRegexp.ReplaceAllStringFunc(path, func(wildcard string) string {
+		match := wildcardRegexp.FindStringSubmatch(wildcard)
+		value, ok := lookup(match[1])
+		if !ok {
+			err = fmt.Errorf("missing value for wildcard %q", match[1])
+			return wildcard
+		}
+
+		if match[2] == "" {
+			return url.PathEscape(value)
+		}
+		segments := strings.Split(value, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		return strings.Join(segments, "/")
+	})
+
+	return built, err
+}
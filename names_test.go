@@ -0,0 +1,56 @@
+package simplerouter_test
+
+import (
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestURL tests the URLs built for named routes
+func TestURL(t *testing.T) {
+	route := r.NewRoute("/api").Add(
+		r.NewRoute("/users").Name("users").Add(r.Get(handlerWriter("users"))),
+		r.NewRoute("/users/{id}").Name("user").Add(r.Get(handlerWriter("user"))),
+		r.NewRoute("/files/{path...}").Add(r.Get(handlerWriter("file")).Name("file")),
+		r.NewRoute("/{$}").Name("index").Add(r.Get(handlerWriter("index"))),
+	)
+
+	tests := []struct {
+		name          string
+		routeName     string
+		params        []string
+		expectedURL   string
+		expectedError bool
+	}{
+		{name: "static route", routeName: "users", expectedURL: "/api/users"},
+		{name: "route with wildcard", routeName: "user", params: []string{"id", "42"}, expectedURL: "/api/users/42"},
+		{name: "escaped wildcard value", routeName: "user", params: []string{"id", "a b/c"}, expectedURL: "/api/users/a%20b%2Fc"},
+		{name: "multi segment wildcard", routeName: "file", params: []string{"path", "docs/a b.txt"}, expectedURL: "/api/files/docs/a%20b.txt"},
+		{name: "exact match route", routeName: "index", expectedURL: "/api/"},
+		{name: "undefined name", routeName: "posts", expectedError: true},
+		{name: "missing wildcard value", routeName: "user", expectedError: true},
+		{name: "odd params", routeName: "user", params: []string{"id"}, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := route.URL(tt.routeName, tt.params...)
+
+			assertCorrect(t, err != nil, tt.expectedError)
+			if !tt.expectedError {
+				assertCorrect(t, got, tt.expectedURL)
+			}
+		})
+	}
+}
+
+// TestNameWithEmptyName tests that an empty name causes a panic
+func TestNameWithEmptyName(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Name(\"\") to panic, but it didn't")
+		}
+	}()
+
+	r.NewRoute("/").Name("")
+}
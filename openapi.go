@@ -0,0 +1,189 @@
+package simplerouter
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// openAPIDocument is the root of an OpenAPI document.
+type openAPIDocument struct {
+	OpenAPI string                      `json:"openapi"`
+	Info    openAPIInfo                 `json:"info"`
+	Paths   map[string]*openAPIPathItem `json:"paths"`
+}
+
+// openAPIInfo holds the general information about the API.
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// openAPIPathItem holds the operations available on a path.
+type openAPIPathItem struct {
+	Get     *openAPIOperation `json:"get,omitempty"`
+	Put     *openAPIOperation `json:"put,omitempty"`
+	Post    *openAPIOperation `json:"post,omitempty"`
+	Delete  *openAPIOperation `json:"delete,omitempty"`
+	Options *openAPIOperation `json:"options,omitempty"`
+	Head    *openAPIOperation `json:"head,omitempty"`
+	Patch   *openAPIOperation `json:"patch,omitempty"`
+	Trace   *openAPIOperation `json:"trace,omitempty"`
+}
+
+// openAPIOperation describes a single API operation on a path.
+type openAPIOperation struct {
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+// openAPIRequestBody describes the request body of an operation.
+type openAPIRequestBody struct {
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+// openAPIParameter describes a path parameter of an operation.
+type openAPIParameter struct {
+	Name     string        `json:"name"`
+	In       string        `json:"in"`
+	Required bool          `json:"required"`
+	Schema   openAPISchema `json:"schema"`
+}
+
+// openAPISchema describes the type of a value.
+type openAPISchema struct {
+	Type string `json:"type"`
+}
+
+// openAPIResponse describes a response of an operation.
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+// openAPIMediaType describes the content of a request or response body with a given media type.
+type openAPIMediaType struct {
+	Example any `json:"example,omitempty"`
+}
+
+// wildcardRegexp matches the wildcards of http.ServeMux patterns.
+var wildcardRegexp = regexp.MustCompile(`\{([^}.$]+)(\.\.\.)?\}`)
+
+// OpenAPI generates an OpenAPI 3.1 document in JSON format describing the endpoints of the route tree.
+// Operations are described using the route metadata (summary, description, tags and deprecation),
+// the first request body of [Route.Examples] and the sample responses of [Route.Example],
+// and path wildcards are documented as path parameters.
+// Endpoints matching all methods cannot be expressed as OpenAPI operations and are left out.
+func (r *Route) OpenAPI(title, version string) []byte {
+	document := openAPIDocument{
+		OpenAPI: "3.1.0",
+		Info:    openAPIInfo{Title: title, Version: version},
+		Paths:   map[string]*openAPIPathItem{},
+	}
+
+	for _, endpoint := range r.Endpoints() {
+		path := openAPIPath(endpoint.Path)
+		if document.Paths[path] == nil {
+			document.Paths[path] = &openAPIPathItem{}
+		}
+
+		operation := &openAPIOperation{
+			Summary:     endpoint.Metadata.Summary,
+			Description: endpoint.Metadata.Description,
+			Tags:        endpoint.Metadata.Tags,
+			Deprecated:  endpoint.Metadata.Deprecated,
+			Responses:   openAPIResponses(endpoint.Metadata.Responses),
+		}
+		for _, example := range endpoint.Metadata.Examples {
+			if example.Request != nil {
+				operation.RequestBody = &openAPIRequestBody{Content: openAPIContent(example.Request)}
+				break
+			}
+		}
+		for _, match := range wildcardRegexp.FindAllStringSubmatch(endpoint.Path, -1) {
+			operation.Parameters = append(operation.Parameters, openAPIParameter{
+				Name:     match[1],
+				In:       "path",
+				Required: true,
+				Schema:   openAPISchema{Type: "string"},
+			})
+		}
+
+		item := document.Paths[path]
+		switch endpoint.Method {
+		case http.MethodGet:
+			item.Get = operation
+		case http.MethodPut:
+			item.Put = operation
+		case http.MethodPost:
+			item.Post = operation
+		case http.MethodDelete:
+			item.Delete = operation
+		case http.MethodOptions:
+			item.Options = operation
+		case http.MethodHead:
+			item.Head = operation
+		case http.MethodPatch:
+			item.Patch = operation
+		case http.MethodTrace:
+			item.Trace = operation
+		}
+	}
+
+	// Remove paths left without operations, like the ones only matching all methods.
+	for path, item := range document.Paths {
+		if *item == (openAPIPathItem{}) {
+			delete(document.Paths, path)
+		}
+	}
+
+	out, _ := json.MarshalIndent(document, "", "  ")
+	return out
+}
+
+// openAPIResponses returns the responses of an operation documenting the sample responses of its route,
+// or a default response if there are none.
+func openAPIResponses(examples []ResponseExample) map[string]openAPIResponse {
+	if len(examples) == 0 {
+		return map[string]openAPIResponse{"default": {Description: "Default response"}}
+	}
+
+	responses := map[string]openAPIResponse{}
+	for _, example := range examples {
+		response := openAPIResponse{Description: http.StatusText(example.Status)}
+		if example.Body != nil {
+			response.Content = openAPIContent(example.Body)
+		}
+		responses[strconv.Itoa(example.Status)] = response
+	}
+	return responses
+}
+
+// openAPIContent returns the content of a body with the given example,
+// plain text for strings and byte slices and JSON for other values.
+func openAPIContent(example any) map[string]openAPIMediaType {
+	switch v := example.(type) {
+	case string:
+		return map[string]openAPIMediaType{"text/plain": {Example: v}}
+	case []byte:
+		return map[string]openAPIMediaType{"text/plain": {Example: string(v)}}
+	}
+	return map[string]openAPIMediaType{"application/json": {Example: example}}
+}
+
+// openAPIPath converts an http.ServeMux path into an OpenAPI path template.
+func openAPIPath(path string) string {
+	// Patterns may start with a host, which is not part of the OpenAPI path.
+	if i := strings.Index(path, "/"); i > 0 {
+		path = path[i:]
+	}
+	path = strings.TrimSuffix(path, "{$}")
+	return wildcardRegexp.ReplaceAllString(path, "{$1}")
+}
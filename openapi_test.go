@@ -0,0 +1,162 @@
+package simplerouter_test
+
+import (
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestOpenAPI tests the OpenAPI document generated from a route tree
+func TestOpenAPI(t *testing.T) {
+	route := r.NewRoute("/api").Tags("api").Add(
+		r.NewRoute("/users").Add(
+			r.Get(handlerWriter("users")).Summary("List users"),
+			r.Post(handlerWriter("create user")).Description("Creates a user.").Deprecated(),
+		),
+		r.NewRoute("/users/{id}").Add(
+			r.Get(handlerWriter("user")),
+		),
+		r.NewRoute("/files/{path...}").Add(
+			r.All(handlerWriter("files")),
+		),
+		r.NewRoute("/{$}").Add(
+			r.Get(handlerWriter("index")),
+		),
+	)
+
+	want := `{
+  "openapi": "3.1.0",
+  "info": {
+    "title": "Test API",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/api/": {
+      "get": {
+        "tags": [
+          "api"
+        ],
+        "responses": {
+          "default": {
+            "description": "Default response"
+          }
+        }
+      }
+    },
+    "/api/users": {
+      "get": {
+        "summary": "List users",
+        "tags": [
+          "api"
+        ],
+        "responses": {
+          "default": {
+            "description": "Default response"
+          }
+        }
+      },
+      "post": {
+        "description": "Creates a user.",
+        "tags": [
+          "api"
+        ],
+        "deprecated": true,
+        "responses": {
+          "default": {
+            "description": "Default response"
+          }
+        }
+      }
+    },
+    "/api/users/{id}": {
+      "get": {
+        "tags": [
+          "api"
+        ],
+        "parameters": [
+          {
+            "name": "id",
+            "in": "path",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "default": {
+            "description": "Default response"
+          }
+        }
+      }
+    }
+  }
+}`
+
+	got := string(route.OpenAPI("Test API", "1.0.0"))
+	if got != want {
+		t.Errorf("OpenAPI() = %s, want %s", got, want)
+	}
+}
+
+// TestOpenAPIExamples tests the descriptions and examples documented in the OpenAPI document
+func TestOpenAPIExamples(t *testing.T) {
+	route := r.NewRoute("/users").Add(
+		r.Post(handlerWriter("create user")).
+			Describe("Create user", "Creates a user.").
+			Examples(r.Example{Request: map[string]string{"name": "ada"}, Status: 201}).
+			Example(201, map[string]any{"id": 1, "name": "ada"}).
+			Example(400, "invalid user"),
+	)
+
+	want := `{
+  "openapi": "3.1.0",
+  "info": {
+    "title": "Test API",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/users": {
+      "post": {
+        "summary": "Create user",
+        "description": "Creates a user.",
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "example": {
+                "name": "ada"
+              }
+            }
+          }
+        },
+        "responses": {
+          "201": {
+            "description": "Created",
+            "content": {
+              "application/json": {
+                "example": {
+                  "id": 1,
+                  "name": "ada"
+                }
+              }
+            }
+          },
+          "400": {
+            "description": "Bad Request",
+            "content": {
+              "text/plain": {
+                "example": "invalid user"
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+	got := string(route.OpenAPI("Test API", "1.0.0"))
+	if got != want {
+		t.Errorf("OpenAPI() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package simplerouter
+
+import (
+	"net/http"
+)
+
+// Optional marks the route and its child routes as depending on a resource that may fail to initialize,
+// like a database or a third party client. If initErr is not nil, the routes are still mounted, with their
+// middlewares, but their handlers are replaced by fallback so the rest of the service keeps working.
+// If fallback is nil, a 503 Service Unavailable response is written.
+// Failed optional routes are reported by [Route.Degraded] and by the health endpoint of [NewAPI].
+func (r *Route) Optional(initErr error, fallback http.HandlerFunc) *Route {
+	if initErr == nil {
+		return r
+	}
+	if fallback == nil {
+		fallback = func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		}
+	}
+	r.Metadata.InitError = initErr
+	r.Metadata.Fallback = fallback
+	return r
+}
+
+// Degraded returns the errors of the optional routes of the tree whose dependency failed to initialize,
+// indexed by the full path of the route marked as optional.
+func (r *Route) Degraded() map[string]error {
+	degraded := map[string]error{}
+
+	var visit func(route *Route, path string)
+	visit = func(route *Route, path string) {
+		chainedPath := path + route.Path
+		if route.Metadata.InitError != nil {
+			degraded[chainedPath] = route.Metadata.InitError
+			return
+		}
+		for _, child := range route.Routes {
+			visit(child, chainedPath)
+		}
+	}
+	visit(r, "")
+
+	return degraded
+}
+
+// handler returns the handler serving a route with the metadata m,
+// the fallback of the optional route it belongs to if its dependency failed.
+func (m Metadata) handler(handler http.HandlerFunc) http.HandlerFunc {
+	if m.InitError != nil {
+		return m.Fallback
+	}
+	return handler
+}
@@ -0,0 +1,76 @@
+package simplerouter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestOptional tests the handlers serving optional routes
+func TestOptional(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedMws    []string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "healthy route", path: "/users", expectedMws: []string{"mw1"}, expectedStatus: http.StatusOK, expectedBody: "users"},
+		{name: "healthy optional route", path: "/search", expectedMws: []string{"mw1"}, expectedStatus: http.StatusOK, expectedBody: "search"},
+		{name: "failed optional route", path: "/payments/1", expectedMws: []string{"mw1", "mw2"}, expectedStatus: http.StatusServiceUnavailable, expectedBody: "Service Unavailable\n"},
+		{name: "custom fallback", path: "/reports", expectedMws: []string{"mw1"}, expectedStatus: http.StatusOK, expectedBody: "reports disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mwTrackerSlice := []string{}
+			mux := r.NewRoute("").Use(middlewareTracker("mw1", &mwTrackerSlice)).Add(
+				r.NewRoute("/users").Add(r.Get(handlerWriter("users"))),
+				r.NewRoute("/search").Optional(nil, nil).Add(r.Get(handlerWriter("search"))),
+				r.NewRoute("/payments").Optional(errors.New("connection refused"), nil).Use(middlewareTracker("mw2", &mwTrackerSlice)).Add(
+					r.NewRoute("/{id}").Add(r.Get(handlerWriter("payment"))),
+				),
+				r.NewRoute("/reports").Optional(errors.New("timeout"), handlerWriter("reports disabled")).Add(r.Get(handlerWriter("reports"))),
+			).Mount()
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			if !reflect.DeepEqual(mwTrackerSlice, tt.expectedMws) {
+				t.Errorf("Middlewares executed = %v, want %v", mwTrackerSlice, tt.expectedMws)
+			}
+		})
+	}
+}
+
+// TestDegraded tests the failed optional routes reported by a tree
+func TestDegraded(t *testing.T) {
+	paymentsErr := errors.New("connection refused")
+	route := r.NewRoute("/api").Add(
+		r.NewRoute("/users").Optional(nil, nil).Add(r.Get(handlerWriter("users"))),
+		r.NewRoute("/payments").Optional(paymentsErr, nil).Add(r.Get(handlerWriter("payments"))),
+	)
+
+	want := map[string]error{"/api/payments": paymentsErr}
+	if got := route.Degraded(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Degraded() = %v, want %v", got, want)
+	}
+}
+
+// TestNewAPIHealthDegraded tests that the health endpoint reports failed optional routes
+func TestNewAPIHealthDegraded(t *testing.T) {
+	root, api := r.NewAPI(r.APIOptions{Version: "/v1"})
+	api.Add(r.NewRoute("/payments").Optional(errors.New("connection refused"), nil).Add(r.Get(handlerWriter("payments"))))
+
+	w := httptest.NewRecorder()
+	root.Mount().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assertCorrect(t, w.Code, http.StatusOK)
+	assertCorrect(t, w.Body.String(), "{\"degraded\":{\"/v1/payments\":\"connection refused\"},\"status\":\"degraded\"}\n")
+}
@@ -0,0 +1,35 @@
+package simplerouter
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Parse returns a Route for the path of a pattern in the "[METHOD ]/path" form of http.ServeMux, holding
+// the handler for its method, or for all methods if the pattern has none. It allows compact registration
+// tables and migrating raw http.ServeMux code one line at a time:
+//
+//	router.Add(
+//		simplerouter.Parse("GET /users", listUsers),
+//		simplerouter.Parse("GET /users/{id}", getUser),
+//		simplerouter.Parse("/static/", serveStatic),
+//	)
+//
+// The returned route is the parent of the handler, so the middlewares and metadata set on it apply to it.
+// Patterns with a host are not supported, as paths are joined with the ones of the parent routes.
+// It panics if the pattern is empty, its path does not start with "/" or handler is nil.
+func Parse(pattern string, handler http.HandlerFunc) *Route {
+	if handler == nil {
+		panic("handler parameter cannot be nil")
+	}
+	method, path, found := strings.Cut(strings.TrimSpace(pattern), " ")
+	if !found {
+		method, path = "", method
+	}
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \t") {
+		panic("pattern parameter must be a method and a path starting with /, like \"GET /users\", got " + pattern)
+	}
+
+	return NewRoute(path).Add((&Route{Handler: handler, Method: method}).recordBuild("Parse", handlerName(handler)))
+}
@@ -0,0 +1,62 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestParse tests that the routes parsed from patterns serve their method and path
+func TestParse(t *testing.T) {
+	tracker := []string{}
+	mux := r.NewRoute("/api").Add(
+		r.Parse("GET /users", handlerWriter("list")),
+		r.Parse("POST  /users", handlerWriter("create")),
+		r.Parse("GET /users/{id}", handlerWriter("get")).Use(middlewareTracker("user", &tracker)),
+		r.Parse("/files/", handlerWriter("files")),
+	).Mount(r.WithValidation())
+
+	tests := []struct {
+		method          string
+		path            string
+		expectedStatus  int
+		expectedBody    string
+		expectedTracker int
+	}{
+		{method: http.MethodGet, path: "/api/users", expectedStatus: http.StatusOK, expectedBody: "list"},
+		{method: http.MethodPost, path: "/api/users", expectedStatus: http.StatusOK, expectedBody: "create"},
+		{method: http.MethodGet, path: "/api/users/1", expectedStatus: http.StatusOK, expectedBody: "get", expectedTracker: 1},
+		{method: http.MethodDelete, path: "/api/files/a.txt", expectedStatus: http.StatusOK, expectedBody: "files"},
+		{method: http.MethodDelete, path: "/api/users", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			tracker = []string{}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, len(tracker), tt.expectedTracker)
+			if tt.expectedBody != "" {
+				assertCorrect(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+// TestParseWithInvalidPatterns tests that invalid patterns cause a panic
+func TestParseWithInvalidPatterns(t *testing.T) {
+	for _, pattern := range []string{"", "GET", "GET users", "example.com/users", "GET /users extra"} {
+		t.Run(pattern, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Parse(%q) did not panic", pattern)
+				}
+			}()
+			r.Parse(pattern, handlerWriter(""))
+		})
+	}
+}
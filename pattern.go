@@ -0,0 +1,69 @@
+package simplerouter
+
+import (
+	"context"
+	"net/http"
+)
+
+// RouteInfo describes the endpoint serving a request, see [RouteInfoFrom].
+type RouteInfo struct {
+	// Method is the HTTP method of the endpoint, empty when it matches all methods.
+	Method string
+	// Pattern is the path pattern of the endpoint, as returned by [PatternFrom].
+	Pattern string
+	// Name is the name of the endpoint, or of the route defining its path if the endpoint has none,
+	// so routes named as in NewRoute("/users/{id}").Name("user").Add(Get(getUser)) identify their handlers.
+	Name string
+	// BasePath is the path prefixing the patterns of the tree, see [WithBasePath].
+	BasePath string
+	// Locale is the locale of the path of the endpoint, empty if it is not localized, see [Route.Localize].
+	Locale string
+	// Metadata is the metadata of the endpoint, including the one inherited from its parent routes.
+	Metadata Metadata
+
+	// paths maps the route names of the mounted tree to their full paths, for [URLFrom].
+	paths map[string]string
+}
+
+// routeInfoKey is the context key storing the RouteInfo of the endpoint serving the request.
+type routeInfoKey struct{}
+
+// RouteInfoFrom returns the description of the endpoint serving the request, or nil if the request is not
+// served by a mounted route tree. It is available to the middlewares and handlers of the route, so generic
+// middlewares can be driven by the annotations of the routes, like the scopes required to call them:
+//
+//	router.Add(simplerouter.NewRoute("/admin").Meta("scopes", []string{"admin"}).Add(...))
+//
+//	func authorize(next http.Handler) http.Handler {
+//		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//			scopes, _ := simplerouter.RouteInfoFrom(r.Context()).Metadata.Values["scopes"].([]string)
+//			...
+//		})
+//	}
+//
+// The RouteInfo is shared by the requests of the endpoint and must not be modified.
+// CORS preflight requests get the metadata inherited by the endpoints of their path, without their names.
+func RouteInfoFrom(ctx context.Context) *RouteInfo {
+	info, _ := ctx.Value(routeInfoKey{}).(*RouteInfo)
+	return info
+}
+
+// PatternFrom returns the path pattern of the route serving the request, like "/api/users/{id}",
+// or an empty string if the request is not served by a mounted route tree. It is available to the
+// middlewares and handlers of the route, so metrics and logs can be labeled by route instead of by
+// concrete URL. Requests served through an alias get the path pattern of the alias.
+func PatternFrom(ctx context.Context) string {
+	if info := RouteInfoFrom(ctx); info != nil {
+		return info.Pattern
+	}
+	return ""
+}
+
+// withRouteInfo returns a handler storing the description of the endpoint in the request context before calling next.
+func withRouteInfo(info *RouteInfo, next http.Handler) http.Handler {
+	// The description is converted to an interface once, instead of on every request.
+	var value any = info
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeInfoKey{}, value)))
+	})
+}
@@ -0,0 +1,105 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestPatternFrom tests the patterns seen by the middlewares and handlers of the routes
+func TestPatternFrom(t *testing.T) {
+	seen := ""
+	recordPattern := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			seen = r.PatternFrom(req.Context())
+			next.ServeHTTP(w, req)
+		})
+	}
+	writePattern := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(r.PatternFrom(req.Context())))
+	}
+
+	mux := r.NewRoute("/api").Use(recordPattern).Add(
+		r.NewRoute("/users/{id}").Alias("/people/{id}").Add(r.Get(writePattern)),
+		r.NewRoute("/files/{path...}").Add(r.All(writePattern)),
+	).Mount()
+
+	tests := []struct {
+		name            string
+		path            string
+		expectedPattern string
+	}{
+		{name: "wildcard", path: "/api/users/42", expectedPattern: "/api/users/{id}"},
+		{name: "alias", path: "/api/people/42", expectedPattern: "/api/people/{id}"},
+		{name: "multi segment wildcard", path: "/api/files/a/b.txt", expectedPattern: "/api/files/{path...}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Body.String(), tt.expectedPattern)
+			assertCorrect(t, seen, tt.expectedPattern)
+		})
+	}
+
+	assertCorrect(t, r.PatternFrom(httptest.NewRequest(http.MethodGet, "/", nil).Context()), "")
+}
+
+// TestRouteInfoFrom tests the descriptions of the endpoints seen by the middlewares and handlers of the routes
+func TestRouteInfoFrom(t *testing.T) {
+	var seen *r.RouteInfo
+	recordInfo := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			seen = r.RouteInfoFrom(req.Context())
+			next.ServeHTTP(w, req)
+		})
+	}
+
+	mux := r.NewRoute("/api").Use(recordInfo).Tags("api").Meta("scopes", "read").Add(
+		r.NewRoute("/users/{id}").Name("user").Alias("/people/{id}").Add(
+			r.Get(handlerWriter("")),
+			r.Delete(handlerWriter("")).Name("deleteUser").Meta("scopes", "admin"),
+		),
+		r.NewRoute("/files/").CORS(middleware.CORSOptions{AllowedOrigins: []string{"https://example.com"}}).Add(r.Put(handlerWriter(""))),
+	).Mount()
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		expectedMethod  string
+		expectedPattern string
+		expectedName    string
+		expectedScopes  string
+	}{
+		{name: "route named by its parent", method: http.MethodGet, path: "/api/users/42", expectedMethod: http.MethodGet, expectedPattern: "/api/users/{id}", expectedName: "user", expectedScopes: "read"},
+		{name: "alias", method: http.MethodGet, path: "/api/people/42", expectedMethod: http.MethodGet, expectedPattern: "/api/people/{id}", expectedName: "user", expectedScopes: "read"},
+		{name: "named route", method: http.MethodDelete, path: "/api/users/42", expectedMethod: http.MethodDelete, expectedPattern: "/api/users/{id}", expectedName: "deleteUser", expectedScopes: "admin"},
+		{name: "preflight", method: http.MethodOptions, path: "/api/files/", expectedMethod: http.MethodOptions, expectedPattern: "/api/files/", expectedScopes: "read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			if seen == nil {
+				t.Fatal("RouteInfoFrom() = nil")
+			}
+			assertCorrect(t, seen.Method, tt.expectedMethod)
+			assertCorrect(t, seen.Pattern, tt.expectedPattern)
+			assertCorrect(t, seen.Name, tt.expectedName)
+			assertCorrect(t, seen.Metadata.Values["scopes"], tt.expectedScopes)
+			assertCorrect(t, seen.Metadata.Tags[0], "api")
+		})
+	}
+
+	if info := r.RouteInfoFrom(httptest.NewRequest(http.MethodGet, "/", nil).Context()); info != nil {
+		t.Errorf("RouteInfoFrom() outside a route tree = %v, want nil", info)
+	}
+}
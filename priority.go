@@ -0,0 +1,109 @@
+package simplerouter
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+)
+
+// Priority sets the priority of the endpoints of the route and its child routes, used to resolve the
+// overlaps between patterns that http.ServeMux rejects as conflicting, like "GET /users/{id}" and
+// "/users/me": instead of panicking, the endpoint with the greater priority wins the requests matched
+// by both patterns, and the other one keeps serving the rest of its requests:
+//
+//	router.Add(
+//		simplerouter.NewRoute("/users/me").Priority(1).Add(simplerouter.All(me)),
+//		simplerouter.NewRoute("/users/{id}").Add(simplerouter.Get(getUser)),
+//	)
+//
+// Endpoints have priority 0 by default, and child routes can set their own non-zero priority, which replaces
+// the one of their parents. Conflicts between endpoints with the same priority are still reported by
+// [Route.Mount] and [Route.Validate]. The endpoints losing a conflict are served from a nested
+// http.ServeMux registered on the "/" pattern, so trees relying on priorities cannot register it.
+func (r *Route) Priority(n int) *Route {
+	r.Metadata.Priority = n
+	return r
+}
+
+// register registers the table of handlers of the mounter into its router, from the greatest priority
+// to the lowest. The handlers conflicting with a handler of greater priority are registered into
+// a nested http.ServeMux serving the requests not matched by the router, and so on.
+// It returns the router of the handlers with the lowest priority.
+func (m *mounter) register() registrar {
+	handlers := slices.Clone(m.handlers)
+	slices.SortStableFunc(handlers, func(a, b mountedHandler) int { return cmp.Compare(b.priority, a.priority) })
+
+	router := m.router
+	for {
+		priorities := map[string]int{}
+		var registered []string
+		var overflow []mountedHandler
+		for _, h := range handlers {
+			if conflicts, ok := tryHandle(router, h.pattern, h.handler, registered); !ok {
+				if !slices.ContainsFunc(conflicts, func(c string) bool { return priorities[c] <= h.priority }) {
+					overflow = append(overflow, h)
+					continue
+				}
+				// Conflicts between handlers of the same priority panic as usual.
+				router.Handle(h.pattern, h.handler)
+			}
+			priorities[h.pattern] = h.priority
+			registered = append(registered, h.pattern)
+		}
+		if len(overflow) == 0 {
+			return router
+		}
+
+		next := http.NewServeMux()
+		router.Handle("/", next)
+		router, handlers = next, overflow
+	}
+}
+
+// tryHandle registers the handler for the pattern into router, reporting the patterns of registered it
+// conflicts with instead of panicking. It panics for any other problem.
+func tryHandle(router registrar, pattern string, handler http.Handler, registered []string) (conflicts []string, ok bool) {
+	defer func() {
+		if p := recover(); p != nil {
+			conflicts = conflictingPatterns(pattern, registered)
+			if len(conflicts) == 0 {
+				panic(p)
+			}
+		}
+	}()
+
+	router.Handle(pattern, handler)
+	return nil, true
+}
+
+// conflictingPatterns returns the patterns of registered conflicting with pattern, in the same order.
+// The panics of http.ServeMux only describe the conflicts in their message, so each pair of patterns is
+// registered into a probe http.ServeMux instead, conflicting if it panics. It returns nil if the pattern
+// is invalid.
+func conflictingPatterns(pattern string, registered []string) []string {
+	if !registers(pattern) {
+		return nil
+	}
+	var conflicts []string
+	for _, other := range registered {
+		if !registers(other, pattern) {
+			conflicts = append(conflicts, other)
+		}
+	}
+	return conflicts
+}
+
+// registers reports whether the patterns can be registered together into an http.ServeMux.
+func registers(patterns ...string) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	mux := http.NewServeMux()
+	for _, pattern := range patterns {
+		mux.Handle(pattern, http.NotFoundHandler())
+	}
+	return true
+}
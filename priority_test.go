@@ -0,0 +1,104 @@
+package simplerouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// priorityTree returns a tree with conflicting "/users/me" and "GET /users/{id}" routes,
+// with the given priorities
+func priorityTree(me, user int) *r.Route {
+	return r.NewRoute("/users").Add(
+		r.NewRoute("/me").Priority(me).Add(r.All(handlerWriter("me"))),
+		r.NewRoute("/{id}").Priority(user).Add(r.Get(handlerWriter("user"))),
+	)
+}
+
+// TestPriority tests that the routes with the greatest priority win the requests matched by conflicting patterns
+func TestPriority(t *testing.T) {
+	tests := []struct {
+		name         string
+		route        *r.Route
+		method       string
+		path         string
+		expectedBody string
+	}{
+		{name: "literal wins", route: priorityTree(1, 0), method: http.MethodGet, path: "/users/me", expectedBody: "me"},
+		{name: "literal keeps its requests", route: priorityTree(1, 0), method: http.MethodPost, path: "/users/me", expectedBody: "me"},
+		{name: "param keeps its requests", route: priorityTree(1, 0), method: http.MethodGet, path: "/users/42", expectedBody: "user"},
+		{name: "param wins", route: priorityTree(0, 1), method: http.MethodGet, path: "/users/me", expectedBody: "user"},
+		{name: "literal served after param", route: priorityTree(0, 1), method: http.MethodPost, path: "/users/me", expectedBody: "me"},
+		{name: "negative priority", route: priorityTree(0, -1), method: http.MethodGet, path: "/users/me", expectedBody: "me"},
+		{name: "inherited priority", route: r.NewRoute("").Priority(-1).Add(priorityTree(0, 1)), method: http.MethodGet, path: "/users/me", expectedBody: "user"},
+	}
+
+	for _, tt := range tests {
+		for name, mux := range map[string]http.Handler{"Mount": tt.route.Mount(), "MountFast": tt.route.MountFast()} {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+				assertCorrect(t, w.Code, http.StatusOK)
+				assertCorrect(t, w.Body.String(), tt.expectedBody)
+			})
+		}
+	}
+}
+
+// TestPriorityWithSamePriority tests that conflicts between routes with the same priority are still reported
+func TestPriorityWithSamePriority(t *testing.T) {
+	route := priorityTree(1, 1)
+
+	err := route.Validate()
+	if err == nil || !strings.Contains(err.Error(), "conflict") {
+		t.Errorf("Validate() = %v, want a conflict", err)
+	}
+	if err := priorityTree(1, 0).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil for different priorities", err)
+	}
+
+	defer func() {
+		if v := recover(); !strings.Contains(fmt.Sprint(v), "conflicts with") {
+			t.Errorf("Mount() panicked with %v, want a conflict", v)
+		}
+	}()
+	route.Mount()
+}
+
+// TestPriorityWithNotFound tests that the not found handler answers the requests not matched by any priority
+func TestPriorityWithNotFound(t *testing.T) {
+	mux := priorityTree(1, 0).Mount(r.WithValidation(), r.WithNotFound(handlerWriter("custom not found")))
+
+	for path, expectedBody := range map[string]string{"/users/me": "me", "/users/42": "user", "/orders": "custom not found"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assertCorrect(t, w.Body.String(), expectedBody)
+	}
+}
+
+// TestPriorityWithSeveralConflicts tests that a pattern conflicting with patterns of a greater and of the
+// same priority is reported, whichever conflict http.ServeMux describes
+func TestPriorityWithSeveralConflicts(t *testing.T) {
+	route := r.NewRoute("").Add(
+		r.NewRoute("/users/me").Priority(1).Add(r.All(handlerWriter("me"))),
+		r.NewRoute("/{x}/me").Add(r.All(handlerWriter("x"))),
+		r.NewRoute("/users/{id}").Add(r.Get(handlerWriter("user"))),
+	)
+
+	err := route.Validate()
+	if err == nil || !strings.Contains(err.Error(), `GET /users/{id}: conflict: conflicts with "/{x}/me"`) {
+		t.Errorf("Validate() = %v, want a conflict with \"/{x}/me\"", err)
+	}
+
+	defer func() {
+		if v := recover(); !strings.Contains(fmt.Sprint(v), "conflicts with") {
+			t.Errorf("Mount() panicked with %v, want a conflict", v)
+		}
+	}()
+	route.Mount()
+}
@@ -0,0 +1,81 @@
+package simplerouter
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// provenance reports whether the source of the Use calls is recorded.
+var provenance atomic.Bool
+
+// packagePath is the import path of this package, used to skip its frames when looking for callers.
+var packagePath = reflect.TypeFor[Route]().PkgPath()
+
+// EnableProvenance enables or disables recording where middlewares are attached to routes.
+// When enabled, the file and line of each Use call (the first caller outside this package) is recorded,
+// and reported by [Route.MiddlewareSources], [Endpoint.MiddlewareSources] and the exports of the tree,
+// to find out why a middleware runs on a given route in big composed trees.
+// It is disabled by default, as it has a cost, and only affects the Use calls made after enabling it.
+func EnableProvenance(enabled bool) {
+	provenance.Store(enabled)
+}
+
+// MiddlewareSources returns the "file:line" locations where the middlewares of the route were attached,
+// aligned with the Middlewares field. Locations are empty for middlewares attached
+// while provenance was disabled, see [EnableProvenance].
+func (r *Route) MiddlewareSources() []string {
+	sources := make([]string, len(r.Middlewares))
+	copy(sources, r.sources)
+	return sources
+}
+
+// recordSources records the source of n middlewares about to be attached to the route.
+func (r *Route) recordSources(n int) {
+	source := ""
+	if provenance.Load() {
+		source = callerSource()
+	}
+	if source == "" && len(r.sources) == 0 {
+		return
+	}
+
+	// Middlewares may have been appended directly to the field, keep the sources aligned with them.
+	sources := r.MiddlewareSources()
+	for range n {
+		sources = append(sources, source)
+	}
+	r.sources = sources
+}
+
+// callerSource returns the "file:line" location of the first caller outside this package.
+func callerSource() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePath+".") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// shortSource shortens a source location to its file name and parent directory for display.
+func shortSource(source string) string {
+	dir, file := filepath.Split(source)
+	return filepath.Join(filepath.Base(dir), file)
+}
+
+// middlewareName returns the display name of a middleware, followed by its source if known.
+func middlewareName(mw Middleware, source string) string {
+	if source == "" {
+		return FuncName(mw)
+	}
+	return FuncName(mw) + " (" + shortSource(source) + ")"
+}
@@ -0,0 +1,94 @@
+package simplerouter_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// previousLine returns the location of the line before the caller
+func previousLine() string {
+	_, file, line, _ := runtime.Caller(1)
+	return "/" + filepath.Base(file) + ":" + strconv.Itoa(line-1)
+}
+
+// TestMiddlewareSources tests the sources recorded for attached middlewares
+func TestMiddlewareSources(t *testing.T) {
+	route := r.NewRoute("/api").Use(authMiddleware)
+
+	r.EnableProvenance(true)
+	defer r.EnableProvenance(false)
+
+	route.Use(authMiddleware, authMiddleware)
+	useLine := previousLine()
+	route.CORS(middleware.CORSOptions{})
+	corsLine := previousLine()
+	route.Middlewares = append(route.Middlewares, authMiddleware)
+
+	sources := route.MiddlewareSources()
+	if len(sources) != 5 {
+		t.Fatalf("MiddlewareSources() returned %d sources, want 5", len(sources))
+	}
+
+	tests := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{name: "attached before enabling", source: sources[0], expected: ""},
+		{name: "first of use call", source: sources[1], expected: useLine},
+		{name: "second of use call", source: sources[2], expected: useLine},
+		{name: "attached by builder", source: sources[3], expected: corsLine},
+		{name: "appended to the field", source: sources[4], expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.HasSuffix(tt.source, tt.expected) || (tt.expected == "") != (tt.source == "") {
+				t.Errorf("Source = %q, want suffix %q", tt.source, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMiddlewareSourcesWithUsePre tests that the sources of prepended middlewares stay aligned with them
+func TestMiddlewareSourcesWithUsePre(t *testing.T) {
+	r.EnableProvenance(true)
+	defer r.EnableProvenance(false)
+
+	route := r.NewRoute("/api").Use(authMiddleware)
+	useLine := previousLine()
+	route.UsePre(middleware.RequestID())
+	usePreLine := previousLine()
+
+	sources := route.MiddlewareSources()
+	assertCorrect(t, len(sources), 2)
+	if !strings.HasSuffix(sources[0], usePreLine) || !strings.HasSuffix(sources[1], useLine) {
+		t.Errorf("MiddlewareSources() = %q, want suffixes %q and %q", sources, usePreLine, useLine)
+	}
+}
+
+// TestMiddlewareSourcesInExports tests that the sources are reported by the exports of the tree
+func TestMiddlewareSourcesInExports(t *testing.T) {
+	r.EnableProvenance(true)
+	defer r.EnableProvenance(false)
+
+	route := r.NewRoute("/api").Use(authMiddleware).Add(r.Get(listUsers))
+	line := previousLine()
+	want := line + ")"
+
+	endpoint := route.Endpoints()[0]
+	if !strings.HasSuffix(endpoint.MiddlewareSources[0], line) {
+		t.Errorf("Endpoint source = %q", endpoint.MiddlewareSources[0])
+	}
+	for name, export := range map[string]string{"Markdown": route.Markdown(), "DOT": route.DOT(), "Mermaid": route.Mermaid()} {
+		if !strings.Contains(export, want) {
+			t.Errorf("%s() = %q, want it to contain %q", name, export, want)
+		}
+	}
+}
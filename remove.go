@@ -0,0 +1,61 @@
+package simplerouter
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Remove removes from the route tree the routes with a handler for the given method and full path,
+// as reported by [Endpoint] (the method is empty for routes matching all methods), so programs
+// assembling trees from plugins or configuration can prune them before mounting.
+// It reports whether any route was removed. It panics if the tree is frozen, see [WithFreeze].
+func (r *Route) Remove(path, method string) bool {
+	r.checkFrozen()
+	return r.removeRoutes("", path, method)
+}
+
+// removeRoutes removes the matching routes from the child routes of r, whose full path is parentPath.
+func (r *Route) removeRoutes(parentPath, path, method string) bool {
+	fullPath := parentPath + r.Path
+	removed := false
+	r.Routes = slices.DeleteFunc(r.Routes, func(route *Route) bool {
+		if route.Handler != nil && route.Method == method && fullPath+route.Path == path {
+			removed = true
+			return true
+		}
+		return false
+	})
+	for _, route := range r.Routes {
+		if route.removeRoutes(fullPath, path, method) {
+			removed = true
+		}
+	}
+	return removed
+}
+
+// Replace replaces the handler of the routes of the tree with the given method and full path,
+// found as by [Route.Remove], keeping their middlewares and metadata. It reports whether any route
+// was replaced. It panics if h is nil or the tree is frozen, see [WithFreeze].
+func (r *Route) Replace(path, method string, h http.HandlerFunc) bool {
+	if h == nil {
+		panic("h parameter cannot be nil")
+	}
+	r.checkFrozen()
+	return r.replaceRoutes("", path, method, h)
+}
+
+// replaceRoutes replaces the handler of the matching routes of the tree of r, whose parent full path is parentPath.
+func (r *Route) replaceRoutes(parentPath, path, method string, h http.HandlerFunc) bool {
+	fullPath := parentPath + r.Path
+	replaced := false
+	if r.Handler != nil && r.Method == method && fullPath == path {
+		r.Handler = h
+		replaced = true
+	}
+	for _, route := range r.Routes {
+		if route.replaceRoutes(fullPath, path, method, h) {
+			replaced = true
+		}
+	}
+	return replaced
+}
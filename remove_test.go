@@ -0,0 +1,50 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestRemoveAndReplace tests the routes served after pruning and overriding the tree
+func TestRemoveAndReplace(t *testing.T) {
+	route := r.NewRoute("/api").Add(
+		r.NewRoute("/users").Add(
+			r.Get(handlerWriter("users")),
+			r.Post(handlerWriter("created")),
+		),
+		r.NewRoute("/debug").Add(r.All(handlerWriter("debug"))),
+	)
+
+	assertCorrect(t, route.Remove("/api/users", http.MethodPost), true)
+	assertCorrect(t, route.Remove("/api/debug", ""), true)
+	assertCorrect(t, route.Remove("/api/users", http.MethodDelete), false)
+	assertCorrect(t, route.Replace("/api/users", http.MethodGet, handlerWriter("replaced")), true)
+	assertCorrect(t, route.Replace("/api/missing", http.MethodGet, handlerWriter("missing")), false)
+
+	mux := route.Mount()
+	tests := []struct {
+		method         string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{method: http.MethodGet, path: "/api/users", expectedStatus: http.StatusOK, expectedBody: "replaced"},
+		{method: http.MethodPost, path: "/api/users", expectedStatus: http.StatusMethodNotAllowed},
+		{method: http.MethodGet, path: "/api/debug", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			if tt.expectedBody != "" {
+				assertCorrect(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
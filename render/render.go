@@ -0,0 +1,94 @@
+// Package render writes HTTP responses with the right headers for their content. Values are encoded
+// before anything is written, so encoding errors are returned while the response can still be replaced,
+// ready to be returned by simplerouter error-returning handlers and answered by their error handler:
+//
+//	func getUser(w http.ResponseWriter, r *http.Request) error {
+//		user, err := users.Get(r.Context(), r.PathValue("id"))
+//		if err != nil {
+//			return err
+//		}
+//		return render.JSON(w, http.StatusOK, user)
+//	}
+//
+//	router.Add(simplerouter.NewRoute("/users/{id}").OnError(writeError).Add(simplerouter.GetE(getUser)))
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// JSON writes v encoded as JSON with the given status code. It returns the error encoding v,
+// in which case nothing is written, or the error writing the response.
+func JSON(w http.ResponseWriter, code int, v any) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return fmt.Errorf("render: encode JSON: %w", err)
+	}
+	return write(w, code, "application/json", buf.Bytes())
+}
+
+// XML writes v encoded as XML, preceded by the XML header, with the given status code. It returns
+// the error encoding v, in which case nothing is written, or the error writing the response.
+func XML(w http.ResponseWriter, code int, v any) error {
+	buf := bytes.NewBufferString(xml.Header)
+	if err := xml.NewEncoder(buf).Encode(v); err != nil {
+		return fmt.Errorf("render: encode XML: %w", err)
+	}
+	return write(w, code, "application/xml; charset=utf-8", buf.Bytes())
+}
+
+// Text writes text as plain text with the given status code. It returns the error writing the response.
+func Text(w http.ResponseWriter, code int, text string) error {
+	return write(w, code, "text/plain; charset=utf-8", []byte(text))
+}
+
+// NoContent writes a 204 No Content response. It always returns nil, so it can be returned by handlers
+// as the other functions of the package.
+func NoContent(w http.ResponseWriter) error {
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Stream writes the content of src with the given status code and content type, flushing the response
+// after each read so clients receive the data as it is produced, like server logs or generated exports.
+// It returns the error reading src or writing the response, when the status code is already sent.
+func Stream(w http.ResponseWriter, code int, contentType string, src io.Reader) error {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+
+	rc := http.NewResponseController(w)
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+			if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("render: stream: %w", err)
+		}
+	}
+}
+
+// write writes the body with the given status code and content type.
+func write(w http.ResponseWriter, code int, contentType string, body []byte) error {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	_, err := w.Write(body)
+	return err
+}
@@ -0,0 +1,130 @@
+package render_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/render"
+)
+
+func assertCorrect(t testing.TB, got, want any) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+type item struct {
+	Name string `json:"name" xml:"name"`
+}
+
+// TestRender tests the responses written by the rendering helpers
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name                string
+		render              func(w http.ResponseWriter) error
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "JSON",
+			render:              func(w http.ResponseWriter) error { return render.JSON(w, http.StatusCreated, item{Name: "a"}) },
+			expectedStatus:      http.StatusCreated,
+			expectedContentType: "application/json",
+			expectedBody:        `{"name":"a"}` + "\n",
+		},
+		{
+			name:                "XML",
+			render:              func(w http.ResponseWriter) error { return render.XML(w, http.StatusOK, item{Name: "a"}) },
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/xml; charset=utf-8",
+			expectedBody:        `<?xml version="1.0" encoding="UTF-8"?>` + "\n<item><name>a</name></item>",
+		},
+		{
+			name:                "text",
+			render:              func(w http.ResponseWriter) error { return render.Text(w, http.StatusAccepted, "queued") },
+			expectedStatus:      http.StatusAccepted,
+			expectedContentType: "text/plain; charset=utf-8",
+			expectedBody:        "queued",
+		},
+		{
+			name:           "no content",
+			render:         render.NoContent,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "stream",
+			render: func(w http.ResponseWriter) error {
+				return render.Stream(w, http.StatusOK, "text/csv", iotest.OneByteReader(strings.NewReader("a,b\n1,2\n")))
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv",
+			expectedBody:        "a,b\n1,2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			assertCorrect(t, tt.render(w), nil)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Header().Get("Content-Type"), tt.expectedContentType)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+// TestRenderEncodeError tests that encoding errors are answered by the error handler of the route
+func TestRenderEncodeError(t *testing.T) {
+	var renderErr error
+	mux := r.NewRoute("/items").OnError(func(w http.ResponseWriter, req *http.Request, err error) {
+		renderErr = err
+		render.Text(w, http.StatusInternalServerError, "cannot render")
+	}).Add(r.GetE(func(w http.ResponseWriter, req *http.Request) error {
+		return render.JSON(w, http.StatusOK, map[string]any{"bad": func() {}})
+	})).Mount()
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	assertCorrect(t, w.Code, http.StatusInternalServerError)
+	assertCorrect(t, w.Body.String(), "cannot render")
+	var unsupported *json.UnsupportedTypeError
+	assertCorrect(t, errors.As(renderErr, &unsupported), true)
+	assertCorrect(t, strings.HasPrefix(renderErr.Error(), "render: encode JSON: "), true)
+}
+
+// TestStreamReadError tests that errors reading the streamed content are returned
+func TestStreamReadError(t *testing.T) {
+	err := render.Stream(httptest.NewRecorder(), http.StatusOK, "text/plain", iotest.ErrReader(errors.New("broken")))
+	assertCorrect(t, err.Error(), "render: stream: broken")
+}
+
+// plainWriter is an http.ResponseWriter without support for flushing
+type plainWriter struct {
+	header http.Header
+	body   strings.Builder
+}
+
+func (w *plainWriter) Header() http.Header { return w.header }
+
+func (w *plainWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+func (w *plainWriter) WriteHeader(code int) {}
+
+// TestStreamWithoutFlush tests that content is streamed to writers that cannot flush
+func TestStreamWithoutFlush(t *testing.T) {
+	w := &plainWriter{header: http.Header{}}
+	err := render.Stream(w, http.StatusOK, "text/csv", iotest.OneByteReader(strings.NewReader("a,b\n1,2\n")))
+
+	assertCorrect(t, err, nil)
+	assertCorrect(t, w.body.String(), "a,b\n1,2\n")
+}
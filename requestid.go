@@ -0,0 +1,13 @@
+package simplerouter
+
+import (
+	"context"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// RequestIDFrom returns the ID of the request stored in ctx by the [middleware.RequestID] middleware,
+// part of [DefaultAPIStack], or an empty string if there is none.
+func RequestIDFrom(ctx context.Context) string {
+	return middleware.RequestIDFrom(ctx)
+}
@@ -0,0 +1,27 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestRequestIDFrom tests the request ID available to the handlers of a tree
+func TestRequestIDFrom(t *testing.T) {
+	mux := r.NewRoute("/").Use(middleware.RequestID()).Add(
+		r.Get(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(r.RequestIDFrom(req.Context())))
+		}),
+	).Mount()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "upstream-id")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	assertCorrect(t, w.Body.String(), "upstream-id")
+	assertCorrect(t, w.Header().Get("X-Request-ID"), "upstream-id")
+}
@@ -0,0 +1,77 @@
+package simplerouter
+
+import (
+	"encoding/xml"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// NoIndex marks the route and its child routes as not indexable by search engines.
+// Once mounted, their responses carry an "X-Robots-Tag: noindex" header
+// and they are left out of the sitemap generated by [Route.Sitemap].
+func (r *Route) NoIndex() *Route {
+	r.Metadata.NoIndex = true
+	return r
+}
+
+// noIndex sets the X-Robots-Tag header before calling the next handler.
+func noIndex(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Robots-Tag", "noindex")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Sitemap returns a handler serving an XML sitemap of the route tree, with locations prefixed by baseURL.
+// Only routes answering GET requests on concrete paths are listed: routes with wildcards and
+// routes marked with [Route.NoIndex] are skipped.
+// The sitemap is generated on the first request, so the handler can be added to the same tree it describes.
+func (r *Route) Sitemap(baseURL string) http.HandlerFunc {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	body := sync.OnceValue(func() []byte {
+		set := sitemapURLSet{Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+		for _, path := range r.sitemapPaths() {
+			set.URLs = append(set.URLs, sitemapURL{Loc: baseURL + path})
+		}
+
+		out, _ := xml.MarshalIndent(set, "", "  ")
+		return append([]byte(xml.Header), out...)
+	})
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.Write(body())
+	}
+}
+
+// sitemapURLSet is the root element of a sitemap document.
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// sitemapURL is a location listed in a sitemap document.
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+// sitemapPaths collects the indexable paths of the route tree without duplicates.
+func (r *Route) sitemapPaths() []string {
+	paths := []string{}
+	for _, endpoint := range r.Endpoints() {
+		// The exact match marker only affects matching, the path itself is still concrete.
+		location := strings.TrimSuffix(endpoint.Path, "{$}")
+		if !endpoint.Metadata.NoIndex &&
+			(endpoint.Method == http.MethodGet || endpoint.Method == "") &&
+			strings.HasPrefix(location, "/") &&
+			!strings.Contains(location, "{") &&
+			!slices.Contains(paths, location) {
+			paths = append(paths, location)
+		}
+	}
+
+	return paths
+}
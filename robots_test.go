@@ -0,0 +1,81 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestNoIndex tests the X-Robots-Tag header of mounted routes
+func TestNoIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "indexable route", path: "/public", expected: ""},
+		{name: "noindex route", path: "/private", expected: "noindex"},
+		{name: "noindex inherited from parent", path: "/admin/users", expected: "noindex"},
+	}
+
+	route := r.NewRoute("").Add(
+		r.NewRoute("/public").Add(r.Get(handlerWriter("public"))),
+		r.NewRoute("/private").NoIndex().Add(r.Get(handlerWriter("private"))),
+		r.NewRoute("/admin").NoIndex().Add(
+			r.NewRoute("/users").Add(r.Get(handlerWriter("users"))),
+		),
+	)
+	mux := route.Mount()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, http.StatusOK)
+			assertCorrect(t, w.Header().Get("X-Robots-Tag"), tt.expected)
+		})
+	}
+}
+
+// TestSitemap tests the generated sitemap skips non indexable routes
+func TestSitemap(t *testing.T) {
+	route := r.NewRoute("")
+	route.Add(
+		r.NewRoute("/{$}").Add(r.Get(handlerWriter("root"))),
+		r.NewRoute("/about").Add(r.Get(handlerWriter("about")), r.Head(handlerWriter("about"))),
+		r.NewRoute("/contact").Add(r.Post(handlerWriter("contact")), r.All(handlerWriter("contact"))),
+		r.NewRoute("/posts/{id}").Add(r.Get(handlerWriter("post"))),
+		r.NewRoute("/admin").NoIndex().Add(
+			r.NewRoute("/users").Add(r.Get(handlerWriter("users"))),
+		),
+		r.NewRoute("/sitemap.xml").Add(r.Get(route.Sitemap("https://example.com/"))),
+	)
+
+	w := httptest.NewRecorder()
+	route.Mount().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/</loc>
+  </url>
+  <url>
+    <loc>https://example.com/about</loc>
+  </url>
+  <url>
+    <loc>https://example.com/contact</loc>
+  </url>
+  <url>
+    <loc>https://example.com/sitemap.xml</loc>
+  </url>
+</urlset>`
+
+	assertCorrect(t, w.Code, http.StatusOK)
+	assertCorrect(t, w.Header().Get("Content-Type"), "application/xml; charset=utf-8")
+	if w.Body.String() != want {
+		t.Errorf("Body = %q, want %q", w.Body.String(), want)
+	}
+}
@@ -0,0 +1,54 @@
+package routertest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/internal/recorder"
+)
+
+// AssertMatches checks that a request with the given method and path is served by the handler named
+// wantHandler once the route tree is mounted, failing the test otherwise. The handler is named as by
+// [simplerouter.FuncName], with or without its package name, like "main.getUser" or "getUser":
+//
+//	routertest.AssertMatches(t, router, http.MethodGet, "/api/users/1", "getUser")
+//
+// Requests reach the handler through the aliases of its route too.
+func AssertMatches(t testing.TB, route *simplerouter.Route, method, path, wantHandler string) {
+	t.Helper()
+	_, pattern := route.Mount().Handler(httptest.NewRequest(method, path, nil))
+	if pattern == "" {
+		t.Errorf("%s %s matches no route, want handler %s", method, path, wantHandler)
+		return
+	}
+
+	for _, endpoint := range route.Endpoints() {
+		for _, p := range append([]string{endpoint.Path}, endpoint.Aliases...) {
+			if strings.TrimSpace(endpoint.Method+" "+p) != strings.TrimSpace(pattern) {
+				continue
+			}
+			name := simplerouter.FuncName(endpoint.Handler)
+			if name != wantHandler && !strings.HasSuffix(name, "."+wantHandler) {
+				t.Errorf("%s %s matches %q served by %s, want handler %s", method, path, pattern, name, wantHandler)
+			}
+			return
+		}
+	}
+	t.Errorf("%s %s matches %q, which is not a route of the tree, want handler %s", method, path, pattern, wantHandler)
+}
+
+// AssertStatus serves a request with the given method and path with the mounted route tree, and checks
+// that it is answered with wantStatus, failing the test with the response body otherwise. Informational
+// responses, like the 103 Early Hints of the routes with preloaded assets, are not taken into account.
+func AssertStatus(t testing.TB, route *simplerouter.Route, method, path string, wantStatus int) {
+	t.Helper()
+	w := recorder.New()
+	route.Mount().ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	if w.Code != wantStatus {
+		t.Errorf("%s %s answered %d %s with body %q, want %d %s", method, path,
+			w.Code, http.StatusText(w.Code), w.Body.String(), wantStatus, http.StatusText(wantStatus))
+	}
+}
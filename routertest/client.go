@@ -0,0 +1,160 @@
+package routertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/internal/recorder"
+)
+
+// Client sends requests to a mounted route tree in-process and checks their responses with chainable
+// helpers, so handler tests fit in a single statement:
+//
+//	client := routertest.NewClient(router)
+//	var user User
+//	client.Post("/users").WithJSON(User{Name: "ada"}).Expect(t).Status(http.StatusCreated).JSON(&user)
+//
+// Requests are served in-process, without a network connection. The status code checked is the final
+// one, informational responses like the 103 Early Hints of the routes with preloaded assets are skipped.
+type Client struct {
+	handler http.Handler
+}
+
+// NewClient mounts the route tree and returns a Client sending requests to it.
+// Editing the tree afterwards does not affect the Client.
+func NewClient(route *simplerouter.Route) *Client {
+	return &Client{handler: route.Mount()}
+}
+
+// Request is a request being built by a [Client], sent with [Request.Expect].
+type Request struct {
+	handler http.Handler
+	req     *http.Request
+	err     error
+}
+
+// NewRequest returns a request with the given method and target, a path optionally followed by a query.
+func (c *Client) NewRequest(method, target string) *Request {
+	return &Request{handler: c.handler, req: httptest.NewRequest(method, target, nil)}
+}
+
+// Get returns a GET request for the target.
+func (c *Client) Get(target string) *Request {
+	return c.NewRequest(http.MethodGet, target)
+}
+
+// Head returns a HEAD request for the target.
+func (c *Client) Head(target string) *Request {
+	return c.NewRequest(http.MethodHead, target)
+}
+
+// Post returns a POST request for the target.
+func (c *Client) Post(target string) *Request {
+	return c.NewRequest(http.MethodPost, target)
+}
+
+// Put returns a PUT request for the target.
+func (c *Client) Put(target string) *Request {
+	return c.NewRequest(http.MethodPut, target)
+}
+
+// Patch returns a PATCH request for the target.
+func (c *Client) Patch(target string) *Request {
+	return c.NewRequest(http.MethodPatch, target)
+}
+
+// Delete returns a DELETE request for the target.
+func (c *Client) Delete(target string) *Request {
+	return c.NewRequest(http.MethodDelete, target)
+}
+
+// WithHeader sets a header of the request.
+func (r *Request) WithHeader(name, value string) *Request {
+	r.req.Header.Set(name, value)
+	return r
+}
+
+// WithBody sets the body of the request and its Content-Type header.
+func (r *Request) WithBody(contentType string, body []byte) *Request {
+	r.req.Body = io.NopCloser(bytes.NewReader(body))
+	r.req.ContentLength = int64(len(body))
+	r.req.Header.Set("Content-Type", contentType)
+	return r
+}
+
+// WithJSON sets the body of the request to the JSON encoding of v. Values that cannot be encoded
+// fail the test once the request is sent.
+func (r *Request) WithJSON(v any) *Request {
+	body, err := json.Marshal(v)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	return r.WithBody("application/json", body)
+}
+
+// Expect sends the request and returns its response, whose checks fail the test t.
+func (r *Request) Expect(t testing.TB) *Response {
+	t.Helper()
+	if r.err != nil {
+		t.Fatalf("%s %s: encoding request body: %v", r.req.Method, r.req.URL, r.err)
+	}
+	w := recorder.New()
+	r.handler.ServeHTTP(w, r.req)
+	return &Response{t: t, req: r.req, recorder: w}
+}
+
+// Response is the response to a request sent by a [Client], checked with chainable helpers
+// failing the test.
+type Response struct {
+	t        testing.TB
+	req      *http.Request
+	recorder *recorder.ResponseRecorder
+}
+
+// Status checks the status code of the response, reporting its body if it differs.
+func (r *Response) Status(want int) *Response {
+	r.t.Helper()
+	if r.recorder.Code != want {
+		r.t.Errorf("%s %s answered %d %s with body %q, want %d %s", r.req.Method, r.req.URL, r.recorder.Code,
+			http.StatusText(r.recorder.Code), r.recorder.Body.String(), want, http.StatusText(want))
+	}
+	return r
+}
+
+// Header checks the value of a header of the response.
+func (r *Response) Header(name, want string) *Response {
+	r.t.Helper()
+	if got := r.recorder.Header().Get(name); got != want {
+		r.t.Errorf("%s %s answered header %s %q, want %q", r.req.Method, r.req.URL, name, got, want)
+	}
+	return r
+}
+
+// Body checks the body of the response.
+func (r *Response) Body(want string) *Response {
+	r.t.Helper()
+	if got := r.recorder.Body.String(); got != want {
+		r.t.Errorf("%s %s answered body %q, want %q", r.req.Method, r.req.URL, got, want)
+	}
+	return r
+}
+
+// JSON decodes the JSON body of the response into out, failing the test at once if it cannot be decoded.
+func (r *Response) JSON(out any) *Response {
+	r.t.Helper()
+	if err := json.Unmarshal(r.recorder.Body.Bytes(), out); err != nil {
+		r.t.Fatalf("%s %s answered body %q, not decoded as JSON: %v", r.req.Method, r.req.URL, r.recorder.Body.String(), err)
+	}
+	return r
+}
+
+// Recorder returns the recorded response, for checks not covered by the helpers.
+func (r *Response) Recorder() *httptest.ResponseRecorder {
+	return r.recorder.ResponseRecorder
+}
@@ -0,0 +1,3 @@
+// Package routertest provides helpers to write compact tests of simplerouter route trees, serving
+// requests in-process without building the httptest plumbing by hand.
+package routertest
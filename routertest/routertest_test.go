@@ -0,0 +1,173 @@
+package routertest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/routertest"
+)
+
+func assertCorrect(t testing.TB, got, want any) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+// recordingTB is a testing.TB recording the failures instead of reporting them
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (t *recordingTB) Helper() {}
+
+func (t *recordingTB) Errorf(format string, args ...any) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingTB) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// record runs the assertion with a recordingTB, in its own goroutine as it exits on fatal failures
+func record(t testing.TB, assert func(t testing.TB)) []string {
+	recorder := &recordingTB{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert(recorder)
+	}()
+	<-done
+	return recorder.failures
+}
+
+func authMiddleware(next http.Handler) http.Handler { return next }
+
+func getUser(w http.ResponseWriter, req *http.Request) {}
+
+func listUsers(w http.ResponseWriter, req *http.Request) {}
+
+// TestAssertions tests that AssertMatches and AssertStatus fail only when the route tree does not match
+func TestAssertions(t *testing.T) {
+	tree := r.NewRoute("/api").Add(
+		r.NewRoute("/users").Add(r.Get(listUsers)),
+		r.NewRoute("/users/{id}").Alias("/people/{id}").Add(r.Get(getUser)),
+		r.NewRoute("/page").Preload("/app.css").Add(r.Get(listUsers)),
+	)
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+		fails  bool
+	}{
+		{name: "matching handler", assert: func(t testing.TB) { routertest.AssertMatches(t, tree, "GET", "/api/users/1", "getUser") }},
+		{name: "matching handler with package", assert: func(t testing.TB) {
+			routertest.AssertMatches(t, tree, "GET", "/api/users", "routertest_test.listUsers")
+		}},
+		{name: "matching alias", assert: func(t testing.TB) { routertest.AssertMatches(t, tree, "GET", "/api/people/1", "getUser") }},
+		{name: "other handler", assert: func(t testing.TB) { routertest.AssertMatches(t, tree, "GET", "/api/users", "getUser") }, fails: true},
+		{name: "no route", assert: func(t testing.TB) { routertest.AssertMatches(t, tree, "GET", "/api/orders", "getUser") }, fails: true},
+		{name: "other method", assert: func(t testing.TB) { routertest.AssertMatches(t, tree, "POST", "/api/users", "listUsers") }, fails: true},
+		{name: "expected status", assert: func(t testing.TB) {
+			routertest.AssertStatus(t, tree, "DELETE", "/api/users", http.StatusMethodNotAllowed)
+		}},
+		{name: "status after early hints", assert: func(t testing.TB) { routertest.AssertStatus(t, tree, "GET", "/api/page", http.StatusOK) }},
+		{name: "unexpected status", assert: func(t testing.TB) { routertest.AssertStatus(t, tree, "GET", "/api/orders", http.StatusOK) }, fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCorrect(t, len(record(t, tt.assert)) > 0, tt.fails)
+		})
+	}
+}
+
+// TestSnapshot tests that route snapshots are written on update and then fail when the endpoints change
+func TestSnapshot(t *testing.T) {
+	t.Chdir(t.TempDir())
+	tree := r.NewRoute("/api").Use(authMiddleware).Add(
+		r.NewRoute("/users").Add(r.Get(listUsers)),
+		r.NewRoute("/users/{id}").Alias("/people/{id}").Add(r.Get(getUser).Name("user")),
+	)
+
+	failures := record(t, func(t testing.TB) { routertest.Snapshot(t, tree) })
+	assertCorrect(t, len(failures), 1)
+	assertCorrect(t, strings.Contains(failures[0], "does not exist"), true)
+
+	t.Setenv("ROUTERTEST_UPDATE", "1")
+	assertCorrect(t, len(record(t, func(t testing.TB) { routertest.Snapshot(t, tree) })), 0)
+	data, err := os.ReadFile(filepath.Join("testdata", "TestSnapshot.routes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCorrect(t, string(data), "GET /api/users middlewares=routertest_test.authMiddleware handler=routertest_test.listUsers\n"+
+		"GET /api/users/{id} name=user aliases=/api/people/{id} middlewares=routertest_test.authMiddleware handler=routertest_test.getUser\n")
+
+	t.Setenv("ROUTERTEST_UPDATE", "")
+	assertCorrect(t, len(record(t, func(t testing.TB) { routertest.Snapshot(t, tree) })), 0)
+
+	tree.Add(r.NewRoute("/orders").Add(r.Post(listUsers)))
+	failures = record(t, func(t testing.TB) { routertest.Snapshot(t, tree) })
+	assertCorrect(t, len(failures), 1)
+	assertCorrect(t, strings.HasSuffix(failures[0], "\n+ POST /api/orders middlewares=routertest_test.authMiddleware handler=routertest_test.listUsers"), true)
+}
+
+// TestClient tests the requests sent and the responses checked by test clients
+func TestClient(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	client := routertest.NewClient(r.NewRoute("/users").Add(
+		r.Post(func(w http.ResponseWriter, req *http.Request) {
+			var u user
+			if err := json.NewDecoder(req.Body).Decode(&u); err != nil || req.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "invalid user", http.StatusBadRequest)
+				return
+			}
+			u.ID = 1
+			w.Header().Set("Location", "/users/1")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(u)
+		}),
+		r.Get(func(w http.ResponseWriter, req *http.Request) { w.Write([]byte(req.Header.Get("Accept"))) }),
+		r.NewRoute("/page").Preload("/app.css").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		})),
+	))
+
+	var created user
+	client.Post("/users").WithJSON(user{Name: "ada"}).Expect(t).
+		Status(http.StatusCreated).Header("Location", "/users/1").JSON(&created)
+	assertCorrect(t, created, user{ID: 1, Name: "ada"})
+	client.Get("/users").WithHeader("Accept", "text/plain").Expect(t).Status(http.StatusOK).Body("text/plain")
+	client.Get("/users/page").Expect(t).Status(http.StatusAccepted).Header("Link", "</app.css>; rel=preload; as=style")
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+	}{
+		{name: "status", assert: func(t testing.TB) {
+			client.Post("/users").WithBody("text/plain", []byte("ada")).Expect(t).Status(http.StatusCreated)
+		}},
+		{name: "header", assert: func(t testing.TB) { client.Get("/users").Expect(t).Header("Location", "/users/1") }},
+		{name: "body", assert: func(t testing.TB) { client.Get("/users").Expect(t).Body("text/html") }},
+		{name: "JSON", assert: func(t testing.TB) { client.Get("/users").Expect(t).JSON(&created) }},
+		{name: "request JSON", assert: func(t testing.TB) { client.Post("/users").WithJSON(func() {}).Expect(t) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCorrect(t, len(record(t, tt.assert)), 1)
+		})
+	}
+}
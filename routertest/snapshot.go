@@ -0,0 +1,96 @@
+package routertest
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter"
+)
+
+// Snapshot compares the endpoints of the route tree with the golden file
+// "testdata/<test name>.routes", failing the test with the endpoints added and removed if they differ,
+// to catch accidental changes of the API surface:
+//
+//	func TestRoutes(t *testing.T) {
+//		routertest.Snapshot(t, newRouter())
+//	}
+//
+// Each endpoint is a line with its method, path, name, aliases, middlewares and handler, named with
+// [simplerouter.FuncName], sorted so the file does not depend on the order of the Add calls.
+// Running the tests with the ROUTERTEST_UPDATE environment variable set to 1 writes the golden files
+// instead, which is needed to create them too.
+func Snapshot(t testing.TB, route *simplerouter.Route) {
+	t.Helper()
+	got := snapshotLines(route)
+	name := filepath.Join("testdata", strings.ReplaceAll(t.Name(), "/", "_")+".routes")
+
+	if os.Getenv("ROUTERTEST_UPDATE") == "1" {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatalf("writing route snapshot: %v", err)
+		}
+		if err := os.WriteFile(name, []byte(strings.Join(got, "\n")+"\n"), 0o644); err != nil {
+			t.Fatalf("writing route snapshot: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("route snapshot %s does not exist, run the tests with ROUTERTEST_UPDATE=1 to create it", name)
+	}
+	if err != nil {
+		t.Fatalf("reading route snapshot: %v", err)
+	}
+	want := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+
+	var diff []string
+	for _, line := range want {
+		if !slices.Contains(got, line) {
+			diff = append(diff, "- "+line)
+		}
+	}
+	for _, line := range got {
+		if !slices.Contains(want, line) {
+			diff = append(diff, "+ "+line)
+		}
+	}
+	if len(diff) > 0 {
+		t.Errorf("routes differ from the snapshot %s, run the tests with ROUTERTEST_UPDATE=1 if intended:\n%s",
+			name, strings.Join(diff, "\n"))
+	}
+}
+
+// snapshotLines returns the sorted lines describing the endpoints of the route tree.
+func snapshotLines(route *simplerouter.Route) []string {
+	endpoints := route.Endpoints()
+	lines := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		method := endpoint.Method
+		if method == "" {
+			method = "ALL"
+		}
+		fields := []string{method, endpoint.Path}
+		if endpoint.Metadata.Name != "" {
+			fields = append(fields, "name="+endpoint.Metadata.Name)
+		}
+		if len(endpoint.Aliases) > 0 {
+			fields = append(fields, "aliases="+strings.Join(endpoint.Aliases, ","))
+		}
+		if len(endpoint.Middlewares) > 0 {
+			middlewares := make([]string, len(endpoint.Middlewares))
+			for i, mw := range endpoint.Middlewares {
+				middlewares[i] = simplerouter.FuncName(mw)
+			}
+			fields = append(fields, "middlewares="+strings.Join(middlewares, ","))
+		}
+		fields = append(fields, "handler="+simplerouter.FuncName(endpoint.Handler))
+		lines = append(lines, strings.Join(fields, " "))
+	}
+	slices.Sort(lines)
+	return lines
+}
@@ -0,0 +1,139 @@
+package simplerouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/carlos-el/simplerouter/internal/recorder"
+)
+
+// Example is a sample request of a route and the status code it is expected to get.
+type Example struct {
+	// Path is the request path, required when the path of the route has wildcards.
+	// If empty, the full path of the route is used.
+	Path string
+	// Request is the request body. Strings and byte slices are sent as is, other values are encoded as JSON.
+	Request any
+	// Status is the expected status code of the response.
+	Status int
+}
+
+// ResponseExample is a sample response of a route.
+type ResponseExample struct {
+	// Status is the status code of the response.
+	Status int
+	// Body is the response body. Strings are documented as plain text, other values as JSON.
+	Body any
+}
+
+// Example adds a sample response of the route with the given status code and body, documented by
+// [Route.OpenAPI] and the documentation routes built from it. Unlike the examples of [Route.Examples],
+// responses are not requested by [Route.SelfTest], as they do not describe the request getting them.
+func (r *Route) Example(status int, body any) *Route {
+	r.Metadata.Responses = append(r.Metadata.Responses, ResponseExample{Status: status, Body: body})
+	return r
+}
+
+// Examples adds sample requests to the route, used to document it and to check it with [Route.SelfTest].
+func (r *Route) Examples(examples ...Example) *Route {
+	r.Metadata.Examples = append(r.Metadata.Examples, examples...)
+	return r
+}
+
+// SelfTest mounts the route tree and serves synthetic requests to it in-process, to catch wiring errors
+// before the service starts listening or in CI. Endpoints with examples get their example requests,
+// which must be answered with the example status code. Endpoints without examples serving GET or HEAD
+// requests on paths without wildcards get a plain request, which must not be answered with a 5xx status code.
+// Other endpoints are not requested, so handlers with side effects only run when examples are declared.
+// It returns an error joining the failure of every endpoint, nil if all of them passed.
+// Trees that cannot be mounted are not requested, the problems reported by [Route.Validate] are returned instead.
+func (r *Route) SelfTest(ctx context.Context) (err error) {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("mount: %v", v)
+		}
+	}()
+	mux := r.Mount()
+
+	errs := []error{}
+	for _, endpoint := range r.Endpoints() {
+		method := endpoint.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		examples := endpoint.Metadata.Examples
+		if len(examples) == 0 {
+			if (method != http.MethodGet && method != http.MethodHead) || strings.Contains(endpoint.Path, "{") {
+				continue
+			}
+			examples = []Example{{}}
+		}
+
+		for _, example := range examples {
+			if err := ctx.Err(); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+
+			path := example.Path
+			if path == "" {
+				path = strings.TrimSuffix(endpoint.Path, "{$}")
+			}
+			if err := selfTestRequest(ctx, mux, method, path, example); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", method, path, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// selfTestRequest serves the example request to mux and checks the status code of the response.
+func selfTestRequest(ctx context.Context, mux http.Handler, method, path string, example Example) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v", v)
+		}
+	}()
+
+	var body io.Reader
+	contentType := ""
+	switch v := example.Request.(type) {
+	case nil:
+	case string:
+		body = strings.NewReader(v)
+	case []byte:
+		body = bytes.NewReader(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+		body, contentType = bytes.NewReader(encoded), "application/json"
+	}
+
+	req := httptest.NewRequestWithContext(ctx, method, path, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	w := recorder.New()
+	mux.ServeHTTP(w, req)
+
+	switch {
+	case example.Status != 0 && w.Code != example.Status:
+		return fmt.Errorf("got status %d, want %d", w.Code, example.Status)
+	case example.Status == 0 && w.Code >= 500:
+		return fmt.Errorf("got status %d", w.Code)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package simplerouter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// echoStatus creates a handler answering with the status code written in the request body
+func echoStatus(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	switch string(body) {
+	case "", `{"name":"john"}`:
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// TestSelfTest tests the failures reported by the self test of a tree
+func TestSelfTest(t *testing.T) {
+	tests := []struct {
+		name             string
+		route            *r.Route
+		expectedFailures []string
+	}{
+		{
+			name: "passing tree",
+			route: r.NewRoute("/api").Add(
+				r.NewRoute("/users").Add(
+					r.Get(handlerWriter("users")),
+					r.Post(echoStatus).Examples(
+						r.Example{Request: map[string]string{"name": "john"}, Status: http.StatusCreated},
+						r.Example{Request: "invalid", Status: http.StatusBadRequest},
+					),
+				),
+				r.NewRoute("/users/{id}").Add(
+					r.Get(handlerWriter("user")).Examples(r.Example{Path: "/api/users/1", Status: http.StatusOK}),
+					r.Delete(func(w http.ResponseWriter, req *http.Request) { panic("not probed") }),
+				),
+				r.NewRoute("/page").Preload("/app.css").Add(
+					r.Get(handlerWriter("page")).Examples(r.Example{Status: http.StatusOK}),
+				),
+			),
+			expectedFailures: nil,
+		},
+		{
+			name: "failing endpoints",
+			route: r.NewRoute("/api").Add(
+				r.NewRoute("/broken").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				})),
+				r.NewRoute("/panic").Add(r.All(func(w http.ResponseWriter, req *http.Request) {
+					panic("nil dependency")
+				})),
+				r.NewRoute("/users").Add(
+					r.Post(echoStatus).Examples(r.Example{Request: "invalid", Status: http.StatusCreated}),
+				),
+				r.NewRoute("/hinted").Preload("/app.js").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+					w.WriteHeader(http.StatusInternalServerError)
+				})),
+			),
+			expectedFailures: []string{
+				"GET /api/broken: got status 500",
+				"GET /api/hinted: got status 500",
+				"GET /api/panic: panic: nil dependency",
+				"POST /api/users: got status 400, want 201",
+			},
+		},
+		{
+			name: "mount failure",
+			route: r.NewRoute("/api").Add(
+				r.Get(handlerWriter("a")),
+				r.Get(handlerWriter("b")),
+			),
+			expectedFailures: []string{"GET /api: conflict: conflicts with \"GET /api\""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.route.SelfTest(context.Background())
+
+			if tt.expectedFailures == nil {
+				if err != nil {
+					t.Fatalf("SelfTest() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("SelfTest() = nil, want an error")
+			}
+			for _, failure := range tt.expectedFailures {
+				if !strings.Contains(err.Error(), failure) {
+					t.Errorf("SelfTest() = %q, want it to contain %q", err.Error(), failure)
+				}
+			}
+		})
+	}
+}
+
+// TestSelfTestWithCanceledContext tests that the self test stops when its context is canceled
+func TestSelfTestWithCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := r.NewRoute("/").Add(r.Get(handlerWriter("ok"))).SelfTest(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("SelfTest() = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,199 @@
+package simplerouter
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ServerOption configures the server started by [ListenAndServe] and [ListenAndServeTLS].
+type ServerOption func(*serverConfig)
+
+// serverConfig holds the options of a server.
+type serverConfig struct {
+	shutdownTimeout time.Duration
+	// tlsConfig is the TLS configuration of the server, see [WithTLSConfig].
+	tlsConfig     *tls.Config
+	certFile      string
+	keyFile       string
+	tlsConfigured bool
+	h2c           bool
+	// socket is the path of the unix socket to listen on instead of the address, see [WithUnixSocket].
+	socket     string
+	socketPerm os.FileMode
+}
+
+// WithShutdownTimeout sets how long the server waits for the active requests to finish once its context
+// is done, 10 seconds by default. Requests still running after d are interrupted by closing their connections.
+func WithShutdownTimeout(d time.Duration) ServerOption {
+	if d <= 0 {
+		panic("d parameter must be greater than zero")
+	}
+	return func(c *serverConfig) { c.shutdownTimeout = d }
+}
+
+// WithH2C serves HTTP/2 without TLS (h2c) along with HTTP/1, for the clients that speak HTTP/2 in
+// cleartext, like gRPC clients, internal load balancers and proxies terminating TLS in front of the server.
+// Clients must start the connection with the HTTP/2 preface, as the HTTP/1 upgrade mechanism is not supported.
+func WithH2C() ServerOption {
+	return func(c *serverConfig) { c.h2c = true }
+}
+
+// WithUnixSocket listens on the unix domain socket at path instead of the TCP address, for sidecars
+// and local clients, with the given permissions, like 0o660 to restrict the clients to the group of
+// the server. A socket file left by a previous server that did not shut down properly is replaced,
+// and the socket file is removed once the server is shut down. The address is ignored and can be empty.
+// The socket is created with its permissions in a private directory next to path, then moved to path,
+// so the directory must be writable by the server, and its path a few bytes shorter than the limit of
+// unix socket paths, about 100 bytes.
+func WithUnixSocket(path string, perm os.FileMode) ServerOption {
+	if path == "" {
+		panic("path parameter cannot be empty")
+	}
+	return func(c *serverConfig) { c.socket, c.socketPerm = path, perm }
+}
+
+// WithTLSConfig serves HTTPS with the TLS configuration config, like one getting its certificates from
+// an ACME client with GetCertificate (see the autotls module) or verifying client certificates for
+// [middleware.ClientCert]. With [ListenAndServeTLS], the certificate files are added to config.
+// TLS 1.2 is required if config sets no MinVersion. HTTP/2 is enabled.
+func WithTLSConfig(config *tls.Config) ServerOption {
+	if config == nil {
+		panic("config parameter cannot be nil")
+	}
+	return func(c *serverConfig) { c.tlsConfig = config }
+}
+
+// ListenAndServe serves handler on the TCP address addr until ctx is done, then shuts the server down
+// gracefully, waiting for the active requests to finish, see [WithShutdownTimeout]. It returns nil once
+// the server is shut down, or the error that stopped it otherwise:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+//	defer stop()
+//	log.Fatal(simplerouter.ListenAndServe(ctx, ":8080", router.Mount()))
+//
+// Plain HTTP is served unless the options enable TLS, like [WithTLSConfig].
+// The server sets a ReadHeaderTimeout of 10 seconds, to protect it from slow clients.
+func ListenAndServe(ctx context.Context, addr string, handler http.Handler, opts ...ServerOption) error {
+	config := &serverConfig{shutdownTimeout: 10 * time.Second}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config.serve(ctx, addr, handler)
+}
+
+// ListenAndServeTLS does the same as [ListenAndServe], but serves HTTPS with the certificate and private key
+// of the PEM encoded certFile and keyFile. The certificate file should contain the intermediate certificates
+// after the certificate of the server. HTTP/2 is enabled.
+func ListenAndServeTLS(ctx context.Context, addr, certFile, keyFile string, handler http.Handler, opts ...ServerOption) error {
+	config := &serverConfig{shutdownTimeout: 10 * time.Second, certFile: certFile, keyFile: keyFile, tlsConfigured: true}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config.serve(ctx, addr, handler)
+}
+
+// serve runs the server configured by c.
+func (c *serverConfig) serve(ctx context.Context, addr string, handler http.Handler) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if c.h2c {
+		server.Protocols = &http.Protocols{}
+		server.Protocols.SetHTTP1(true)
+		server.Protocols.SetHTTP2(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
+	}
+
+	if c.tlsConfig != nil {
+		server.TLSConfig = c.tlsConfig.Clone()
+		if server.TLSConfig.MinVersion == 0 {
+			server.TLSConfig.MinVersion = tls.VersionTLS12
+		}
+		c.tlsConfigured = true
+	}
+
+	listener, err := c.listen(addr)
+	if err != nil {
+		return err
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		if c.tlsConfigured {
+			errs <- server.ServeTLS(listener, c.certFile, c.keyFile)
+		} else {
+			errs <- server.Serve(listener)
+		}
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		server.Close()
+		return err
+	}
+	return nil
+}
+
+// listen returns the listener of the server, on the TCP address or on the unix socket of the options.
+func (c *serverConfig) listen(addr string) (net.Listener, error) {
+	if c.socket == "" {
+		return net.Listen("tcp", addr)
+	}
+
+	if info, err := os.Stat(c.socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := os.Remove(c.socket); err != nil {
+			return nil, err
+		}
+	}
+	// The socket is created in a private directory, and moved to its path once its permissions are set,
+	// so clients cannot connect to it with the permissions given by the umask in between.
+	dir, err := os.MkdirTemp(filepath.Dir(c.socket), ".socket")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "s")
+	listener, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, c.socketPerm); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, c.socket); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return &unixListener{Listener: listener, path: c.socket}, nil
+}
+
+// unixListener is a unix socket listener removing the socket file at path when it is closed.
+type unixListener struct {
+	net.Listener
+	path string
+	once sync.Once
+}
+
+// Close closes the listener and removes its socket file.
+func (l *unixListener) Close() error {
+	err := l.Listener.Close()
+	l.once.Do(func() { os.Remove(l.path) })
+	return err
+}
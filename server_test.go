@@ -0,0 +1,221 @@
+package simplerouter_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// freeAddr returns a local TCP address free to listen on
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// getEventually sends GET requests to url with client until the server answers, returning the response body
+func getEventually(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	for range 50 {
+		resp, err := client.Get(url)
+		if err == nil {
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			return string(body)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("GET %s never succeeded", url)
+	return ""
+}
+
+// writeCertificate writes a self-signed certificate for 127.0.0.1 and its key to PEM files in dir
+func writeCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return certFile, keyFile
+}
+
+// TestListenAndServe tests that the server serves the handler until its context is done
+func TestListenAndServe(t *testing.T) {
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- r.ListenAndServe(ctx, addr, r.NewRoute("/").Add(r.Get(handlerWriter("served"))).Mount())
+	}()
+
+	assertCorrect(t, getEventually(t, http.DefaultClient, "http://"+addr+"/"), "served")
+
+	cancel()
+	select {
+	case err := <-errs:
+		assertCorrect(t, err, nil)
+	case <-time.After(5 * time.Second):
+		t.Fatal("ListenAndServe() did not return after its context was done")
+	}
+}
+
+// TestListenAndServeTLS tests that the server serves HTTPS with the certificate files
+func TestListenAndServeTLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t, t.TempDir())
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.ListenAndServeTLS(ctx, addr, certFile, keyFile, r.NewRoute("/").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(req.Proto))
+	})).Mount())
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		ForceAttemptHTTP2: true,
+	}}
+	assertCorrect(t, getEventually(t, client, "https://"+addr+"/"), "HTTP/2.0")
+}
+
+// TestListenAndServeWithH2C tests that the server serves HTTP/2 and HTTP/1 requests without TLS
+func TestListenAndServeWithH2C(t *testing.T) {
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.ListenAndServe(ctx, addr, r.NewRoute("/").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(req.Proto))
+	})).Mount(), r.WithH2C())
+
+	protocols := &http.Protocols{}
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+	assertCorrect(t, getEventually(t, client, "http://"+addr+"/"), "HTTP/2.0")
+	assertCorrect(t, getEventually(t, http.DefaultClient, "http://"+addr+"/"), "HTTP/1.1")
+}
+
+// TestListenAndServeWithUnixSocket tests that the server serves the handler on a unix socket,
+// replacing stale socket files and removing the socket file on shutdown
+func TestListenAndServeWithUnixSocket(t *testing.T) {
+	// Unix socket paths are limited to about 100 bytes, so the test temporary directory may be too long.
+	dir, err := os.MkdirTemp("", "sr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "server.sock")
+
+	stale, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		errs <- r.ListenAndServe(ctx, "", r.NewRoute("/").Add(r.Get(handlerWriter("served"))).Mount(), r.WithUnixSocket(socket, 0o660))
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	assertCorrect(t, getEventually(t, client, "http://unix/"), "served")
+	info, err := os.Stat(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCorrect(t, info.Mode().Perm(), os.FileMode(0o660))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertCorrect(t, len(entries), 1)
+
+	cancel()
+	assertCorrect(t, <-errs, nil)
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("socket file still exists after shutdown: %v", err)
+	}
+}
+
+// TestListenAndServeWithTLSConfig tests that the server serves HTTPS with the certificates of the TLS configuration
+func TestListenAndServeWithTLSConfig(t *testing.T) {
+	certificate, err := tls.LoadX509KeyPair(writeCertificate(t, t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := freeAddr(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config := &tls.Config{GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return &certificate, nil }}
+	go r.ListenAndServe(ctx, addr, r.NewRoute("/").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(req.Proto))
+	})).Mount(), r.WithTLSConfig(config))
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true, MaxVersion: tls.VersionTLS11},
+		ForceAttemptHTTP2: true,
+	}}
+	if _, err := client.Get("https://" + addr + "/"); err == nil {
+		t.Error("GET with TLS 1.1 succeeded, want the server to require TLS 1.2")
+	}
+	client.Transport.(*http.Transport).TLSClientConfig.MaxVersion = 0
+	assertCorrect(t, getEventually(t, client, "https://"+addr+"/"), "HTTP/2.0")
+}
+
+// TestServerOptionsWithInvalidParameters tests that invalid server options cause a panic
+func TestServerOptionsWithInvalidParameters(t *testing.T) {
+	tests := map[string]func(){
+		"WithTLSConfig":       func() { r.WithTLSConfig(nil) },
+		"WithShutdownTimeout": func() { r.WithShutdownTimeout(0) },
+		"WithUnixSocket":      func() { r.WithUnixSocket("", 0o600) },
+	}
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s() did not panic", name)
+				}
+			}()
+			fn()
+		})
+	}
+}
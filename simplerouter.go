@@ -3,7 +3,12 @@
 package simplerouter
 
 import (
+	"errors"
 	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/carlos-el/simplerouter/middleware"
 )
 
 // Middleware is any function that takes an http.Handler and returns an http.Handler.
@@ -23,141 +28,403 @@ func applyMiddleware(mws ...Middleware) Middleware {
 }
 
 // Route represents a route in the router.
-// It stores the information about the route's path, middlewares, child routes, handler, and HTTP method.
+// It stores the information about the route's path and aliases, middlewares, child routes, handler, HTTP method, and metadata.
 type Route struct {
 	Path        string
+	Aliases     []string
 	Middlewares []Middleware
 	Routes      []*Route
 	Handler     http.HandlerFunc
 	Method      string
+	Metadata    Metadata
+
+	// sources stores where each middleware was attached, see [EnableProvenance].
+	sources []string
+	// buildEvents stores the construction calls made on the route, see [EnableBuildLog].
+	buildEvents []BuildEvent
+	// frozen reports whether the route was frozen by mounting it, see [WithFreeze].
+	frozen bool
 }
 
 // NewRoute creates a new Route with the given path path.
 // It initializes the route with an empty list of middlewares and child routes.
 func NewRoute(path string) *Route {
-	return &Route{
+	return (&Route{
 		Path:        path,
+		Aliases:     []string{},
 		Middlewares: []Middleware{},
 		Routes:      []*Route{},
 		Handler:     nil,
 		Method:      "",
-	}
+		Metadata:    Metadata{},
+	}).recordBuild("NewRoute", func() string { return "" })
 }
 
+// ErrNilMiddleware is returned by [Route.TryUse] when the middlewares contain a nil middleware.
+var ErrNilMiddleware = errors.New("middlewares parameter cannot contain nil middlewares")
+
+// ErrNilRoute is returned by [Route.TryAdd] when the routes contain a nil route.
+var ErrNilRoute = errors.New("routes parameter cannot contain nil routes")
+
+// ErrRouteCycle is returned by [Route.TryAdd] when a route would become its own descendant.
+var ErrRouteCycle = errors.New("routes parameter cannot contain the route or its ancestors")
+
+// ErrFrozenRoute is returned by [Route.TryUse], [Route.TryAdd] and [Route.Merge] when the route belongs
+// to a tree frozen by mounting it with [WithFreeze].
+var ErrFrozenRoute = errors.New("route cannot be modified after mounting it with WithFreeze")
+
 // Use adds middlewares that execute before the route's handlers or child routes.
+// It panics if the middlewares contain a nil middleware or the route is frozen, see [Route.TryUse].
 func (r *Route) Use(middlewares ...Middleware) *Route {
+	if err := r.TryUse(middlewares...); err != nil {
+		panic(err.Error())
+	}
+	return r
+}
+
+// TryUse does the same as [Route.Use], but returns [ErrNilMiddleware] or [ErrFrozenRoute] instead of panicking,
+// for programs assembling trees from dynamic input. No middleware is added if it fails.
+func (r *Route) TryUse(middlewares ...Middleware) error {
+	if r.frozen {
+		return ErrFrozenRoute
+	}
 	for _, mw := range middlewares {
 		if mw == nil {
-			panic("middlewares parameter cannot contain nil middlewares")
+			return ErrNilMiddleware
 		}
 	}
+	r.recordSources(len(middlewares))
+	r.recordBuild("Use", func() string {
+		names := make([]string, len(middlewares))
+		for i, mw := range middlewares {
+			names[i] = FuncName(mw)
+		}
+		return strings.Join(names, ", ")
+	})
 	r.Middlewares = append(r.Middlewares, middlewares...)
+	return nil
+}
+
+// UsePre adds middlewares at the front of the route's middlewares, so they execute before the ones
+// already added, like a tracing middleware that must run outermost when added after the rest of the tree
+// was built. Middlewares of parent routes still execute before them.
+// It panics if the middlewares contain a nil middleware or the route is frozen, see [WithFreeze].
+func (r *Route) UsePre(middlewares ...Middleware) *Route {
+	r.checkFrozen()
+	for _, mw := range middlewares {
+		if mw == nil {
+			panic(ErrNilMiddleware.Error())
+		}
+	}
+	r.recordSources(len(middlewares))
+	if len(r.sources) > 0 {
+		n := len(r.sources) - len(middlewares)
+		r.sources = append(slices.Clone(r.sources[n:]), r.sources[:n]...)
+	}
+	r.recordBuild("UsePre", func() string {
+		names := make([]string, len(middlewares))
+		for i, mw := range middlewares {
+			names[i] = FuncName(mw)
+		}
+		return strings.Join(names, ", ")
+	})
+	r.Middlewares = append(slices.Clone(middlewares), r.Middlewares...)
 	return r
 }
 
 // Add adds child routes to the current route.
+// It panics if the routes contain a nil route, would create a cycle or the route is frozen, see [Route.TryAdd].
 func (r *Route) Add(routes ...*Route) *Route {
+	if err := r.TryAdd(routes...); err != nil {
+		panic(err.Error())
+	}
+	return r
+}
+
+// TryAdd does the same as [Route.Add], but returns [ErrNilRoute], [ErrRouteCycle] or [ErrFrozenRoute] instead of panicking,
+// for programs assembling trees from dynamic input. No route is added if it fails.
+func (r *Route) TryAdd(routes ...*Route) error {
+	if r.frozen {
+		return ErrFrozenRoute
+	}
 	for _, route := range routes {
 		if route == nil {
-			panic("routes parameter cannot contain nil routes")
+			return ErrNilRoute
+		}
+		if route.contains(r) {
+			return ErrRouteCycle
 		}
 	}
+	r.recordBuild("Add", func() string {
+		descriptions := make([]string, len(routes))
+		for i, route := range routes {
+			descriptions[i] = route.describe()
+		}
+		return strings.Join(descriptions, ", ")
+	})
 	r.Routes = append(r.Routes, routes...)
-	return r
+	return nil
+}
+
+// contains reports whether target is the route or one of its descendants.
+func (r *Route) contains(target *Route) bool {
+	if r == target {
+		return true
+	}
+	for _, route := range r.Routes {
+		if route.contains(target) {
+			return true
+		}
+	}
+	return false
 }
 
 // Returns a Route with the handler associated to the GET http method and no path.
 func Get(handler http.HandlerFunc) *Route {
-	return &Route{Handler: handler, Method: http.MethodGet}
+	return (&Route{Handler: handler, Method: http.MethodGet}).recordBuild("Get", handlerName(handler))
 }
 
 // Returns a Route with the handler associated to the HEAD http method and no path.
 func Head(handler http.HandlerFunc) *Route {
-	return &Route{Handler: handler, Method: http.MethodHead}
+	return (&Route{Handler: handler, Method: http.MethodHead}).recordBuild("Head", handlerName(handler))
 }
 
 // Returns a Route with the handler associated to the POST http method and no path.
 func Post(handler http.HandlerFunc) *Route {
-	return &Route{Handler: handler, Method: http.MethodPost}
+	return (&Route{Handler: handler, Method: http.MethodPost}).recordBuild("Post", handlerName(handler))
 }
 
 // Returns a Route with the handler associated to the PUT http method and no path.
 func Put(handler http.HandlerFunc) *Route {
-	return &Route{Handler: handler, Method: http.MethodPut}
+	return (&Route{Handler: handler, Method: http.MethodPut}).recordBuild("Put", handlerName(handler))
 }
 
 // Returns a Route with the handler associated to the PATCH http method and no path.
 func Patch(handler http.HandlerFunc) *Route {
-	return &Route{Handler: handler, Method: http.MethodPatch}
+	return (&Route{Handler: handler, Method: http.MethodPatch}).recordBuild("Patch", handlerName(handler))
 }
 
 // Returns a Route with the handler associated to the DELETE http method and no path.
 func Delete(handler http.HandlerFunc) *Route {
-	return &Route{Handler: handler, Method: http.MethodDelete}
+	return (&Route{Handler: handler, Method: http.MethodDelete}).recordBuild("Delete", handlerName(handler))
 }
 
 // Returns a Route with the handler associated to the CONNECT http method and no path.
 func Connect(handler http.HandlerFunc) *Route {
-	return &Route{Handler: handler, Method: http.MethodConnect}
+	return (&Route{Handler: handler, Method: http.MethodConnect}).recordBuild("Connect", handlerName(handler))
 }
 
 // Returns a Route with the handler associated to the OPTIONS http method and no path.
 func Options(handler http.HandlerFunc) *Route {
-	return &Route{Handler: handler, Method: http.MethodOptions}
+	return (&Route{Handler: handler, Method: http.MethodOptions}).recordBuild("Options", handlerName(handler))
 }
 
 // Returns a Route with the handler associated to the TRACE http method and no path.
 func Trace(handler http.HandlerFunc) *Route {
-	return &Route{Handler: handler, Method: http.MethodTrace}
+	return (&Route{Handler: handler, Method: http.MethodTrace}).recordBuild("Trace", handlerName(handler))
 }
 
 // Returns a Route with the handler associated and no path or method.
 // This can be used to create a route that matches all methods not explicitly defined (as per the standard lib behavior).
 func All(handler http.HandlerFunc) *Route {
-	return &Route{Handler: handler, Method: ""}
+	return (&Route{Handler: handler, Method: ""}).recordBuild("All", handlerName(handler))
+}
+
+// Methods returns a Route with no path registering the handler for each of the given methods, for handlers
+// that legitimately serve several methods, instead of repeating Get(h), Put(h) and so on.
+// The returned route holds a child route for each method, so the metadata set on it applies to all of them,
+// except for the name, summary, description and examples, which only describe the route they are set on.
+// It panics if no method is given or a method is empty.
+func Methods(handler http.HandlerFunc, methods ...string) *Route {
+	if len(methods) == 0 {
+		panic("methods parameter cannot be empty")
+	}
+	route := NewRoute("")
+	for _, method := range methods {
+		if method == "" {
+			panic("methods parameter cannot contain empty methods")
+		}
+		route.Add((&Route{Handler: handler, Method: method}).recordBuild("Methods", handlerName(handler)))
+	}
+	return route
+}
+
+// registrar registers the handlers of a mounted route tree for their patterns, like http.ServeMux.
+type registrar interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// mounter holds the state shared while mounting a route tree.
+type mounter struct {
+	router registrar
+	walkFn WalkFn
+	// basePath prefixes the patterns of the tree, see [WithBasePath].
+	basePath string
+	// paths maps the route names of the tree to their full paths, including the base path.
+	paths map[string]string
+	// names maps the full paths of the named routes without handlers to their names, see [RouteInfo].
+	names map[string]string
+	// locales maps the localized full paths of the tree to their locales, see [RouteInfo].
+	locales map[string]string
+	// patterns are the patterns of the endpoints to register, all of them if nil.
+	patterns map[string]bool
+	// handlers is the table of the final handlers of the endpoints, with their middleware chains
+	// already applied, registered into the router once the whole tree is inspected.
+	handlers []mountedHandler
+}
+
+// mountedHandler is the final handler of an endpoint registered for a pattern.
+type mountedHandler struct {
+	pattern  string
+	handler  http.Handler
+	priority int
+}
+
+// newMounter returns a mounter registering the route tree r into router under basePath.
+func newMounter(r *Route, router registrar, walkFn WalkFn, basePath string) *mounter {
+	names := map[string]string{}
+	r.pathNames(basePath, names)
+	locales := map[string]string{}
+	r.pathLocales(map[string]string{basePath: ""}, locales)
+	return &mounter{
+		router:   router,
+		walkFn:   walkFn,
+		basePath: basePath,
+		paths:    r.namedPaths(basePath),
+		names:    names,
+		locales:  locales,
+	}
+}
+
+// handle adds the handler of the pattern to the table of the mounter, with the given priority.
+func (m *mounter) handle(pattern string, handler http.Handler, priority int) {
+	m.handlers = append(m.handlers, mountedHandler{pattern: pattern, handler: handler, priority: priority})
+}
+
+// includes reports whether the endpoint with the given pattern is registered by the mounter.
+func (m *mounter) includes(pattern string) bool {
+	return m.patterns == nil || m.patterns[pattern]
+}
+
+// mount registers the route tree into a new http.ServeMux with the given options.
+func (r *Route) mount(opts ...MountOption) *http.ServeMux {
+	mux := http.NewServeMux()
+	r.mountInto(mux, opts...)
+	return mux
+}
+
+// mountInto registers the route tree into router with the given options.
+func (r *Route) mountInto(router registrar, opts ...MountOption) {
+	config := &mountConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.validate {
+		if err := r.Validate(); err != nil {
+			panic(err.Error())
+		}
+	}
+
+	m := newMounter(r, router, config.walkFn, config.basePath)
+	r.inspectRoute([]string{config.basePath}, []Middleware{}, Metadata{}, m)
+	m.registerPreflights(r.Endpoints())
+	last := m.register()
+	if config.notFound != nil {
+		last.Handle("/", config.notFound)
+	}
+	if config.freeze {
+		r.freeze()
+	}
 }
 
 // inspectRoute recursively inspects the route provided and its child routes.
-// It applies the paths, middlewares and handlers to the provided http.ServeMux router.
-// If a WalkFn is provided, it will be called for each route inspected.
+// It builds the final handler of each endpoint once, applying the paths, middlewares and metadata,
+// and adds it to the table of handlers of the mounter.
+// The paths are the full paths of the parent route, the main one followed by the ones coming from aliases.
+// If the mounter has a WalkFn, it will be called once for each route inspected, with the main path.
 func (r *Route) inspectRoute(
-	path string,
+	paths []string,
 	middlewares []Middleware,
-	router *http.ServeMux,
-	walkFn WalkFn,
+	metadata Metadata,
+	m *mounter,
 ) {
-	chainedPath := path + r.Path
-	chainedMiddleware := append(middlewares, r.Middlewares...)
+	chainedPaths := r.chainPaths(paths)
+	chainedMiddleware := slices.Concat(middlewares, r.Middlewares)
+	chainedMetadata := r.Metadata.inherit(metadata)
 
-	if walkFn != nil {
-		walkFn(r, path, middlewares)
+	if m.walkFn != nil {
+		m.walkFn(r, paths[0], middlewares)
 	}
 
-	if r.Handler != nil {
-		router.Handle(
-			r.Method+" "+chainedPath,
-			applyMiddleware(chainedMiddleware...)(r.Handler),
-		)
+	if r.Handler != nil && m.includes(r.Method+" "+chainedPaths[0]) {
+		var handler http.Handler = chainedMetadata.handler(r.Handler)
+		if chainedMetadata.MaxBodySize > 0 {
+			handler = middleware.MaxBytes(chainedMetadata.MaxBodySize)(handler)
+		}
+		handler = applyMiddleware(chainedMiddleware...)(handler)
+		if chainedMetadata.OnError != nil {
+			handler = handleErrors(chainedMetadata.OnError)(handler)
+		}
+		if chainedMetadata.Validator != nil {
+			handler = withValidator(chainedMetadata.Validator)(handler)
+		}
+		if chainedMetadata.CookieSecret != nil {
+			handler = withCookieSecret(chainedMetadata.CookieSecret)(handler)
+		}
+		if chainedMetadata.Canonical != "" {
+			handler = canonical(m.canonicalPath(chainedMetadata.Canonical))(handler)
+		}
+		if chainedMetadata.Deprecated && !chainedMetadata.DeprecatedSince.IsZero() {
+			handler = deprecation(chainedMetadata.DeprecatedSince)(handler)
+		}
+		if !chainedMetadata.Sunset.IsZero() {
+			handler = sunset(chainedMetadata.Sunset)(handler)
+		}
+		if chainedMetadata.NoIndex {
+			handler = noIndex(handler)
+		}
+		if len(chainedMetadata.Assets) > 0 {
+			handler = EarlyHints(chainedMetadata.Assets...)(handler)
+		}
+		if chainedMetadata.Timeout > 0 {
+			handler = withTimeout(chainedMetadata.Timeout)(handler)
+		}
+		name := chainedMetadata.Name
+		if name == "" {
+			name = m.names[chainedPaths[0]]
+		}
+		for _, chainedPath := range chainedPaths {
+			info := &RouteInfo{
+				Method:   r.Method,
+				Pattern:  chainedPath,
+				Name:     name,
+				Locale:   m.locales[chainedPath],
+				BasePath: m.basePath,
+				Metadata: chainedMetadata,
+				paths:    m.paths,
+			}
+			m.handle(r.Method+" "+chainedPath, withRouteInfo(info, handler), chainedMetadata.Priority)
+		}
 	}
 
 	for _, route := range r.Routes {
 		route.inspectRoute(
-			chainedPath,
+			chainedPaths,
 			chainedMiddleware,
-			router,
-			walkFn,
+			chainedMetadata,
+			m,
 		)
 	}
 }
 
 // Mount returns an http.ServeMux with all the routes and handlers registered.
-// Dynamically editing the route after mounting it will not affect the returned http.ServeMux.
-// Mounting the route will not validate the route's structure or the presence of handlers.
-// It is the user's responsibility to ensure that the route is correctly configured before mounting.
-func (r *Route) Mount() *http.ServeMux {
-	router := http.NewServeMux()
-	r.inspectRoute("", []Middleware{}, router, nil)
-	return router
+// Dynamically editing the route after mounting it will not affect the returned http.ServeMux,
+// mount it with [WithFreeze] to catch those edits.
+// Mounting the route will not validate the route's structure or the presence of handlers unless
+// [WithValidation] is given. It is the user's responsibility to ensure that the route is correctly
+// configured before mounting. Options can be combined, like Mount(WithWalk(fn), WithValidation()).
+func (r *Route) Mount(opts ...MountOption) *http.ServeMux {
+	return r.mount(opts...)
 }
 
 // WalkFn is a function type that can be used to walk through the routes as they are mounted.
@@ -165,15 +432,13 @@ func (r *Route) Mount() *http.ServeMux {
 // It can be used for debugging or testing purposes.
 type WalkFn func(router *Route, path string, middlewares []Middleware)
 
-// MountAndWalk does the same as [Route.Mount], but requires a WalkFn to be provided.
-// The WalkFn will be called for each route and subroute,
-// allowing for custom debugging or logging of the routes.
+// MountAndWalk does the same as [Route.Mount] with [WithWalk], and requires a WalkFn to be provided.
+//
+// Deprecated: Use Mount(WithWalk(walkFn)), which can be combined with the other mount options.
 func (r *Route) MountAndWalk(walkFn WalkFn) *http.ServeMux {
 	if walkFn == nil {
 		panic("walkFn parameter cannot be nil")
 	}
 
-	router := http.NewServeMux()
-	r.inspectRoute("", []Middleware{}, router, walkFn)
-	return router
+	return r.mount(WithWalk(walkFn))
 }
@@ -1,6 +1,7 @@
 package simplerouter_test
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -53,6 +54,7 @@ func TestNewRoute(t *testing.T) {
 			got := r.NewRoute(tt.path)
 
 			assertCorrect(t, got.Path, tt.path)
+			assertCorrect(t, len(got.Aliases), 0)
 			assertCorrect(t, len(got.Middlewares), 0)
 			assertCorrect(t, len(got.Routes), 0)
 			assertCorrect(t, got.Method, "")
@@ -111,6 +113,44 @@ func TestHTTPMethodRoutes(t *testing.T) {
 	}
 }
 
+// TestMethods tests that the handler is registered for each of the given methods only
+func TestMethods(t *testing.T) {
+	mux := r.NewRoute("/users/{id}").Add(
+		r.Methods(handlerWriter("upsert"), http.MethodPut, http.MethodPatch),
+	).Mount()
+
+	tests := []struct {
+		method         string
+		expectedStatus int
+	}{
+		{method: http.MethodPut, expectedStatus: http.StatusOK},
+		{method: http.MethodPatch, expectedStatus: http.StatusOK},
+		{method: http.MethodGet, expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, "/users/1", nil))
+			assertCorrect(t, w.Code, tt.expectedStatus)
+		})
+	}
+}
+
+// TestMethodsWithoutMethods tests that missing or empty methods cause a panic
+func TestMethodsWithoutMethods(t *testing.T) {
+	for name, methods := range map[string][]string{"no methods": nil, "empty method": {http.MethodGet, ""}} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Methods() did not panic")
+				}
+			}()
+			r.Methods(handlerWriter("ok"), methods...)
+		})
+	}
+}
+
 // TestAdd tests the Add method functionality with table-driven tests
 func TestAdd(t *testing.T) {
 	tests := []struct {
@@ -242,6 +282,48 @@ func TestAddWithMixedNilRoute(t *testing.T) {
 	route.Add(validRoute, nil)
 }
 
+// TestAddWithCycle tests that adding a route to one of its descendants causes a panic
+func TestAddWithCycle(t *testing.T) {
+	child := r.NewRoute("/child")
+	route := r.NewRoute("/test").Add(child)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Add() with an ancestor route to panic, but it didn't")
+		}
+	}()
+
+	child.Add(route)
+}
+
+// TestTryAdd tests the errors returned by TryAdd and that nothing is added when it fails
+func TestTryAdd(t *testing.T) {
+	child := r.NewRoute("/child")
+	route := r.NewRoute("/test").Add(child)
+
+	tests := []struct {
+		name          string
+		parent        *r.Route
+		routes        []*r.Route
+		expectedErr   error
+		expectedCount int
+	}{
+		{name: "valid routes", parent: route, routes: []*r.Route{r.Get(handlerWriter("h1"))}, expectedErr: nil, expectedCount: 2},
+		{name: "nil route", parent: route, routes: []*r.Route{r.Get(handlerWriter("h1")), nil}, expectedErr: r.ErrNilRoute, expectedCount: 2},
+		{name: "route itself", parent: child, routes: []*r.Route{child}, expectedErr: r.ErrRouteCycle, expectedCount: 0},
+		{name: "ancestor route", parent: child, routes: []*r.Route{route}, expectedErr: r.ErrRouteCycle, expectedCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parent.TryAdd(tt.routes...)
+
+			assertCorrect(t, err, tt.expectedErr)
+			assertCorrect(t, len(tt.parent.Routes), tt.expectedCount)
+		})
+	}
+}
+
 // TestUse tests the Use method functionality with table-driven tests
 func TestUse(t *testing.T) {
 	tests := []struct {
@@ -350,6 +432,39 @@ func TestUseWithMixedNilMiddleware(t *testing.T) {
 	route.Use(middlewareTracker("m1", &[]string{}), nil)
 }
 
+// TestTryUse tests the errors returned by TryUse and that nothing is added when it fails
+func TestTryUse(t *testing.T) {
+	route := r.NewRoute("/test")
+
+	err := route.TryUse(middlewareTracker("m1", &[]string{}), nil)
+	assertCorrect(t, err, r.ErrNilMiddleware)
+	assertCorrect(t, len(route.Middlewares), 0)
+
+	err = route.TryUse(middlewareTracker("m1", &[]string{}))
+	assertCorrect(t, err, nil)
+	assertCorrect(t, len(route.Middlewares), 1)
+}
+
+// TestUsePre tests that prepended middlewares execute before the ones already added
+func TestUsePre(t *testing.T) {
+	var tracker []string
+	route := r.NewRoute("").Use(middlewareTracker("parent", &tracker)).Add(
+		r.NewRoute("/users").
+			Use(middlewareTracker("auth", &tracker)).
+			UsePre(middlewareTracker("tracing", &tracker), middlewareTracker("metrics", &tracker)).
+			Add(r.Get(handlerWriter("users"))),
+	)
+
+	w := httptest.NewRecorder()
+	route.Mount().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	expected := []string{"parent", "tracing", "metrics", "auth"}
+	assertCorrect(t, len(tracker), len(expected))
+	for i, name := range expected {
+		assertCorrect(t, tracker[i], name)
+	}
+}
+
 // TestMount tests the Mount function with table-driven tests
 func TestMount(t *testing.T) {
 	tests := []struct {
@@ -668,3 +783,50 @@ func TestMount(t *testing.T) {
 		})
 	}
 }
+
+// TestMountMiddlewareAliasing tests that sibling subtrees of wide trees do not share the middlewares
+// of their parents, whatever spare capacity the parent chains have
+func TestMountMiddlewareAliasing(t *testing.T) {
+	for n := 1; n <= 8; n++ {
+		t.Run(fmt.Sprintf("%d parent middlewares", n), func(t *testing.T) {
+			var tracker []string
+			root := r.NewRoute("")
+			for i := range n {
+				root.Use(middlewareTracker(fmt.Sprintf("root%d", i), &tracker))
+			}
+			siblings := []string{"a", "b", "c", "d"}
+			for _, name := range siblings {
+				root.Add(r.NewRoute("/" + name).Use(middlewareTracker(name, &tracker)).Add(
+					r.NewRoute("/x").Use(middlewareTracker(name+"x", &tracker)).Add(r.Get(handlerWriter(name))),
+				))
+			}
+
+			// The middlewares given to the walk function for the "/x" routes are the ones of their parents,
+			// kept until all the tree is mounted.
+			walked := map[string][]r.Middleware{}
+			mux := root.Mount(r.WithWalk(func(route *r.Route, path string, middlewares []r.Middleware) {
+				if route.Path == "/x" {
+					walked[path] = middlewares
+				}
+			}))
+
+			for _, name := range siblings {
+				tracker = nil
+				w := httptest.NewRecorder()
+				mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+name+"/x", nil))
+				assertCorrect(t, w.Body.String(), name)
+				assertCorrect(t, fmt.Sprint(tracker[n:]), fmt.Sprint([]string{name, name + "x"}))
+
+				tracker = nil
+				chain := walked["/"+name]
+				var handler http.Handler = handlerWriter(name)
+				for i := len(chain) - 1; i >= 0; i-- {
+					handler = chain[i](handler)
+				}
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+				assertCorrect(t, len(chain), n+1)
+				assertCorrect(t, fmt.Sprint(tracker[n:]), fmt.Sprint([]string{name}))
+			}
+		})
+	}
+}
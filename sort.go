@@ -0,0 +1,72 @@
+package simplerouter
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// methodOrder is the rank of each method when sorting routes, routes matching all methods go last.
+var methodOrder = map[string]int{
+	http.MethodGet:     0,
+	http.MethodHead:    1,
+	http.MethodPost:    2,
+	http.MethodPut:     3,
+	http.MethodPatch:   4,
+	http.MethodDelete:  5,
+	http.MethodConnect: 6,
+	http.MethodOptions: 7,
+	http.MethodTrace:   8,
+	"":                 10,
+}
+
+// SortByPath sorts the child routes of the tree recursively, so walking, listing and exporting it
+// gives the same output regardless of the order of the Add calls, e.g. to keep golden files stable.
+// Child routes are sorted by path, then by method (GET, HEAD, POST, PUT, PATCH, DELETE, CONNECT,
+// OPTIONS, TRACE, other methods alphabetically, and routes matching all methods), and routes
+// with the same path and method by the content of their subtrees.
+// Sorting doesn't change which handler serves a request, as http.ServeMux matches by specificity.
+func (r *Route) SortByPath() *Route {
+	for _, route := range r.Routes {
+		route.SortByPath()
+	}
+
+	slices.SortStableFunc(r.Routes, func(a, b *Route) int {
+		return cmp.Or(
+			strings.Compare(a.Path, b.Path),
+			compareMethods(a.Method, b.Method),
+			strings.Compare(a.signature(), b.signature()),
+		)
+	})
+	return r
+}
+
+// compareMethods compares two methods by their rank in methodOrder, unknown methods alphabetically.
+func compareMethods(a, b string) int {
+	rank := func(method string) int {
+		if order, ok := methodOrder[method]; ok {
+			return order
+		}
+		return 9
+	}
+	return cmp.Or(cmp.Compare(rank(a), rank(b)), strings.Compare(a, b))
+}
+
+// signature returns a string describing the subtree of the route, used to sort otherwise equal routes.
+func (r *Route) signature() string {
+	var b strings.Builder
+	b.WriteString(r.Method + " " + r.Path + " " + strings.Join(r.Aliases, ","))
+	for _, mw := range r.Middlewares {
+		b.WriteString(" " + FuncName(mw))
+	}
+	if r.Handler != nil {
+		b.WriteString(" " + FuncName(r.Handler))
+	}
+	b.WriteString(" [")
+	for _, route := range r.Routes {
+		b.WriteString(route.signature() + ";")
+	}
+	b.WriteString("]")
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// getUsers is a named handler used to check the sort by subtree content
+func getUsers(w http.ResponseWriter, req *http.Request) {}
+
+// TestSortByPath tests that sorted trees give the same output regardless of the Add order
+func TestSortByPath(t *testing.T) {
+	first := r.NewRoute("/api").Add(
+		r.NewRoute("/users").Add(r.All(listUsers), r.Post(listUsers), r.Get(listUsers)),
+		r.NewRoute("/posts").Add(r.Get(listUsers)),
+		r.NewRoute("/admin").Add(r.NewRoute("/b").Add(r.Get(getUsers))),
+		r.NewRoute("/admin").Add(r.NewRoute("/a").Add(r.Get(listUsers))),
+	)
+	second := r.NewRoute("/api").Add(
+		r.NewRoute("/admin").Add(r.NewRoute("/a").Add(r.Get(listUsers))),
+		r.NewRoute("/posts").Add(r.Get(listUsers)),
+		r.NewRoute("/admin").Add(r.NewRoute("/b").Add(r.Get(getUsers))),
+		r.NewRoute("/users").Add(r.Get(listUsers), r.All(listUsers), r.Post(listUsers)),
+	)
+
+	if first.SortByPath() != first {
+		t.Errorf("SortByPath() should return the same route instance for method chaining")
+	}
+	second.SortByPath()
+
+	assertCorrect(t, first.Markdown(), second.Markdown())
+	assertCorrect(t, first.DOT(), second.DOT())
+
+	walk := func(route *r.Route) []string {
+		walked := []string{}
+		route.MountAndWalk(func(route *r.Route, path string, middlewares []r.Middleware) {
+			walked = append(walked, route.Method+" "+path+route.Path)
+		})
+		return walked
+	}
+	want := []string{
+		" /api",
+		" /api/admin",
+		" /api/admin/a",
+		"GET /api/admin/a",
+		" /api/admin",
+		" /api/admin/b",
+		"GET /api/admin/b",
+		" /api/posts",
+		"GET /api/posts",
+		" /api/users",
+		"GET /api/users",
+		"POST /api/users",
+		" /api/users",
+	}
+	if got := walk(first); !reflect.DeepEqual(got, want) {
+		t.Errorf("Walked = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(walk(first), walk(second)) {
+		t.Errorf("Walked = %v, want %v", walk(second), walk(first))
+	}
+}
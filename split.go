@@ -0,0 +1,85 @@
+package simplerouter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CodeSplitOverlap reports an endpoint selected by several rules of [Split].
+const CodeSplitOverlap ErrorCode = "split_overlap"
+
+// SplitRule selects the endpoints served by one of the handlers returned by [Split].
+type SplitRule struct {
+	// Name identifies the rule in errors, like "public" or "internal".
+	Name string
+	// Match reports whether the endpoint is served by the handler of the rule.
+	Match func(e Endpoint) bool
+}
+
+// MetaEquals returns a SplitRule match function selecting the endpoints whose metadata
+// stores value under key, see [Route.Meta].
+func MetaEquals(key string, value any) func(e Endpoint) bool {
+	return func(e Endpoint) bool {
+		v, ok := e.Metadata.Values[key]
+		return ok && v == value
+	}
+}
+
+// Split mounts the route tree into one http.ServeMux per rule, in the order of the rules, each
+// registering only the endpoints selected by its rule, so parts of the tree can be served on
+// different listeners (like a public port and an internal one) from a single definition:
+//
+//	muxes, err := simplerouter.Split(router,
+//		simplerouter.SplitRule{Name: "public", Match: simplerouter.MetaEquals("exposure", "public")},
+//		simplerouter.SplitRule{Name: "internal", Match: simplerouter.MetaEquals("exposure", "internal")},
+//	)
+//
+// Endpoints not selected by any rule are not served. If an endpoint is selected by more than one rule,
+// no handler is returned and the error joins a [*RouteError] with the [CodeSplitOverlap] code for each of them.
+func Split(route *Route, rules ...SplitRule) ([]*http.ServeMux, error) {
+	for _, rule := range rules {
+		if rule.Match == nil {
+			panic("rules parameter cannot contain rules without Match function")
+		}
+	}
+
+	endpoints := route.Endpoints()
+	selected := make([][]Endpoint, len(rules))
+	errs := []error{}
+
+	for _, endpoint := range endpoints {
+		names := []string{}
+		for i, rule := range rules {
+			if rule.Match(endpoint) {
+				selected[i] = append(selected[i], endpoint)
+				names = append(names, rule.Name)
+			}
+		}
+		if len(names) > 1 {
+			errs = append(errs, &RouteError{
+				Code:    CodeSplitOverlap,
+				Pattern: endpoint.Pattern(),
+				Err:     fmt.Errorf("endpoint is selected by rules %s", strings.Join(names, ", ")),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	muxes := make([]*http.ServeMux, len(rules))
+	for i := range rules {
+		muxes[i] = http.NewServeMux()
+		m := newMounter(route, muxes[i], nil, "")
+		m.patterns = map[string]bool{}
+		for _, endpoint := range selected[i] {
+			m.patterns[endpoint.Method+" "+endpoint.Path] = true
+		}
+		route.inspectRoute([]string{""}, []Middleware{}, Metadata{}, m)
+		m.registerPreflights(selected[i])
+		m.register()
+	}
+	return muxes, nil
+}
@@ -0,0 +1,76 @@
+package simplerouter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestSplit tests the endpoints served by each handler of a split tree
+func TestSplit(t *testing.T) {
+	tree := r.NewRoute("/api").Meta("exposure", "public").Add(
+		r.NewRoute("/users").Add(r.Get(handlerWriter("users"))),
+		r.NewRoute("/metrics").Meta("exposure", "internal").Add(r.Get(handlerWriter("metrics"))),
+		r.NewRoute("/debug").Meta("exposure", "none").Add(r.Get(handlerWriter("debug"))),
+	)
+
+	muxes, err := r.Split(tree,
+		r.SplitRule{Name: "public", Match: r.MetaEquals("exposure", "public")},
+		r.SplitRule{Name: "internal", Match: r.MetaEquals("exposure", "internal")},
+	)
+	if err != nil {
+		t.Fatalf("Split() returned error %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		mux            *http.ServeMux
+		path           string
+		expectedStatus int
+	}{
+		{name: "public endpoint on public handler", mux: muxes[0], path: "/api/users", expectedStatus: http.StatusOK},
+		{name: "internal endpoint on public handler", mux: muxes[0], path: "/api/metrics", expectedStatus: http.StatusNotFound},
+		{name: "internal endpoint on internal handler", mux: muxes[1], path: "/api/metrics", expectedStatus: http.StatusOK},
+		{name: "public endpoint on internal handler", mux: muxes[1], path: "/api/users", expectedStatus: http.StatusNotFound},
+		{name: "unselected endpoint on public handler", mux: muxes[0], path: "/api/debug", expectedStatus: http.StatusNotFound},
+		{name: "unselected endpoint on internal handler", mux: muxes[1], path: "/api/debug", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+		})
+	}
+}
+
+// TestSplitWithOverlap tests that endpoints selected by several rules are reported
+func TestSplitWithOverlap(t *testing.T) {
+	tree := r.NewRoute("/api").Tags("admin").Add(
+		r.NewRoute("/users").Add(r.Get(handlerWriter("users"))),
+	)
+
+	muxes, err := r.Split(tree,
+		r.SplitRule{Name: "public", Match: func(e r.Endpoint) bool { return true }},
+		r.SplitRule{Name: "internal", Match: func(e r.Endpoint) bool { return len(e.Metadata.Tags) > 0 }},
+	)
+
+	if muxes != nil {
+		t.Error("Split() returned handlers for overlapping rules")
+	}
+	var routeErr *r.RouteError
+	if !errors.As(err, &routeErr) {
+		t.Fatalf("Split() = %v, want a RouteError", err)
+	}
+	assertCorrect(t, routeErr.Code, r.CodeSplitOverlap)
+	assertCorrect(t, routeErr.Pattern, "GET /api/users")
+	if !strings.Contains(err.Error(), "public, internal") {
+		t.Errorf("Split() = %q, want it to name the rules", err.Error())
+	}
+}
@@ -0,0 +1,46 @@
+// Package srdebug provides simplerouter route trees serving the runtime profiles of net/http/pprof and
+// the variables of expvar, so they can be mounted under any prefix and behind the middlewares of the tree,
+// like an authentication middleware, instead of being served by http.DefaultServeMux:
+//
+//	router.Add(
+//		simplerouter.NewRoute("/debug").Use(requireAdmin).Add(
+//			simplerouter.NewRoute("/pprof").Add(srdebug.Pprof()),
+//			simplerouter.NewRoute("/vars").Add(srdebug.Expvar()),
+//		),
+//	)
+//
+// Importing this package imports net/http/pprof and expvar, which register their handlers on
+// http.DefaultServeMux as a side effect, so services importing it must not serve http.DefaultServeMux.
+package srdebug
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/carlos-el/simplerouter"
+)
+
+// Pprof returns a route tree serving the profiles of net/http/pprof from the path of its parent route:
+// the index of the profiles on "/", the CPU profile on "/profile", the execution trace on "/trace",
+// the command line on "/cmdline", the symbol lookup on "/symbol", and the named profiles, like
+// "/heap" or "/goroutine", on their name. Profiles can be fetched with go tool pprof:
+//
+//	go tool pprof https://example.com/debug/pprof/heap
+func Pprof() *simplerouter.Route {
+	return simplerouter.NewRoute("").Add(
+		simplerouter.NewRoute("/{$}").Add(simplerouter.Get(pprof.Index)),
+		simplerouter.NewRoute("/cmdline").Add(simplerouter.Get(pprof.Cmdline)),
+		simplerouter.NewRoute("/profile").Add(simplerouter.Get(pprof.Profile)),
+		simplerouter.NewRoute("/symbol").Add(simplerouter.Get(pprof.Symbol), simplerouter.Post(pprof.Symbol)),
+		simplerouter.NewRoute("/trace").Add(simplerouter.Get(pprof.Trace)),
+		simplerouter.NewRoute("/{profile}").Add(simplerouter.Get(func(w http.ResponseWriter, r *http.Request) {
+			pprof.Handler(r.PathValue("profile")).ServeHTTP(w, r)
+		})),
+	)
+}
+
+// Expvar returns a route serving the public variables of expvar as a JSON object from the path of its parent route.
+func Expvar() *simplerouter.Route {
+	return simplerouter.Get(expvar.Handler().ServeHTTP)
+}
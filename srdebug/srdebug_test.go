@@ -0,0 +1,63 @@
+package srdebug_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/srdebug"
+)
+
+// requireAdmin answers the requests without the admin token with a 401 Unauthorized
+func requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer admin" {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// TestDebugRoutes tests that the profiles and variables are served under the prefix, behind the middlewares
+func TestDebugRoutes(t *testing.T) {
+	mux := simplerouter.NewRoute("/internal").Use(requireAdmin).Add(
+		simplerouter.NewRoute("/pprof").Add(srdebug.Pprof()),
+		simplerouter.NewRoute("/vars").Add(srdebug.Expvar()),
+	).Mount()
+
+	tests := []struct {
+		path             string
+		token            string
+		expectedStatus   int
+		expectedContains string
+	}{
+		{path: "/internal/pprof/", token: "admin", expectedStatus: http.StatusOK, expectedContains: "goroutine"},
+		{path: "/internal/pprof/goroutine?debug=1", token: "admin", expectedStatus: http.StatusOK, expectedContains: "goroutine profile"},
+		{path: "/internal/pprof/cmdline", token: "admin", expectedStatus: http.StatusOK, expectedContains: "srdebug"},
+		{path: "/internal/pprof/unknown", token: "admin", expectedStatus: http.StatusNotFound, expectedContains: "Unknown profile"},
+		{path: "/internal/vars", token: "admin", expectedStatus: http.StatusOK, expectedContains: `"memstats"`},
+		{path: "/internal/pprof/heap", expectedStatus: http.StatusUnauthorized},
+		{path: "/internal/vars", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.expectedStatus)
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedContains) {
+				t.Errorf("body = %q, want it to contain %q", w.Body.String(), tt.expectedContains)
+			}
+		})
+	}
+}
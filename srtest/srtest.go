@@ -0,0 +1,75 @@
+// Package srtest provides helpers to test simplerouter route trees over real HTTP requests.
+package srtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/har"
+)
+
+// Option configures the handlers and servers returned by [Handler] and [NewServer].
+type Option func(*config)
+
+// config holds the configuration of a test handler.
+type config struct {
+	harFile   string
+	redaction *har.Redaction
+}
+
+// WithHAR records every request served during the test and its response into the named HAR file,
+// written in the order the requests started when the test and its subtests complete. The recordings document the API exercised by the tests
+// and can be used as replay fixtures. Credentials are redacted as by [har.Redaction.Apply].
+func WithHAR(name string) Option {
+	return func(c *config) { c.harFile = name }
+}
+
+// WithRedaction redacts the given values from the HAR file recorded with [WithHAR], besides the credentials.
+func WithRedaction(redaction har.Redaction) Option {
+	return func(c *config) { c.redaction = &redaction }
+}
+
+// Handler mounts the route tree and returns its handler, to serve requests with httptest.NewRecorder.
+func Handler(t testing.TB, route *simplerouter.Route, opts ...Option) http.Handler {
+	t.Helper()
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	var handler http.Handler = route.Mount()
+	if c.harFile == "" {
+		return handler
+	}
+
+	redaction := har.Redaction{}
+	if c.redaction != nil {
+		redaction = *c.redaction
+	}
+	var mu sync.Mutex
+	entries := []har.Entry{}
+	t.Cleanup(func() {
+		slices.SortStableFunc(entries, func(a, b har.Entry) int { return a.StartedDateTime.Compare(b.StartedDateTime) })
+		if err := har.NewFile(entries...).WriteFile(c.harFile); err != nil {
+			t.Errorf("writing HAR file: %v", err)
+		}
+	})
+	return har.Record(handler, 0, func(r *http.Request, entry har.Entry) {
+		redaction.Apply(&entry)
+		mu.Lock()
+		defer mu.Unlock()
+		entries = append(entries, entry)
+	})
+}
+
+// NewServer starts a server serving the route tree with [Handler], closed when the test completes.
+func NewServer(t testing.TB, route *simplerouter.Route, opts ...Option) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(Handler(t, route, opts...))
+	t.Cleanup(server.Close)
+	return server
+}
@@ -0,0 +1,73 @@
+package srtest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/har"
+	"github.com/carlos-el/simplerouter/srtest"
+)
+
+func assertCorrect(t testing.TB, got, want any) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+// TestWithHAR tests the HAR files recorded by test servers
+func TestWithHAR(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "users.har")
+	tree := r.NewRoute("/users").Add(
+		r.Get(func(w http.ResponseWriter, req *http.Request) { w.Write([]byte(`[]`)) }),
+		r.Post(func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusCreated) }),
+	)
+
+	t.Run("requests", func(t *testing.T) {
+		server := srtest.NewServer(t, tree, srtest.WithHAR(name), srtest.WithRedaction(har.Redaction{Query: []string{"key"}}))
+		resp, err := http.Get(server.URL + "/users?key=abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		resp, err = http.Post(server.URL+"/users", "application/json", strings.NewReader(`{"name":"ada"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	})
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var file har.File
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatal(err)
+	}
+	if len(file.Log.Entries) != 2 {
+		t.Fatalf("recorded %d entries, want 2", len(file.Log.Entries))
+	}
+
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{name: "first url", got: strings.HasSuffix(file.Log.Entries[0].Request.URL, "/users?key=[REDACTED]"), expected: true},
+		{name: "first body", got: file.Log.Entries[0].Response.Content.Text, expected: "[]"},
+		{name: "second body", got: file.Log.Entries[1].Request.PostData.Text, expected: `{"name":"ada"}`},
+		{name: "second status", got: file.Log.Entries[1].Response.Status, expected: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCorrect(t, tt.got, tt.expected)
+		})
+	}
+}
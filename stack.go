@@ -0,0 +1,82 @@
+package simplerouter
+
+import (
+	"time"
+
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// APIStack is the preset of middlewares commonly needed by API services.
+// Each middleware can be replaced by another one, or set to nil to leave it out.
+type APIStack struct {
+	RequestID Middleware
+	RealIP    Middleware
+	Logger    Middleware
+	Metrics   Middleware
+	Recover   Middleware
+}
+
+// DefaultAPIStack returns an APIStack with the middlewares of the middleware package.
+// Apply it with a single Use call, overriding any of its middlewares first if needed:
+//
+//	stack := simplerouter.DefaultAPIStack()
+//	stack.Logger = myLogger
+//	router.Use(stack.Middlewares()...)
+func DefaultAPIStack() APIStack {
+	return APIStack{
+		RequestID: middleware.RequestID(),
+		RealIP:    middleware.RealIP(),
+		Logger:    middleware.Logger(nil),
+		Metrics:   middleware.Metrics(),
+		Recover:   middleware.Recover(),
+	}
+}
+
+// Middlewares returns the middlewares of the stack in execution order, leaving out the nil ones.
+// The request ID and real IP are set first so the rest of the middlewares can use them,
+// and panics are recovered last so they are logged and measured as 500 responses.
+func (s APIStack) Middlewares() []Middleware {
+	return nonNilMiddlewares(s.RequestID, s.RealIP, s.Logger, s.Metrics, s.Recover)
+}
+
+// WebStack is the preset of middlewares commonly needed by web applications serving browsers.
+// It extends the APIStack and, as it, each middleware can be replaced or set to nil to leave it out.
+type WebStack struct {
+	APIStack
+	SecureHeaders Middleware
+	// ContentSecurityPolicy sets the Content-Security-Policy header and the CSP nonce of the requests,
+	// see [CSPNonce]. It is nil in the DefaultWebStack, as the policy depends on the resources of the pages.
+	ContentSecurityPolicy Middleware
+	Sessions              Middleware
+	CSRF                  Middleware
+}
+
+// DefaultWebStack returns a WebStack with the middlewares of the middleware package.
+// Sessions are kept in memory and expire after 24 hours of inactivity.
+// CSRF protection applies to the whole tree, replace it to exempt subtrees authenticated
+// without cookies, see [github.com/carlos-el/simplerouter/middleware.CSRFOptions].
+func DefaultWebStack() WebStack {
+	return WebStack{
+		APIStack:      DefaultAPIStack(),
+		SecureHeaders: middleware.SecureHeaders(),
+		Sessions:      middleware.Sessions(24 * time.Hour),
+		CSRF:          middleware.CSRF(middleware.CSRFOptions{}),
+	}
+}
+
+// Middlewares returns the middlewares of the stack in execution order, leaving out the nil ones.
+// The web middlewares run after the ones of the APIStack.
+func (s WebStack) Middlewares() []Middleware {
+	return append(s.APIStack.Middlewares(), nonNilMiddlewares(s.SecureHeaders, s.ContentSecurityPolicy, s.Sessions, s.CSRF)...)
+}
+
+// nonNilMiddlewares returns the given middlewares leaving out the nil ones.
+func nonNilMiddlewares(mws ...Middleware) []Middleware {
+	result := []Middleware{}
+	for _, mw := range mws {
+		if mw != nil {
+			result = append(result, mw)
+		}
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+	"github.com/carlos-el/simplerouter/middleware"
+)
+
+// TestAPIStackMiddlewares tests the middlewares of the API stack and their overrides
+func TestAPIStackMiddlewares(t *testing.T) {
+	tracker := []string{}
+
+	stack := r.DefaultAPIStack()
+	assertCorrect(t, len(stack.Middlewares()), 5)
+
+	stack.RequestID = middlewareTracker("request-id", &tracker)
+	stack.RealIP = nil
+	stack.Logger = middlewareTracker("logger", &tracker)
+	stack.Metrics = nil
+	stack.Recover = middlewareTracker("recover", &tracker)
+
+	mux := r.NewRoute("/").Use(stack.Middlewares()...).Add(r.Get(handlerWriter("ok"))).Mount()
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"request-id", "logger", "recover"}
+	if !reflect.DeepEqual(tracker, want) {
+		t.Errorf("Middlewares executed = %v, want %v", tracker, want)
+	}
+}
+
+// TestWebStackMiddlewares tests the middlewares of the web stack and their overrides
+func TestWebStackMiddlewares(t *testing.T) {
+	stack := r.DefaultWebStack()
+	assertCorrect(t, len(stack.Middlewares()), 8)
+
+	stack.Sessions = nil
+	stack.Metrics = nil
+	assertCorrect(t, len(stack.Middlewares()), 6)
+}
+
+// TestDefaultWebStack tests a route using the default web stack
+func TestDefaultWebStack(t *testing.T) {
+	mux := r.NewRoute("").Use(r.DefaultWebStack().Middlewares()...).Add(
+		r.NewRoute("/ok").Add(r.Get(handlerWriter("ok"))),
+		r.NewRoute("/panic").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+			panic("boom")
+		})),
+	).Mount()
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/ok", expectedStatus: http.StatusOK},
+		{path: "/panic", expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, len(w.Header().Get("X-Request-ID")), 32)
+			assertCorrect(t, w.Header().Get("X-Content-Type-Options"), "nosniff")
+			// The session cookie is only sent once the handlers set a session value.
+			assertCorrect(t, len(w.Result().Cookies()), 1)
+			assertCorrect(t, w.Result().Cookies()[0].Name, middleware.CSRFCookie)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package simplerouter
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// staticPathWildcard is the name of the wildcard matching the paths of the files served by [Static].
+const staticPathWildcard = "staticpath"
+
+// StaticOption configures the routes returned by [Static].
+type StaticOption func(*staticConfig)
+
+// staticConfig holds the configuration of a static route.
+type staticConfig struct {
+	listing   bool
+	modTime   time.Time
+	immutable *regexp.Regexp
+}
+
+// hashedAssetPattern matches the names of the files holding a content hash of at least 8 hexadecimal
+// digits, like app.3f2a9c1b.js or logo-3f2a9c1b.svg, as written by most bundlers.
+var hashedAssetPattern = regexp.MustCompile(`[.-][0-9a-fA-F]{8,}\.[^/]+$`)
+
+// DirectoryListing lists the files of the directories without index.html, which are not found otherwise.
+func DirectoryListing() StaticOption {
+	return func(c *staticConfig) { c.listing = true }
+}
+
+// LastModified sets the modification time of the files without one, like the ones of an embed.FS,
+// usually the build time of the binary. Their responses get a Last-Modified header and the requests
+// with an If-Modified-Since header are answered with a 304 Not Modified if they were not modified since.
+func LastModified(t time.Time) StaticOption {
+	if t.IsZero() {
+		panic("t parameter cannot be zero")
+	}
+	return func(c *staticConfig) { c.modTime = t }
+}
+
+// ImmutableAssets marks the files whose paths match pattern as immutable, so browsers and caches keep them
+// for a year without revalidating them. It suits the files holding a hash of their content in their names,
+// as their names change with them; if pattern is nil, names with a hash of at least 8 hexadecimal digits
+// before their extension, like app.3f2a9c1b.js, match.
+func ImmutableAssets(pattern *regexp.Regexp) StaticOption {
+	if pattern == nil {
+		pattern = hashedAssetPattern
+	}
+	return func(c *staticConfig) { c.immutable = pattern }
+}
+
+// Static returns a Route serving the files of fsys under prefix, so static assets get the
+// middlewares and metadata of the tree as any other route:
+//
+//	//go:embed assets
+//	var assets embed.FS
+//
+//	sub, _ := fs.Sub(assets, "assets")
+//	router.Add(simplerouter.Static("/assets", sub))
+//
+// Files are served with http.FileServerFS, which sets their content type, serves index.html for
+// directories and supports range requests. Responses get a strong ETag computed from the content of
+// the file, cached until its size or modification time change, so requests with If-None-Match or
+// If-Range headers are answered as if the file were on disk; files with a modification time, like the
+// ones of os.DirFS, also get a Last-Modified header, see [LastModified] for the ones without.
+// Hashed assets can be cached for good with [ImmutableAssets].
+// Directories without index.html are not found unless [DirectoryListing] is given. Once mounted,
+// requests to the prefix itself are redirected to the prefix followed by a slash.
+func Static(prefix string, fsys fs.FS, opts ...StaticOption) *Route {
+	if fsys == nil {
+		panic("fsys parameter cannot be nil")
+	}
+	config := &staticConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if !config.listing {
+		fsys = noListingFS{fsys}
+	}
+	return NewRoute(prefix + "/{" + staticPathWildcard + "...}").Add(
+		Get(config.handler(fsys)),
+	)
+}
+
+// staticETag is the ETag of a file, valid while its size and modification time do not change.
+type staticETag struct {
+	size    int64
+	modTime time.Time
+	etag    string
+}
+
+// handler returns a handler serving the files of fsys with the validators and cache headers of the configuration.
+func (c *staticConfig) handler(fsys fs.FS) http.HandlerFunc {
+	fileServer := wildcardHandler(http.FileServerFS(fsys), staticPathWildcard)
+	var etags sync.Map
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := staticFileName(r)
+		info, err := fs.Stat(fsys, name)
+		if err == nil && info.IsDir() && strings.HasSuffix(r.URL.Path, "/") {
+			name = path.Join(name, "index.html")
+			info, err = fs.Stat(fsys, name)
+		}
+		if err != nil || info.IsDir() {
+			fileServer(w, r)
+			return
+		}
+
+		if etag, err := fileETag(fsys, name, info, &etags); err == nil {
+			w.Header().Set("ETag", etag)
+		}
+		if c.immutable != nil && c.immutable.MatchString(name) {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		}
+		if info.ModTime().IsZero() && !c.modTime.IsZero() {
+			// http.ServeContent ignores the files without a modification time, so it is handled here.
+			w.Header().Set("Last-Modified", c.modTime.UTC().Format(http.TimeFormat))
+			if notModifiedSince(r, c.modTime) {
+				h := w.Header()
+				delete(h, "Content-Type")
+				delete(h, "Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		fileServer(w, r)
+	}
+}
+
+// staticFileName returns the name in the file system of the file requested to a static route.
+func staticFileName(r *http.Request) string {
+	name := strings.TrimPrefix(path.Clean("/"+r.PathValue(staticPathWildcard)), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+// fileETag returns the ETag of the named file, hashing its content unless the one in etags is still valid.
+func fileETag(fsys fs.FS, name string, info fs.FileInfo, etags *sync.Map) (string, error) {
+	if cached, ok := etags.Load(name); ok {
+		if e := cached.(staticETag); e.size == info.Size() && e.modTime.Equal(info.ModTime()) {
+			return e.etag, nil
+		}
+	}
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	etag := `"` + base64.RawURLEncoding.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	etags.Store(name, staticETag{size: info.Size(), modTime: info.ModTime(), etag: etag})
+	return etag, nil
+}
+
+// notModifiedSince reports whether the GET or HEAD request has an If-Modified-Since header, and no
+// If-None-Match header taking precedence over it, not before modTime.
+func notModifiedSince(r *http.Request, modTime time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead || r.Header.Get("If-None-Match") != "" {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	return err == nil && !modTime.Truncate(time.Second).After(since)
+}
+
+// SPA returns a Route serving a single-page application from fsys: the files of fsys are served as
+// by [Static], and the GET requests for unknown paths are answered with the file at indexPath, so the
+// client-side router of the application can handle them:
+//
+//	router.Add(
+//		simplerouter.NewRoute("/api").Add(apiRoutes...),
+//		simplerouter.SPA(dist, "index.html"),
+//	)
+//
+// The route matches every path under its parents, so the more specific routes of the tree, like the
+// API ones, take precedence over it. Only requests explicitly accepting text/html, like the navigations
+// of browsers, for paths without a file extension fall back to the index, so missing assets and unknown
+// API calls, even accepting "*/*", are still not found.
+// It panics if indexPath is not a file of fsys.
+func SPA(fsys fs.FS, indexPath string) *Route {
+	if fsys == nil {
+		panic("fsys parameter cannot be nil")
+	}
+	if info, err := fs.Stat(fsys, indexPath); err != nil || info.IsDir() {
+		panic("indexPath parameter must be a file of fsys")
+	}
+
+	files := noListingFS{fsys}
+	serveFile := (&staticConfig{}).handler(files)
+	return NewRoute("/{" + staticPathWildcard + "...}").Add(
+		Get(func(w http.ResponseWriter, r *http.Request) {
+			name := staticFileName(r)
+			if _, err := fs.Stat(files, name); err == nil || !acceptsIndex(r, name) {
+				serveFile(w, r)
+				return
+			}
+			w.Header().Set("Cache-Control", "no-cache")
+			http.ServeFileFS(w, r, fsys, indexPath)
+		}),
+	)
+}
+
+// acceptsIndex reports whether the request for the missing file name is answered with the index of a SPA,
+// that is whether name has no extension and the Accept header of the request lists text/html.
+func acceptsIndex(r *http.Request, name string) bool {
+	if path.Ext(name) != "" {
+		return false
+	}
+	// Wildcards are not enough, as API clients and fetch calls send "*/*" by default.
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(accept, ";")
+		if !strings.EqualFold(strings.TrimSpace(mediaType), "text/html") {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			if name, value, _ := strings.Cut(param, "="); strings.TrimSpace(name) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// noListingFS wraps a file system hiding the directories without index.html.
+type noListingFS struct {
+	fs.FS
+}
+
+// Open opens the named file, or returns fs.ErrNotExist if it is a directory without index.html.
+func (fsys noListingFS) Open(name string) (fs.File, error) {
+	f, err := fsys.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil || !info.IsDir() {
+		return f, err
+	}
+	if _, err := fs.Stat(fsys.FS, path.Join(name, "index.html")); err != nil {
+		f.Close()
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return f, nil
+}
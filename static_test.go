@@ -0,0 +1,197 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// staticFiles is a file system with a site and a directory without index
+var staticFiles = fstest.MapFS{
+	"index.html":    {Data: []byte("<h1>home</h1>")},
+	"css/app.css":   {Data: []byte("body{}")},
+	"docs/a.txt":    {Data: []byte("0123456789")},
+	"docs/sub/b.md": {Data: []byte("# b")},
+}
+
+// TestStatic tests the files served by static routes
+func TestStatic(t *testing.T) {
+	tracker := []string{}
+	tree := r.NewRoute("").Use(middlewareTracker("m1", &tracker)).Add(
+		r.Static("/static", staticFiles),
+		r.Static("/listed", staticFiles, r.DirectoryListing()),
+	)
+	mux := tree.Mount()
+
+	tests := []struct {
+		name                string
+		path                string
+		rangeHeader         string
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{name: "file", path: "/static/css/app.css", expectedStatus: http.StatusOK, expectedContentType: "text/css; charset=utf-8", expectedBody: "body{}"},
+		{name: "index", path: "/static/", expectedStatus: http.StatusOK, expectedContentType: "text/html; charset=utf-8", expectedBody: "<h1>home</h1>"},
+		{name: "range request", path: "/static/docs/a.txt", rangeHeader: "bytes=2-4", expectedStatus: http.StatusPartialContent, expectedContentType: "text/plain; charset=utf-8", expectedBody: "234"},
+		{name: "missing file", path: "/static/missing.js", expectedStatus: http.StatusNotFound, expectedContentType: "text/plain; charset=utf-8", expectedBody: "404 page not found\n"},
+		{name: "directory without index", path: "/static/docs/", expectedStatus: http.StatusNotFound, expectedContentType: "text/plain; charset=utf-8", expectedBody: "404 page not found\n"},
+		{name: "listed directory", path: "/listed/docs/", expectedStatus: http.StatusOK, expectedContentType: "text/html; charset=utf-8", expectedBody: "<!doctype html>\n<meta name=\"viewport\" content=\"width=device-width\">\n<pre>\n<a href=\"a.txt\">a.txt</a>\n<a href=\"sub/\">sub/</a>\n</pre>\n"},
+		{name: "prefix without slash", path: "/static", expectedStatus: http.StatusTemporaryRedirect, expectedContentType: "", expectedBody: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker = tracker[:0]
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.rangeHeader != "" {
+				req.Header.Set("Range", tt.rangeHeader)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			if tt.expectedStatus != http.StatusTemporaryRedirect {
+				assertCorrect(t, w.Header().Get("Content-Type"), tt.expectedContentType)
+				assertCorrect(t, w.Body.String(), tt.expectedBody)
+				assertCorrect(t, len(tracker), 1)
+			}
+		})
+	}
+}
+
+// TestStaticConditionalRequests tests the validators and cache headers of the files served by static routes
+func TestStaticConditionalRequests(t *testing.T) {
+	built := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	files := fstest.MapFS{
+		"index.html":         {Data: []byte("<h1>home</h1>")},
+		"js/app.3f2a9c1b.js": {Data: []byte("console.log(1)")},
+		"js/vendor.js":       {Data: []byte("console.log(2)")},
+	}
+	mux := r.NewRoute("").Add(
+		r.Static("/static", files, r.LastModified(built), r.ImmutableAssets(nil)),
+	).Mount()
+
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header = header
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	first := serve("/static/js/app.3f2a9c1b.js", http.Header{})
+	etag := first.Header().Get("ETag")
+	assertCorrect(t, first.Code, http.StatusOK)
+	assertCorrect(t, len(etag) > 2 && etag[0] == '"', true)
+	assertCorrect(t, first.Header().Get("Last-Modified"), "Fri, 02 Jan 2026 03:04:05 GMT")
+	assertCorrect(t, first.Header().Get("Cache-Control"), "public, max-age=31536000, immutable")
+	assertCorrect(t, serve("/static/js/vendor.js", http.Header{}).Header().Get("Cache-Control"), "")
+	assertCorrect(t, serve("/static/", http.Header{}).Header().Get("ETag") != "", true)
+
+	tests := []struct {
+		name           string
+		header         http.Header
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "matching etag", header: http.Header{"If-None-Match": {etag}}, expectedStatus: http.StatusNotModified},
+		{name: "other etag", header: http.Header{"If-None-Match": {`"other"`}}, expectedStatus: http.StatusOK, expectedBody: "console.log(1)"},
+		{name: "not modified since", header: http.Header{"If-Modified-Since": {"Fri, 02 Jan 2026 03:04:05 GMT"}}, expectedStatus: http.StatusNotModified},
+		{name: "modified since", header: http.Header{"If-Modified-Since": {"Thu, 01 Jan 2026 00:00:00 GMT"}}, expectedStatus: http.StatusOK, expectedBody: "console.log(1)"},
+		{name: "etag takes precedence", header: http.Header{"If-None-Match": {`"other"`}, "If-Modified-Since": {"Fri, 02 Jan 2026 03:04:05 GMT"}}, expectedStatus: http.StatusOK, expectedBody: "console.log(1)"},
+		{name: "range", header: http.Header{"Range": {"bytes=0-6"}}, expectedStatus: http.StatusPartialContent, expectedBody: "console"},
+		{name: "range if etag matches", header: http.Header{"Range": {"bytes=0-6"}, "If-Range": {etag}}, expectedStatus: http.StatusPartialContent, expectedBody: "console"},
+		{name: "range if etag changed", header: http.Header{"Range": {"bytes=0-6"}, "If-Range": {`"other"`}}, expectedStatus: http.StatusOK, expectedBody: "console.log(1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve("/static/js/app.3f2a9c1b.js", tt.header)
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, w.Header().Get("ETag"), etag)
+		})
+	}
+}
+
+// TestStaticETagWithModifiedFile tests that the ETags of the files of a directory change with their content
+func TestStaticETagWithModifiedFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	os.WriteFile(file, []byte("first"), 0o600)
+	mux := r.NewRoute("").Add(r.Static("/static", os.DirFS(dir))).Mount()
+
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/static/a.txt", nil))
+		return w
+	}
+	first := serve()
+	assertCorrect(t, first.Header().Get("Last-Modified") != "", true)
+
+	os.WriteFile(file, []byte("second"), 0o600)
+	os.Chtimes(file, time.Time{}, time.Now().Add(time.Hour))
+	second := serve()
+	assertCorrect(t, second.Body.String(), "second")
+	assertCorrect(t, second.Header().Get("ETag") != first.Header().Get("ETag"), true)
+}
+
+// TestSPA tests the files and fallbacks served by SPA routes
+func TestSPA(t *testing.T) {
+	tree := r.NewRoute("").Add(
+		r.NewRoute("/api/users").Add(r.Get(handlerWriter("users"))),
+		r.SPA(staticFiles, "index.html"),
+	)
+	mux := tree.Mount()
+
+	tests := []struct {
+		name           string
+		path           string
+		accept         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "index", path: "/", expectedStatus: http.StatusOK, expectedBody: "<h1>home</h1>"},
+		{name: "asset", path: "/css/app.css", expectedStatus: http.StatusOK, expectedBody: "body{}"},
+		{name: "client route", path: "/settings/profile", accept: "text/html,application/xhtml+xml", expectedStatus: http.StatusOK, expectedBody: "<h1>home</h1>"},
+		{name: "directory without index", path: "/docs/", accept: "text/html", expectedStatus: http.StatusOK, expectedBody: "<h1>home</h1>"},
+		{name: "api route", path: "/api/users", expectedStatus: http.StatusOK, expectedBody: "users"},
+		{name: "unknown api call", path: "/api/orders", accept: "application/json", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+		{name: "missing asset", path: "/js/app.js", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+		{name: "unknown api call accepting anything", path: "/api/orders", accept: "*/*", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+		{name: "unknown path without accept", path: "/settings", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+		{name: "client route with quality", path: "/settings", accept: "application/json, TEXT/HTML;q=0.8", expectedStatus: http.StatusOK, expectedBody: "<h1>home</h1>"},
+		{name: "client route refusing html", path: "/settings", accept: "text/html;q=0, */*", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+// TestSPAMissingIndex tests that SPA panics when the index is not a file
+func TestSPAMissingIndex(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("SPA() did not panic")
+		}
+	}()
+	r.SPA(staticFiles, "docs")
+}
@@ -0,0 +1,73 @@
+package simplerouter
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// Subtree returns the route of the tree with the given full path, as a new root route that can be
+// mounted on its own, and whether it was found. Routes are matched by their main path, depth first.
+// The returned route keeps the middlewares, metadata and aliases inherited from its ancestors, so
+// once mounted, it serves its requests as the full tree does. Its child routes are shared with the
+// tree, not copied, so later changes to them affect both.
+func (r *Route) Subtree(path string) (*Route, bool) {
+	return r.subtree(path, "", []Middleware{}, []string{}, Metadata{})
+}
+
+// MountSubtree does the same as [Route.Mount], for the subtree with the given full path,
+// so a part of a large tree can be served on a different listener, like /internal on an admin port.
+// It panics if no route of the tree has that path, see [Route.Subtree].
+func (r *Route) MountSubtree(path string) *http.ServeMux {
+	subtree, ok := r.Subtree(path)
+	if !ok {
+		panic(fmt.Sprintf("path %q is not defined in the route tree", path))
+	}
+	return subtree.Mount()
+}
+
+// subtree recursively looks for the route with the given full path,
+// with the main path, middlewares, middleware sources and metadata of its parent.
+func (r *Route) subtree(
+	path string,
+	parentPath string,
+	middlewares []Middleware,
+	sources []string,
+	metadata Metadata,
+) (*Route, bool) {
+	chainedPath := parentPath + r.Path
+	chainedMiddleware := append(append([]Middleware{}, middlewares...), r.Middlewares...)
+	chainedSources := append(append([]string{}, sources...), r.MiddlewareSources()...)
+	chainedMetadata := r.Metadata.inherit(metadata)
+
+	if chainedPath == path {
+		aliases := make([]string, len(r.Aliases))
+		for i, alias := range r.Aliases {
+			aliases[i] = parentPath + alias
+		}
+		if chainedMetadata.Locales != nil {
+			locales := map[string]string{}
+			for p, locale := range chainedMetadata.Locales {
+				locales[parentPath+p] = locale
+			}
+			chainedMetadata.Locales = locales
+		}
+		return &Route{
+			Path:        chainedPath,
+			Aliases:     aliases,
+			Middlewares: chainedMiddleware,
+			Routes:      slices.Clip(r.Routes),
+			Handler:     r.Handler,
+			Method:      r.Method,
+			Metadata:    chainedMetadata,
+			sources:     chainedSources,
+		}, true
+	}
+
+	for _, route := range r.Routes {
+		if subtree, ok := route.subtree(path, chainedPath, chainedMiddleware, chainedSources, chainedMetadata); ok {
+			return subtree, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,76 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestSubtree tests the routes extracted from a tree
+func TestSubtree(t *testing.T) {
+	tracker := []string{}
+	tree := r.NewRoute("/api").Use(middlewareTracker("m1", &tracker)).DeprecatedSince(time.Unix(1688169599, 0)).Add(
+		r.NewRoute("/public").Add(r.Get(handlerWriter("public"))),
+		r.NewRoute("/internal").Alias("/private").Use(middlewareTracker("m2", &tracker)).Add(
+			r.NewRoute("/stats").Add(r.Get(handlerWriter("stats"))),
+		),
+	)
+
+	if _, ok := tree.Subtree("/api/missing"); ok {
+		t.Error("Subtree() found a route for an undefined path")
+	}
+
+	subtree, ok := tree.Subtree("/api/internal")
+	if !ok {
+		t.Fatal("Subtree() did not find the route")
+	}
+	mux := subtree.Mount()
+
+	tests := []struct {
+		name            string
+		path            string
+		expectedStatus  int
+		expectedBody    string
+		expectedTracker []string
+	}{
+		{name: "route of the subtree", path: "/api/internal/stats", expectedStatus: http.StatusOK, expectedBody: "stats", expectedTracker: []string{"m1", "m2"}},
+		{name: "alias of the subtree", path: "/api/private/stats", expectedStatus: http.StatusOK, expectedBody: "stats", expectedTracker: []string{"m1", "m2"}},
+		{name: "route outside the subtree", path: "/api/public", expectedStatus: http.StatusNotFound, expectedBody: "404 page not found\n", expectedTracker: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker = []string{}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, len(tracker), len(tt.expectedTracker))
+			for i := range tracker {
+				assertCorrect(t, tracker[i], tt.expectedTracker[i])
+			}
+			if tt.expectedStatus == http.StatusOK {
+				assertCorrect(t, w.Header().Get("Deprecation"), "@1688169599")
+			}
+		})
+	}
+
+	// The subtree does not change the tree it was extracted from.
+	subtree.Add(r.NewRoute("/extra").Add(r.Get(handlerWriter("extra"))))
+	assertCorrect(t, len(tree.Routes[1].Routes), 1)
+}
+
+// TestMountSubtreeWithUndefinedPath tests that mounting undefined subtrees causes a panic
+func TestMountSubtreeWithUndefinedPath(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MountSubtree() with an undefined path to panic, but it didn't")
+		}
+	}()
+
+	r.NewRoute("/api").MountSubtree("/missing")
+}
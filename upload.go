@@ -0,0 +1,209 @@
+package simplerouter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"sync"
+)
+
+// UploadOption configures the routes returned by [Upload].
+type UploadOption func(*uploadConfig)
+
+// uploadConfig holds the configuration of an upload route.
+type uploadConfig struct {
+	memory  int64
+	tempDir string
+}
+
+// UploadMemory sets how many bytes of the parts saved by [SavePart] are kept in memory for each request,
+// 1 MiB by default. The parts that do not fit are spilled to temporary files.
+func UploadMemory(n int64) UploadOption {
+	if n < 0 {
+		panic("n parameter cannot be negative")
+	}
+	return func(c *uploadConfig) { c.memory = n }
+}
+
+// UploadTempDir sets the directory of the temporary files written by [SavePart], os.TempDir by default.
+func UploadTempDir(dir string) UploadOption {
+	if dir == "" {
+		panic("dir parameter cannot be empty")
+	}
+	return func(c *uploadConfig) { c.tempDir = dir }
+}
+
+// uploadKey is the context key storing the state of the request handled by an [Upload] route.
+type uploadKey struct{}
+
+// uploadState holds the memory left to the parts saved by [SavePart] during a request and their temporary files.
+type uploadState struct {
+	mu        sync.Mutex
+	config    *uploadConfig
+	memory    int64
+	tempFiles []string
+}
+
+// Upload returns a POST Route streaming the multipart bodies of the requests to fn, which reads their
+// parts one at a time without buffering the whole body, so large files can be written to their
+// destination as they arrive:
+//
+//	router.Add(simplerouter.NewRoute("/photos").Add(
+//		simplerouter.Upload(50<<20, func(ctx context.Context, mr *multipart.Reader) error {
+//			for {
+//				part, err := mr.NextPart()
+//				if err == io.EOF {
+//					return nil
+//				}
+//				if err != nil {
+//					return err
+//				}
+//				if err := bucket.Put(ctx, part.FileName(), part); err != nil {
+//					return err
+//				}
+//			}
+//		}),
+//	))
+//
+// Parts needed after reading the next ones can be kept with [SavePart]. The bodies are limited to
+// maxSize bytes as by [Route.MaxBodySize]: reading past it fails, and the request is answered with
+// a 413 Request Entity Too Large. Requests that are not multipart are answered with [JSONError], with
+// a 415 Unsupported Media Type, and the ones without a multipart boundary with a 400 Bad Request.
+// Requests are answered with a 204 No Content once fn returns nil, and its other errors are returned
+// by the handler, so they are handled by the error handler of the route, see [Route.OnError].
+func Upload(maxSize int64, fn func(ctx context.Context, mr *multipart.Reader) error, opts ...UploadOption) *Route {
+	if maxSize <= 0 {
+		panic("maxSize parameter must be greater than zero")
+	}
+	if fn == nil {
+		panic("fn parameter cannot be nil")
+	}
+	config := &uploadConfig{memory: 1 << 20}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	handler := HandlerE(func(w http.ResponseWriter, r *http.Request) error {
+		mr, err := r.MultipartReader()
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, http.ErrNotMultipart) {
+				status = http.StatusUnsupportedMediaType
+			}
+			JSONError(w, r, status)
+			return nil
+		}
+
+		state := &uploadState{config: config, memory: config.memory}
+		defer state.removeTempFiles()
+		err = fn(context.WithValue(r.Context(), uploadKey{}, state), mr)
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			JSONError(w, r, http.StatusRequestEntityTooLarge)
+			return nil
+		case err != nil:
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+	return (&Route{Handler: handler.ServeHTTP, Method: http.MethodPost}).MaxBodySize(maxSize).
+		recordBuild("Upload", func() string { return FuncName(fn) })
+}
+
+// removeTempFiles removes the temporary files written for the parts of the request.
+func (s *uploadState) removeTempFiles() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range s.tempFiles {
+		os.Remove(name)
+	}
+}
+
+// UploadedFile is a part of a multipart body saved by [SavePart].
+type UploadedFile struct {
+	// FormName is the name of the form field of the part.
+	FormName string
+	// FileName is the name of the file of the part, empty if it is not a file.
+	FileName string
+	// Header is the header of the part.
+	Header textproto.MIMEHeader
+	// Size is the size in bytes of the content of the part.
+	Size int64
+
+	data     []byte
+	tempFile string
+}
+
+// SavePart reads the part of a multipart body streamed by an [Upload] route to the end, so it can be read
+// again after the next parts, like a file whose metadata comes after it. Parts are kept in memory up to
+// the memory of the request, see [UploadMemory], and spilled to temporary files once it runs out.
+// Temporary files are removed once the function of the route returns. It panics if ctx is not the
+// context given to the function of an [Upload] route.
+func SavePart(ctx context.Context, part *multipart.Part) (*UploadedFile, error) {
+	state, ok := ctx.Value(uploadKey{}).(*uploadState)
+	if !ok {
+		panic("ctx parameter must be the context of an Upload route")
+	}
+	file := &UploadedFile{FormName: part.FormName(), FileName: part.FileName(), Header: part.Header}
+
+	state.mu.Lock()
+	memory := state.memory
+	state.mu.Unlock()
+	data, err := io.ReadAll(io.LimitReader(part, memory+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) <= memory {
+		state.mu.Lock()
+		state.memory -= int64(len(data))
+		state.mu.Unlock()
+		file.data, file.Size = data, int64(len(data))
+		return file, nil
+	}
+
+	f, err := os.CreateTemp(state.config.tempDir, "upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	state.mu.Lock()
+	state.tempFiles = append(state.tempFiles, f.Name())
+	state.mu.Unlock()
+	size, err := io.Copy(f, io.MultiReader(bytes.NewReader(data), part))
+	if err != nil {
+		return nil, err
+	}
+	file.tempFile, file.Size = f.Name(), size
+	return file, nil
+}
+
+// InMemory reports whether the content of the part is kept in memory rather than in a temporary file.
+func (f *UploadedFile) InMemory() bool {
+	return f.tempFile == ""
+}
+
+// Open opens the content of the part, which can be opened as many times as needed until the function
+// of the [Upload] route returns.
+func (f *UploadedFile) Open() (multipart.File, error) {
+	if f.InMemory() {
+		return memoryFile{bytes.NewReader(f.data)}, nil
+	}
+	return os.Open(f.tempFile)
+}
+
+// memoryFile is a multipart.File reading the content of a part kept in memory.
+type memoryFile struct {
+	*bytes.Reader
+}
+
+// Close does nothing, the content is released with the [UploadedFile].
+func (memoryFile) Close() error {
+	return nil
+}
@@ -0,0 +1,132 @@
+package simplerouter_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// multipartBody returns a multipart body with a file part for each field, named after it, and its content type
+func multipartBody(fields ...[2]string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	for _, field := range fields {
+		w, _ := mw.CreateFormFile(field[0], field[0]+".txt")
+		w.Write([]byte(field[1]))
+	}
+	mw.Close()
+	return body, mw.FormDataContentType()
+}
+
+// TestUpload tests that the parts of multipart bodies are streamed to the upload functions
+func TestUpload(t *testing.T) {
+	var saved []string
+	tempDir := t.TempDir()
+	upload := func(ctx context.Context, mr *multipart.Reader) error {
+		saved = saved[:0]
+		var files []*r.UploadedFile
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			file, err := r.SavePart(ctx, part)
+			if err != nil {
+				return err
+			}
+			files = append(files, file)
+		}
+
+		// Saved parts can be read once all the parts were read.
+		for _, file := range files {
+			f, err := file.Open()
+			if err != nil {
+				return err
+			}
+			content, _ := io.ReadAll(f)
+			f.Close()
+			saved = append(saved, fmt.Sprintf("%s=%s (%d bytes, in memory: %t)", file.FileName, content, file.Size, file.InMemory()))
+		}
+		entries, _ := os.ReadDir(tempDir)
+		if len(entries) > 0 {
+			saved = append(saved, fmt.Sprintf("%d temporary files", len(entries)))
+		}
+		if len(files) == 0 {
+			return errors.New("no files")
+		}
+		return nil
+	}
+	mux := r.NewRoute("/upload").Add(r.Upload(1024, upload, r.UploadMemory(8), r.UploadTempDir(tempDir))).Mount()
+
+	tests := []struct {
+		name           string
+		fields         [][2]string
+		contentType    string
+		expectedStatus int
+		expectedSaved  []string
+	}{
+		{
+			name:           "parts in memory",
+			fields:         [][2]string{{"a", "abc"}, {"b", "defg"}},
+			expectedStatus: http.StatusNoContent,
+			expectedSaved:  []string{"a.txt=abc (3 bytes, in memory: true)", "b.txt=defg (4 bytes, in memory: true)"},
+		},
+		{
+			name:           "parts spilled to temporary files",
+			fields:         [][2]string{{"a", "abcdef"}, {"b", "ghijkl"}},
+			expectedStatus: http.StatusNoContent,
+			expectedSaved:  []string{"a.txt=abcdef (6 bytes, in memory: true)", "b.txt=ghijkl (6 bytes, in memory: false)", "1 temporary files"},
+		},
+		{name: "too large", fields: [][2]string{{"a", strings.Repeat("a", 2048)}}, expectedStatus: http.StatusRequestEntityTooLarge, expectedSaved: []string{}},
+		{name: "not multipart", contentType: "application/json", expectedStatus: http.StatusUnsupportedMediaType, expectedSaved: []string{}},
+		{name: "without boundary", contentType: "multipart/form-data", expectedStatus: http.StatusBadRequest, expectedSaved: []string{}},
+		{name: "upload error", fields: [][2]string{}, expectedStatus: http.StatusInternalServerError, expectedSaved: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved = []string{}
+			body, contentType := multipartBody(tt.fields...)
+			if tt.contentType != "" {
+				contentType = tt.contentType
+			}
+			req := httptest.NewRequest(http.MethodPost, "/upload", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, fmt.Sprint(saved), fmt.Sprint(tt.expectedSaved))
+			entries, _ := os.ReadDir(tempDir)
+			assertCorrect(t, len(entries), 0)
+		})
+	}
+}
+
+// TestSavePartOutsideUpload tests that SavePart panics outside upload routes
+func TestSavePartOutsideUpload(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("SavePart() did not panic")
+		}
+	}()
+	body, contentType := multipartBody([2]string{"a", "abc"})
+	req := httptest.NewRequest(http.MethodPost, "/", body)
+	req.Header.Set("Content-Type", contentType)
+	mr, _ := req.MultipartReader()
+	part, _ := mr.NextPart()
+	r.SavePart(context.Background(), part)
+}
@@ -0,0 +1,175 @@
+package simplerouter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ErrorCode is a machine readable code identifying the kind of problem reported by a [RouteError].
+type ErrorCode string
+
+const (
+	// CodeInvalidPattern reports a route whose method and path do not form a valid http.ServeMux pattern.
+	CodeInvalidPattern ErrorCode = "invalid_pattern"
+	// CodeConflict reports a route whose pattern conflicts with the pattern of another route of the tree.
+	CodeConflict ErrorCode = "conflict"
+	// CodeDuplicateName reports a route whose name is already used by another route of the tree.
+	CodeDuplicateName ErrorCode = "duplicate_name"
+	// CodeUndefinedName reports a route referencing a route name not defined in the tree.
+	CodeUndefinedName ErrorCode = "undefined_name"
+	// CodeBudgetExceeded reports a route whose time budget is greater than the one of its parents.
+	CodeBudgetExceeded ErrorCode = "budget_exceeded"
+)
+
+// RouteError is a problem found in a route of a tree by [Route.Validate].
+type RouteError struct {
+	// Code identifies the kind of problem.
+	Code ErrorCode
+	// Pattern is the full pattern of the route, or its full path for routes without handler.
+	Pattern string
+	// Source is the "file:line" location where the route was created,
+	// empty unless the build log was enabled when creating it, see [EnableBuildLog].
+	Source string
+	// Err describes the problem.
+	Err error
+}
+
+// Error returns the pattern, the code and the description of the problem, followed by the source if known.
+func (e *RouteError) Error() string {
+	msg := fmt.Sprintf("%s: %s: %v", e.Pattern, e.Code, e.Err)
+	if e.Source != "" {
+		msg += " (" + shortSource(e.Source) + ")"
+	}
+	return msg
+}
+
+// Unwrap returns the description of the problem.
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+// validator holds the state shared while validating a route tree.
+type validator struct {
+	router *http.ServeMux
+	// paths maps the route names of the tree to their full paths.
+	paths map[string]string
+	// names maps the route names already seen to the full path of the route using them.
+	names map[string]string
+	// sources maps the patterns already registered to the source of their route.
+	sources map[string]string
+	// patterns lists the patterns already registered, in order.
+	patterns []string
+	// priorities maps the patterns already registered to the priority of their route.
+	priorities map[string]int
+	errs       []error
+}
+
+// Validate checks the route tree without mounting it, reporting every problem that would make
+// [Route.Mount] panic: invalid or conflicting patterns, duplicate route names and canonical
+// references to undefined names, and routes whose time budget is greater than the one of their parents,
+// see [Route.Timeout]. It returns nil if the tree is valid, or an error joining a [*RouteError]
+// for each problem found, so they can be reported at once:
+//
+//	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+//		var routeErr *simplerouter.RouteError
+//		if errors.As(err, &routeErr) {
+//			log.Println(routeErr.Code, routeErr.Pattern)
+//		}
+//	}
+func (r *Route) Validate() error {
+	v := &validator{
+		router:     http.NewServeMux(),
+		paths:      r.namedPaths(""),
+		names:      map[string]string{},
+		sources:    map[string]string{},
+		priorities: map[string]int{},
+	}
+	r.validate([]string{""}, Metadata{}, v)
+	return errors.Join(v.errs...)
+}
+
+// validate recursively checks the route provided and its child routes,
+// with the full paths and the metadata of the parent route as in [Route.inspectRoute].
+func (r *Route) validate(paths []string, metadata Metadata, v *validator) {
+	chainedPaths := r.chainPaths(paths)
+	chainedMetadata := r.Metadata.inherit(metadata)
+	source := r.buildSource()
+	report := func(code ErrorCode, pattern string, err error) {
+		v.errs = append(v.errs, &RouteError{Code: code, Pattern: pattern, Source: source, Err: err})
+	}
+
+	if name := r.Metadata.Name; name != "" {
+		if path, ok := v.names[name]; ok {
+			report(CodeDuplicateName, chainedPaths[0], fmt.Errorf("route name %q is already used by %q", name, path))
+		} else {
+			v.names[name] = chainedPaths[0]
+		}
+	}
+
+	if name := r.Metadata.Canonical; name != "" && !strings.HasPrefix(name, "/") {
+		if _, ok := v.paths[name]; !ok {
+			report(CodeUndefinedName, chainedPaths[0], fmt.Errorf("canonical route name %q is not defined", name))
+		}
+	}
+
+	if t, timeout := r.Metadata.Timeout, metadata.Timeout; t != 0 && timeout != 0 && t > timeout {
+		report(CodeBudgetExceeded, chainedPaths[0], fmt.Errorf("timeout %s exceeds the timeout %s of its parents", t, timeout))
+	}
+
+	if r.Handler != nil {
+		for _, chainedPath := range chainedPaths {
+			pattern := r.Method + " " + chainedPath
+			if code, err := v.register(pattern, source, chainedMetadata.Priority); err != nil {
+				report(code, strings.TrimSpace(pattern), err)
+			}
+		}
+	}
+
+	for _, route := range r.Routes {
+		route.validate(chainedPaths, chainedMetadata, v)
+	}
+}
+
+// register registers the pattern in the router of the validator, turning the panics of http.ServeMux
+// into errors. Conflicts with patterns of a different priority are resolved when mounting,
+// see [Route.Priority], so they are not reported.
+func (v *validator) register(pattern, source string, priority int) (code ErrorCode, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			conflicts := conflictingPatterns(pattern, v.patterns)
+			if len(conflicts) == 0 {
+				code, err = CodeInvalidPattern, errors.New(fmt.Sprint(p))
+				return
+			}
+			i := slices.IndexFunc(conflicts, func(c string) bool { return v.priorities[c] == priority })
+			if i < 0 {
+				return
+			}
+
+			code, err = CodeConflict, fmt.Errorf("conflicts with %q", strings.TrimSpace(conflicts[i]))
+			if other := v.sources[conflicts[i]]; other != "" {
+				err = fmt.Errorf("%w defined at %s", err, shortSource(other))
+			}
+		}
+	}()
+
+	v.router.Handle(pattern, http.NotFoundHandler())
+	v.sources[pattern] = source
+	v.patterns = append(v.patterns, pattern)
+	v.priorities[pattern] = priority
+	return "", nil
+}
+
+// buildSource returns the location where the route was created if recorded, see [EnableBuildLog].
+func (r *Route) buildSource() string {
+	if len(r.buildEvents) == 0 {
+		return ""
+	}
+	if op := r.buildEvents[0].Op; op == "Use" || op == "UsePre" || op == "Add" {
+		return ""
+	}
+	return r.buildEvents[0].Source
+}
@@ -0,0 +1,106 @@
+package simplerouter_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestValidate tests the problems reported for route trees
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name           string
+		route          *r.Route
+		expectedErrors []string
+	}{
+		{
+			name: "valid tree",
+			route: r.NewRoute("/api").Name("api").Add(
+				r.NewRoute("/users").Canonical("api").Add(r.Get(listUsers)),
+				r.NewRoute("/users/{id}").Alias("/people/{id}").Add(r.Get(listUsers), r.Delete(listUsers)),
+			),
+			expectedErrors: nil,
+		},
+		{
+			name: "all the problems of the tree",
+			route: r.NewRoute("/api").Add(
+				r.NewRoute("/users").Name("users").Add(r.Get(listUsers), r.Get(listUsers)),
+				r.NewRoute("/people").Name("users").Add(r.Get(listUsers)),
+				r.NewRoute("/{bad").Add(r.Get(listUsers)),
+				r.NewRoute("/docs").Canonical("missing").Add(r.Get(listUsers)),
+			),
+			expectedErrors: []string{
+				`GET /api/users: conflict: conflicts with "GET /api/users"`,
+				`/api/people: duplicate_name: route name "users" is already used by "/api/users"`,
+				`GET /api/{bad: invalid_pattern: parsing "GET /api/{bad"`,
+				`/api/docs: undefined_name: canonical route name "missing" is not defined`,
+			},
+		},
+		{
+			name: "conflicting aliases",
+			route: r.NewRoute("").Add(
+				r.NewRoute("/users").Alias("/people").Add(r.Get(listUsers)),
+				r.NewRoute("/people").Add(r.Get(listUsers)),
+			),
+			expectedErrors: []string{`GET /people: conflict: conflicts with "GET /people"`},
+		},
+		{
+			name: "child budget exceeding its parent",
+			route: r.NewRoute("/api").Timeout(time.Second).Add(
+				r.NewRoute("/reports").Timeout(time.Minute).Add(r.Get(listUsers)),
+				r.NewRoute("/users").Timeout(time.Millisecond).Add(r.Get(listUsers)),
+			),
+			expectedErrors: []string{`/api/reports: budget_exceeded: timeout 1m0s exceeds the timeout 1s of its parents`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.route.Validate()
+
+			if tt.expectedErrors == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() = nil, want an error")
+			}
+
+			errs := err.(interface{ Unwrap() []error }).Unwrap()
+			if len(errs) != len(tt.expectedErrors) {
+				t.Fatalf("Validate() returned %d errors, want %d: %v", len(errs), len(tt.expectedErrors), err)
+			}
+			for i, expected := range tt.expectedErrors {
+				if !strings.HasPrefix(errs[i].Error(), expected) {
+					t.Errorf("Error %d = %q, want prefix %q", i, errs[i].Error(), expected)
+				}
+			}
+		})
+	}
+}
+
+// TestValidateErrorDetails tests the fields of the errors reported for route trees
+func TestValidateErrorDetails(t *testing.T) {
+	r.EnableBuildLog(true)
+	defer r.EnableBuildLog(false)
+
+	route := r.NewRoute("/api").Add(r.Get(listUsers))
+	route.Add(r.Get(listUsers))
+	line := previousLine()
+
+	var routeErr *r.RouteError
+	if !errors.As(route.Validate(), &routeErr) {
+		t.Fatal("Validate() did not return a RouteError")
+	}
+
+	assertCorrect(t, routeErr.Code, r.CodeConflict)
+	assertCorrect(t, routeErr.Pattern, "GET /api")
+	if !strings.HasSuffix(routeErr.Source, line) {
+		t.Errorf("Source = %q, want suffix %q", routeErr.Source, line)
+	}
+}
@@ -0,0 +1,119 @@
+package simplerouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// FieldError is the validation error of a struct field.
+type FieldError struct {
+	// Field is the name of the field, empty if the error is not about a single field.
+	Field string `json:"field,omitempty"`
+	// Message describes why the field is not valid.
+	Message string `json:"message"`
+}
+
+// ValidationError is the error returned by [Bind] when the bound struct is not valid, see [Route.Validator].
+type ValidationError struct {
+	// Fields are the errors of the fields that are not valid.
+	Fields []FieldError
+	// Err is the error returned by the validator.
+	Err error
+}
+
+// Error returns the errors of the fields separated by semicolons.
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the error returned by the validator.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validator sets the function validating the structs bound by [Bind], [BindMiddleware] and [JSON] for the
+// requests of the route and its child routes, replacing the one of their parents. Requests binding structs
+// that are not valid are answered with a 422 Unprocessable Entity listing the errors of their fields.
+// The validator of go-playground/validator can be used as is, its errors are split by field:
+//
+//	validate := validator.New()
+//	router.Validator(validate.Struct)
+//
+// Other validators can return a [ValidationError], or any other error, reported without field.
+func (r *Route) Validator(validate func(v any) error) *Route {
+	if validate == nil {
+		panic("validate parameter cannot be nil")
+	}
+	r.Metadata.Validator = validate
+	return r
+}
+
+// validatorKey is the context key storing the validator of the route serving the request.
+type validatorKey struct{}
+
+// withValidator returns a middleware storing the validator in the request context for [Bind].
+func withValidator(validate func(any) error) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), validatorKey{}, validate)))
+		})
+	}
+}
+
+// validateBound validates v with the validator of the route serving the request, if any,
+// returning a [ValidationError] if it is not valid.
+func validateBound(r *http.Request, v any) error {
+	validate, ok := r.Context().Value(validatorKey{}).(func(any) error)
+	if !ok {
+		return nil
+	}
+	err := validate(v)
+	if err == nil {
+		return nil
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr
+	}
+	return &ValidationError{Fields: fieldErrors(err), Err: err}
+}
+
+// fieldError is implemented by the field errors of validation libraries, like go-playground/validator.
+type fieldError interface {
+	Field() string
+	Error() string
+}
+
+// fieldErrors splits the error of a validator into the errors of the fields. Errors that are slices
+// of field errors, like the ones of go-playground/validator, are split by field.
+func fieldErrors(err error) []FieldError {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Slice && v.Len() > 0 && v.Type().Elem().Implements(reflect.TypeFor[fieldError]()) {
+		fields := make([]FieldError, v.Len())
+		for i := range v.Len() {
+			fe := v.Index(i).Interface().(fieldError)
+			fields[i] = FieldError{Field: fe.Field(), Message: fe.Error()}
+		}
+		return fields
+	}
+	return []FieldError{{Message: err.Error()}}
+}
+
+// writeValidationError answers the request with a 422 Unprocessable Entity listing the errors of the fields.
+func writeValidationError(w http.ResponseWriter, err *ValidationError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error":  http.StatusText(http.StatusUnprocessableEntity),
+		"fields": err.Fields,
+	})
+}
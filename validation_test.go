@@ -0,0 +1,107 @@
+package simplerouter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// playgroundFieldError mimics the field errors of go-playground/validator
+type playgroundFieldError struct {
+	field, tag string
+}
+
+func (e playgroundFieldError) Field() string { return e.field }
+func (e playgroundFieldError) Error() string { return e.field + " failed on the " + e.tag + " tag" }
+
+// playgroundErrors mimics the validation errors of go-playground/validator
+type playgroundErrors []playgroundFieldError
+
+func (e playgroundErrors) Error() string { return "validation failed" }
+
+type signup struct {
+	Name  string `json:"name" query:"name"`
+	Email string `json:"email" query:"email"`
+}
+
+// validateSignup is a validator returning per field errors
+func validateSignup(v any) error {
+	s, ok := v.(*signup)
+	if !ok {
+		return nil
+	}
+	errs := playgroundErrors{}
+	if s.Name == "" {
+		errs = append(errs, playgroundFieldError{field: "Name", tag: "required"})
+	}
+	if !strings.Contains(s.Email, "@") {
+		errs = append(errs, playgroundFieldError{field: "Email", tag: "email"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// TestValidator tests that bound structs are validated with the validator of the route
+func TestValidator(t *testing.T) {
+	const invalid = `{"error":"Unprocessable Entity","fields":[{"field":"Name","message":"Name failed on the required tag"},` +
+		`{"field":"Email","message":"Email failed on the email tag"}]}` + "\n"
+
+	mux := r.NewRoute("").Validator(validateSignup).Add(
+		r.NewRoute("/middleware").Use(r.BindMiddleware[signup]()).Add(r.Get(handlerWriter("bound"))),
+		r.NewRoute("/json").Add(r.JSON(http.MethodPost, func(ctx context.Context, s signup) (signup, error) { return s, nil })),
+		r.NewRoute("/handler").Add(r.GetE(func(w http.ResponseWriter, req *http.Request) error {
+			var s signup
+			if err := r.Bind(req, &s); err != nil {
+				return err
+			}
+			w.Write([]byte("bound"))
+			return nil
+		})),
+		r.NewRoute("/custom").Validator(func(v any) error {
+			return &r.ValidationError{Fields: []r.FieldError{{Field: "Name", Message: "taken"}}}
+		}).Add(r.GetE(func(w http.ResponseWriter, req *http.Request) error {
+			return r.Bind(req, &signup{})
+		})),
+		r.NewRoute("/other").Validator(func(v any) error { return errors.New("rejected") }).Add(
+			r.Get(func(w http.ResponseWriter, req *http.Request) {
+				var verr *r.ValidationError
+				errors.As(r.Bind(req, &signup{}), &verr)
+				w.Write([]byte(verr.Error()))
+			}),
+		),
+	).Mount()
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "valid bound struct", method: http.MethodGet, target: "/middleware?name=ada&email=ada@example.com", expectedStatus: http.StatusOK, expectedBody: "bound"},
+		{name: "invalid bound struct", method: http.MethodGet, target: "/middleware", expectedStatus: http.StatusUnprocessableEntity, expectedBody: invalid},
+		{name: "invalid JSON request", method: http.MethodPost, target: "/json", body: `{}`, expectedStatus: http.StatusUnprocessableEntity, expectedBody: invalid},
+		{name: "valid JSON request", method: http.MethodPost, target: "/json", body: `{"name":"ada","email":"ada@example.com"}`, expectedStatus: http.StatusCreated, expectedBody: `{"name":"ada","email":"ada@example.com"}` + "\n"},
+		{name: "error returned by handler", method: http.MethodGet, target: "/handler?name=ada", expectedStatus: http.StatusUnprocessableEntity, expectedBody: `{"error":"Unprocessable Entity","fields":[{"field":"Email","message":"Email failed on the email tag"}]}` + "\n"},
+		{name: "validation error", method: http.MethodGet, target: "/custom", expectedStatus: http.StatusUnprocessableEntity, expectedBody: `{"error":"Unprocessable Entity","fields":[{"field":"Name","message":"taken"}]}` + "\n"},
+		{name: "plain error", method: http.MethodGet, target: "/other", expectedStatus: http.StatusOK, expectedBody: "validation failed: rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			assertCorrect(t, w.Code, tt.expectedStatus)
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package simplerouter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// WithValue adds a middleware storing val under key in the context of the requests of the route and its
+// child routes, to inject dependencies like database handles or tenant configurations into the handlers
+// of a subtree instead of reading them from global variables:
+//
+//	var dbKey = simplerouter.NewContextKey[*sql.DB]("db")
+//
+//	router.Add(simplerouter.NewRoute("/users").WithValue(dbKey, db).Add(simplerouter.Get(listUsers)))
+//
+//	func listUsers(w http.ResponseWriter, r *http.Request) {
+//		db := dbKey.MustValue(r.Context())
+//		...
+//	}
+//
+// As any middleware, it runs in the order it was added, so the middlewares added before it to the same
+// route do not see the value. Keys follow the rules of context.WithValue; [ContextKey] gives typed keys.
+// It panics if key is nil or the route is frozen, see [WithFreeze].
+func (r *Route) WithValue(key, val any) *Route {
+	if key == nil {
+		panic("key parameter cannot be nil")
+	}
+	return r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), key, val)))
+		})
+	})
+}
+
+// ContextKey is a typed key of the values stored in the request context with [Route.WithValue],
+// whose accessors return them with their type. Keys are compared by identity, so two keys created
+// with the same name do not collide.
+type ContextKey[T any] struct {
+	name string
+}
+
+// NewContextKey returns a new key for values of type T, named for debugging.
+func NewContextKey[T any](name string) *ContextKey[T] {
+	return &ContextKey[T]{name: name}
+}
+
+// String returns the name of the key.
+func (k *ContextKey[T]) String() string {
+	return k.name
+}
+
+// Value returns the value stored under the key in ctx, and whether there is one.
+func (k *ContextKey[T]) Value(ctx context.Context) (T, bool) {
+	val, ok := ctx.Value(k).(T)
+	return val, ok
+}
+
+// MustValue returns the value stored under the key in ctx.
+// It panics if there is none, which means that the route serving the request was not given the value.
+func (k *ContextKey[T]) MustValue(ctx context.Context) T {
+	val, ok := k.Value(ctx)
+	if !ok {
+		panic(fmt.Sprintf("context has no value for key %q", k.name))
+	}
+	return val
+}
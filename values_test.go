@@ -0,0 +1,77 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// tenantKey is the key of the tenant of the test routes
+var tenantKey = r.NewContextKey[string]("tenant")
+
+// writeTenant writes the tenant stored in the request context
+func writeTenant(w http.ResponseWriter, req *http.Request) {
+	tenant, ok := tenantKey.Value(req.Context())
+	if !ok {
+		tenant = "none"
+	}
+	w.Write([]byte(tenant))
+}
+
+// TestWithValue tests that the values are injected into the requests of the subtree, overridden by child routes
+func TestWithValue(t *testing.T) {
+	seen := ""
+	mux := r.NewRoute("").Add(
+		r.NewRoute("/acme").WithValue(tenantKey, "acme").Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				seen = tenantKey.MustValue(req.Context())
+				next.ServeHTTP(w, req)
+			})
+		}).Add(
+			r.NewRoute("/users").Add(r.Get(writeTenant)),
+			r.NewRoute("/beta").WithValue(tenantKey, "acme-beta").Add(r.Get(writeTenant)),
+		),
+		r.NewRoute("/public").Add(r.Get(writeTenant)),
+	).Mount()
+
+	tests := []struct {
+		path         string
+		expectedBody string
+		expectedSeen string
+	}{
+		{path: "/acme/users", expectedBody: "acme", expectedSeen: "acme"},
+		{path: "/acme/beta", expectedBody: "acme-beta", expectedSeen: "acme"},
+		{path: "/public", expectedBody: "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			seen = ""
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, seen, tt.expectedSeen)
+		})
+	}
+}
+
+// TestContextKeyMustValue tests that reading a missing value panics, and that keys with the same name do not collide
+func TestContextKeyMustValue(t *testing.T) {
+	other := r.NewContextKey[string]("tenant")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.NewRoute("/").WithValue(other, "other").Add(r.Get(func(w http.ResponseWriter, req *http.Request) {
+		_, ok := tenantKey.Value(req.Context())
+		assertCorrect(t, ok, false)
+		assertCorrect(t, other.MustValue(req.Context()), "other")
+	})).Mount().ServeHTTP(httptest.NewRecorder(), req)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustValue() did not panic")
+		}
+	}()
+	tenantKey.MustValue(req.Context())
+}
@@ -0,0 +1,50 @@
+package simplerouter
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Version returns a Route grouping the routes of version v of an API under the "/" + v path,
+// recording the version in the metadata of its routes, as reported by [Endpoint]:
+//
+//	router.Add(
+//		simplerouter.Version("v1").Deprecated().Sunset(sunset).Add(usersV1),
+//		simplerouter.Version("v2").Add(usersV2),
+//	)
+//
+// Versions marked with [Route.Deprecated] answer with a Deprecation header, and with a Sunset
+// header announcing when they will be removed if [Route.Sunset] is set.
+// It panics if v is empty or contains a slash.
+func Version(v string) *Route {
+	if v == "" || strings.Contains(v, "/") {
+		panic("v parameter must be a non-empty path segment")
+	}
+	route := NewRoute("/" + v)
+	route.Metadata.Version = v
+	return route
+}
+
+// Sunset sets the date after which the route and its child routes are expected to be removed,
+// usually along with [Route.Deprecated]. Once mounted, their responses carry a Sunset header
+// (RFC 8594) with the date, so clients can plan their migration.
+// Child routes can set their own date, which replaces the one of their parents.
+func (r *Route) Sunset(t time.Time) *Route {
+	if t.IsZero() {
+		panic("t parameter cannot be the zero time")
+	}
+	r.Metadata.Sunset = t
+	return r
+}
+
+// sunset returns a Middleware that sets the Sunset header to t before calling the next handler.
+func sunset(t time.Time) Middleware {
+	value := t.UTC().Format(http.TimeFormat)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Sunset", value)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
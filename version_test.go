@@ -0,0 +1,64 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestVersion tests the paths, metadata and lifecycle headers of versioned routes
+func TestVersion(t *testing.T) {
+	sunset := time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+	route := r.NewRoute("").Add(
+		r.Version("v1").DeprecatedSince(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)).Sunset(sunset).Add(r.NewRoute("/users").Add(r.Get(handlerWriter("users v1")))),
+		r.Version("v2").Add(r.NewRoute("/users").Add(r.Get(handlerWriter("users v2")))),
+	)
+	mux := route.Mount()
+
+	tests := []struct {
+		path                string
+		expectedBody        string
+		expectedVersion     string
+		expectedDeprecation string
+		expectedSunset      string
+	}{
+		{
+			path:                "/v1/users",
+			expectedBody:        "users v1",
+			expectedVersion:     "v1",
+			expectedDeprecation: "@1767225600",
+			expectedSunset:      "Fri, 01 Jan 2027 00:00:00 GMT",
+		},
+		{path: "/v2/users", expectedBody: "users v2", expectedVersion: "v2"},
+	}
+
+	endpoints := route.Endpoints()
+	for i, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			assertCorrect(t, w.Header().Get("Deprecation"), tt.expectedDeprecation)
+			assertCorrect(t, w.Header().Get("Sunset"), tt.expectedSunset)
+			assertCorrect(t, endpoints[i].Metadata.Version, tt.expectedVersion)
+		})
+	}
+}
+
+// TestVersionWithInvalidVersion tests that invalid versions cause a panic
+func TestVersionWithInvalidVersion(t *testing.T) {
+	for _, v := range []string{"", "/v1"} {
+		t.Run(v, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Version(%q) did not panic", v)
+				}
+			}()
+			r.Version(v)
+		})
+	}
+}
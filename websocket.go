@@ -0,0 +1,315 @@
+package simplerouter
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// MessageType is the type of a WebSocket message, see [Conn.ReadMessage].
+type MessageType int
+
+const (
+	// TextMessage is a message holding UTF-8 encoded text.
+	TextMessage MessageType = 1
+	// BinaryMessage is a message holding binary data.
+	BinaryMessage MessageType = 2
+)
+
+// WebSocket opcodes of the control frames, as defined by RFC 6455.
+const (
+	opContinuation = 0
+	opClose        = 8
+	opPing         = 9
+	opPong         = 10
+)
+
+// WebSocket close status codes, as defined by RFC 6455.
+const (
+	closeNormal          = 1000
+	closeProtocolError   = 1002
+	closeInvalidPayload  = 1007
+	closeMessageTooLarge = 1009
+)
+
+// webSocketGUID is appended to the key of the handshake to compute the accept value, as defined by RFC 6455.
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Conn is a WebSocket connection upgraded by a [WebSocket] route. Messages can be read by one goroutine
+// while others write them.
+type Conn struct {
+	conn      net.Conn
+	rw        *bufio.ReadWriter
+	req       *http.Request
+	readLimit int64
+	// mu serializes the writes of the messages and of the control frames answering the client.
+	mu     sync.Mutex
+	closed bool
+}
+
+// WebSocket returns a Route upgrading the GET requests to WebSocket connections, handled by handler
+// with the context of the request. The upgrade happens once the middlewares of the route let the
+// request through, so they can authenticate it as any other. The connection is closed when
+// handler returns:
+//
+//	router.Add(simplerouter.NewRoute("/chat").Use(requireUser).Add(
+//		simplerouter.WebSocket(func(ctx context.Context, conn *simplerouter.Conn) {
+//			for {
+//				typ, msg, err := conn.ReadMessage()
+//				if err != nil {
+//					return
+//				}
+//				conn.WriteMessage(typ, msg)
+//			}
+//		}),
+//	))
+//
+// Requests that are not valid WebSocket handshakes are answered with a 400 Bad Request, and the ones
+// sent by browsers from other origins with a 403 Forbidden, as browsers send the cookies of the site
+// with them; middlewares accepting other origins can remove the Origin header of the request.
+func WebSocket(handler func(ctx context.Context, conn *Conn)) *Route {
+	if handler == nil {
+		panic("handler parameter cannot be nil")
+	}
+
+	return (&Route{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := upgrade(w, r)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			handler(r.Context(), conn)
+		}),
+		Method: http.MethodGet,
+	}).recordBuild("WebSocket", func() string { return FuncName(handler) })
+}
+
+// upgrade answers the WebSocket handshake of the request and takes over its connection.
+// If the handshake is not valid, it answers the request with an error and returns it.
+func upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") || key == "" {
+		http.Error(w, "websocket: not a websocket handshake", http.StatusBadRequest)
+		return nil, errors.New("websocket: not a websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "websocket: unsupported version", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: unsupported version")
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || !strings.EqualFold(u.Host, r.Host) {
+			http.Error(w, "websocket: origin not allowed", http.StatusForbidden)
+			return nil, errors.New("websocket: origin not allowed")
+		}
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "websocket: connection cannot be upgraded", http.StatusInternalServerError)
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	fmt.Fprint(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	fmt.Fprintf(rw, "Sec-WebSocket-Accept: %s\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+	// Headers set by the middlewares, like request IDs, are kept.
+	w.Header().Write(rw)
+	fmt.Fprint(rw, "\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Conn{conn: conn, rw: rw, req: r, readLimit: 1 << 20}, nil
+}
+
+// headerContains reports whether the comma separated values of the header contain token, ignoring case.
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for v := range strings.SplitSeq(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Request returns the upgraded request, to read its path values or headers.
+func (c *Conn) Request() *http.Request {
+	return c.req
+}
+
+// SetReadLimit sets the maximum size in bytes of the messages read, 1 MiB by default.
+// Reading a larger message closes the connection.
+func (c *Conn) SetReadLimit(n int64) {
+	c.readLimit = n
+}
+
+// ReadMessage reads the next text or binary message of the client, answering its ping and close frames
+// in the meantime. It returns io.EOF once the client closes the connection.
+func (c *Conn) ReadMessage() (MessageType, []byte, error) {
+	var (
+		typ     MessageType
+		message []byte
+	)
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			code := closeNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			c.closeWith(code)
+			return 0, nil, io.EOF
+		case opContinuation:
+			if typ == 0 {
+				return 0, nil, c.fail(closeProtocolError, "unexpected continuation frame")
+			}
+		case int(TextMessage), int(BinaryMessage):
+			if typ != 0 {
+				return 0, nil, c.fail(closeProtocolError, "unfinished fragmented message")
+			}
+			typ = MessageType(opcode)
+		default:
+			return 0, nil, c.fail(closeProtocolError, fmt.Sprintf("unknown opcode %d", opcode))
+		}
+
+		if int64(len(message)+len(payload)) > c.readLimit {
+			return 0, nil, c.fail(closeMessageTooLarge, "message too large")
+		}
+		message = append(message, payload...)
+		if fin {
+			if typ == TextMessage && !utf8.Valid(message) {
+				return 0, nil, c.fail(closeInvalidPayload, "invalid UTF-8 text message")
+			}
+			return typ, message, nil
+		}
+	}
+}
+
+// readFrame reads a frame of the client, unmasking its payload.
+func (c *Conn) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.rw, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, opcode = header[0]&0x80 != 0, int(header[0]&0x0f)
+	if header[0]&0x70 != 0 || header[1]&0x80 == 0 {
+		return false, 0, nil, c.fail(closeProtocolError, "reserved bits set or unmasked frame")
+	}
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.rw, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]) & (1<<63 - 1))
+	}
+	if opcode >= opClose && (length > 125 || !fin) {
+		return false, 0, nil, c.fail(closeProtocolError, "invalid control frame")
+	}
+	if length > c.readLimit {
+		return false, 0, nil, c.fail(closeMessageTooLarge, "message too large")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.rw, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.rw, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage writes a text or binary message to the client.
+func (c *Conn) WriteMessage(typ MessageType, data []byte) error {
+	if typ != TextMessage && typ != BinaryMessage {
+		return fmt.Errorf("websocket: invalid message type %d", typ)
+	}
+	return c.writeFrame(int(typ), data)
+}
+
+// writeFrame writes an unfragmented frame to the client.
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+
+	header := []byte{0x80 | byte(opcode)}
+	switch length := len(payload); {
+	case length <= 125:
+		header = append(header, byte(length))
+	case length <= 0xffff:
+		header = binary.BigEndian.AppendUint16(append(header, 126), uint16(length))
+	default:
+		header = binary.BigEndian.AppendUint64(append(header, 127), uint64(length))
+	}
+	c.rw.Write(header)
+	c.rw.Write(payload)
+	return c.rw.Flush()
+}
+
+// fail closes the connection after a protocol violation of the client, returning the error describing it.
+func (c *Conn) fail(code int, reason string) error {
+	c.closeWith(code)
+	return errors.New("websocket: " + reason)
+}
+
+// Close sends a close frame to the client and closes the connection.
+func (c *Conn) Close() error {
+	return c.closeWith(closeNormal)
+}
+
+// closeWith sends a close frame with the status code to the client and closes the connection.
+func (c *Conn) closeWith(code int) error {
+	c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, uint16(code)))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
@@ -0,0 +1,184 @@
+package simplerouter_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// dialWebSocket sends a WebSocket handshake for path to the server, returning the connection,
+// its reader and the response to the handshake
+func dialWebSocket(t *testing.T, server *httptest.Server, path string, header http.Header) (net.Conn, *bufio.Reader, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Write(conn)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, reader, resp
+}
+
+// writeClientFrame writes a masked frame, as clients do
+func writeClientFrame(conn net.Conn, fin bool, opcode byte, payload []byte) {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame := append([]byte{first, 0x80 | byte(len(payload))}, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	conn.Write(frame)
+}
+
+// readServerFrame reads an unmasked frame of up to 125 bytes, as servers send them
+func readServerFrame(t *testing.T, reader *bufio.Reader) (opcode byte, payload []byte) {
+	t.Helper()
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		t.Fatal(err)
+	}
+	payload = make([]byte, header[1]&0x7f)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatal(err)
+	}
+	return header[0] & 0x0f, payload
+}
+
+// requireToken is a middleware answering the requests without a bearer token with a 401 Unauthorized
+func requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// echoServer returns a server echoing the WebSocket messages sent to /ws/{room}, prefixed by the room,
+// behind a middleware requiring a token
+func echoServer() *httptest.Server {
+	return httptest.NewServer(r.NewRoute("/ws/{room}").Use(requireToken).Add(
+		r.WebSocket(func(ctx context.Context, conn *r.Conn) {
+			for {
+				typ, msg, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				conn.WriteMessage(typ, append([]byte(conn.Request().PathValue("room")+":"), msg...))
+			}
+		}),
+	).Mount())
+}
+
+// TestWebSocket tests that messages are exchanged over the upgraded connections
+func TestWebSocket(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+	conn, reader, resp := dialWebSocket(t, server, "/ws/lobby", http.Header{"Authorization": {"Bearer token"}})
+
+	assertCorrect(t, resp.StatusCode, http.StatusSwitchingProtocols)
+	assertCorrect(t, resp.Header.Get("Sec-WebSocket-Accept"), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+
+	writeClientFrame(conn, true, 1, []byte("hello"))
+	opcode, payload := readServerFrame(t, reader)
+	assertCorrect(t, opcode, byte(1))
+	assertCorrect(t, string(payload), "lobby:hello")
+
+	// Fragmented messages are joined, and pings are answered in the meantime.
+	writeClientFrame(conn, false, 2, []byte("frag"))
+	writeClientFrame(conn, true, 9, []byte("ping"))
+	writeClientFrame(conn, true, 0, []byte("ment"))
+	opcode, payload = readServerFrame(t, reader)
+	assertCorrect(t, opcode, byte(10))
+	assertCorrect(t, string(payload), "ping")
+	opcode, payload = readServerFrame(t, reader)
+	assertCorrect(t, opcode, byte(2))
+	assertCorrect(t, string(payload), "lobby:fragment")
+
+	writeClientFrame(conn, true, 8, binary.BigEndian.AppendUint16(nil, 1000))
+	opcode, payload = readServerFrame(t, reader)
+	assertCorrect(t, opcode, byte(8))
+	assertCorrect(t, binary.BigEndian.Uint16(payload), uint16(1000))
+}
+
+// TestWebSocketWithInvalidFrames tests that protocol violations close the connection with their status code
+func TestWebSocketWithInvalidFrames(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		write        func(conn net.Conn)
+		expectedCode uint16
+	}{
+		{name: "unmasked frame", write: func(conn net.Conn) { conn.Write([]byte{0x81, 0x01, 'a'}) }, expectedCode: 1002},
+		{name: "continuation without message", write: func(conn net.Conn) { writeClientFrame(conn, true, 0, []byte("a")) }, expectedCode: 1002},
+		{name: "invalid UTF-8 text", write: func(conn net.Conn) { writeClientFrame(conn, true, 1, []byte{0xff}) }, expectedCode: 1007},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, reader, _ := dialWebSocket(t, server, "/ws/lobby", http.Header{"Authorization": {"Bearer token"}})
+			tt.write(conn)
+			opcode, payload := readServerFrame(t, reader)
+			assertCorrect(t, opcode, byte(8))
+			assertCorrect(t, binary.BigEndian.Uint16(payload), tt.expectedCode)
+		})
+	}
+}
+
+// TestWebSocketWithInvalidHandshakes tests that requests that cannot be upgraded are answered with an error
+func TestWebSocketWithInvalidHandshakes(t *testing.T) {
+	server := echoServer()
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		header         http.Header
+		expectedStatus int
+	}{
+		{name: "unauthorized", header: http.Header{}, expectedStatus: http.StatusUnauthorized},
+		{name: "other origin", header: http.Header{"Authorization": {"Bearer token"}, "Origin": {"https://evil.example"}}, expectedStatus: http.StatusForbidden},
+		{name: "unsupported version", header: http.Header{"Authorization": {"Bearer token"}, "Sec-Websocket-Version": {"8"}}, expectedStatus: http.StatusUpgradeRequired},
+		{name: "not an upgrade", header: http.Header{"Authorization": {"Bearer token"}, "Upgrade": {"h2c"}}, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, resp := dialWebSocket(t, server, "/ws/lobby", tt.header)
+			assertCorrect(t, resp.StatusCode, tt.expectedStatus)
+		})
+	}
+
+	_, _, resp := dialWebSocket(t, server, "/ws/lobby", http.Header{
+		"Authorization": {"Bearer token"},
+		"Origin":        {server.URL},
+	})
+	assertCorrect(t, resp.StatusCode, http.StatusSwitchingProtocols)
+}
@@ -0,0 +1,33 @@
+package simplerouter
+
+import (
+	"maps"
+	"slices"
+)
+
+// With returns a variant of the route carrying extra middlewares, for one-off decorated routes that do not
+// change the shared route: the variant has the path, aliases, middlewares and metadata of the route, but
+// neither its child routes nor its handler, so it is added next to the route to register more routes
+// under the same path with the extra middlewares:
+//
+//	api.Add(users, users.With(cache).Add(simplerouter.Get(listUsers)))
+//
+// The variant does not keep the name of the route, which identifies a single route of the tree.
+// It panics if the middlewares contain a nil middleware.
+func (r *Route) With(middlewares ...Middleware) *Route {
+	variant := &Route{
+		Path:        r.Path,
+		Aliases:     slices.Clone(r.Aliases),
+		Middlewares: slices.Clone(r.Middlewares),
+		Routes:      []*Route{},
+		Metadata:    r.Metadata,
+		sources:     slices.Clone(r.sources),
+	}
+	variant.Metadata.Name = ""
+	variant.Metadata.Tags = slices.Clone(r.Metadata.Tags)
+	variant.Metadata.Assets = slices.Clone(r.Metadata.Assets)
+	variant.Metadata.Values = maps.Clone(r.Metadata.Values)
+	variant.Metadata.Locales = maps.Clone(r.Metadata.Locales)
+	variant.recordBuild("With", func() string { return "" })
+	return variant.Use(middlewares...)
+}
@@ -0,0 +1,50 @@
+package simplerouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	r "github.com/carlos-el/simplerouter"
+)
+
+// TestWith tests that route variants carry the extra middlewares without changing the route
+func TestWith(t *testing.T) {
+	tracker := []string{}
+	users := r.NewRoute("/users").Name("users").Use(middlewareTracker("auth", &tracker)).Add(
+		r.NewRoute("/{id}").Add(r.Get(handlerWriter("user"))),
+	)
+	api := r.NewRoute("/api").Add(
+		users,
+		users.With(middlewareTracker("cache", &tracker)).Add(r.Get(handlerWriter("users"))),
+	)
+	if err := api.Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+	mux := api.Mount()
+
+	tests := []struct {
+		path             string
+		expectedBody     string
+		expectedTracking []string
+	}{
+		{path: "/api/users", expectedBody: "users", expectedTracking: []string{"auth", "cache"}},
+		{path: "/api/users/1", expectedBody: "user", expectedTracking: []string{"auth"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			tracker = tracker[:0]
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assertCorrect(t, w.Body.String(), tt.expectedBody)
+			if !reflect.DeepEqual(tracker, tt.expectedTracking) {
+				t.Errorf("Middlewares executed = %v, want %v", tracker, tt.expectedTracking)
+			}
+		})
+	}
+	assertCorrect(t, len(users.Middlewares), 1)
+	assertCorrect(t, len(users.Routes), 1)
+}